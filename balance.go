@@ -0,0 +1,94 @@
+package easysms
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/anhao/go-easy-sms/gateway"
+)
+
+// BalanceResult 表示查询网关余额的结果
+type BalanceResult struct {
+	Gateway string
+	Balance *gateway.Balance
+	Error   error
+}
+
+// Balances 并发查询所有已配置或已注册且支持余额查询（gateway.BalanceQuerier）的网关余额
+// 不支持余额查询的网关不在结果中，存在查询失败的网关时返回错误
+func (e *EasySms) Balances(ctx context.Context) (map[string]BalanceResult, error) {
+	return e.queryBalances(ctx, e.gatewayNames())
+}
+
+// queryBalances 并发查询指定网关的余额
+func (e *EasySms) queryBalances(ctx context.Context, names []string) (map[string]BalanceResult, error) {
+	results := make(map[string]BalanceResult)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, name := range names {
+		gw, err := e.Gateway(name)
+		if err != nil {
+			results[name] = BalanceResult{Gateway: name, Error: err}
+			continue
+		}
+
+		querier, ok := gw.(gateway.BalanceQuerier)
+		if !ok {
+			continue
+		}
+
+		wg.Add(1)
+		go func(name string, querier gateway.BalanceQuerier) {
+			defer wg.Done()
+
+			balance, err := querier.QueryBalance(ctx)
+			if err != nil {
+				e.logger.Error("Failed to query balance of gateway %s: %v", name, err)
+				err = gateway.WrapError(name, err)
+			} else {
+				balance.Gateway = name
+			}
+
+			mu.Lock()
+			results[name] = BalanceResult{Gateway: name, Balance: balance, Error: err}
+			mu.Unlock()
+		}(name, querier)
+	}
+
+	wg.Wait()
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("%d of %d balance queries failed", failed, len(results))
+	}
+
+	return results, nil
+}
+
+// gatewayNames 返回已配置和已注册的网关名称，按名称排序
+func (e *EasySms) gatewayNames() []string {
+	e.mu.RLock()
+	names := make(map[string]struct{}, len(e.gateways)+len(e.config.GatewayConfigs))
+	for name := range e.gateways {
+		names[name] = struct{}{}
+	}
+	for name := range e.config.GatewayConfigs {
+		names[name] = struct{}{}
+	}
+	e.mu.RUnlock()
+
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
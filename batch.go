@@ -0,0 +1,281 @@
+package easysms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/anhao/go-easy-sms/gateway"
+	"github.com/anhao/go-easy-sms/message"
+	"github.com/anhao/go-easy-sms/strategy"
+)
+
+// DefaultBatchConcurrency 网关不支持批量接口时逐个发送的默认并发数
+const DefaultBatchConcurrency = 10
+
+// RecipientResult 表示批量发送中单个号码的结果
+type RecipientResult struct {
+	// 接收号码
+	To *message.PhoneNumber
+
+	// 各网关的发送结果
+	Results map[string]Result
+
+	// 最终失败的原因，发送成功时为 nil
+	Error error
+}
+
+// Success 判断该号码是否发送成功
+func (r *RecipientResult) Success() bool {
+	return r.Error == nil
+}
+
+// SendBatch 向多个号码发送同一条短信，按 to 的顺序返回每个号码的结果
+// 网关支持批量接口（gateway.BatchGateway）时按其单次上限分批调用，否则使用有限并发逐个发送；
+// 某个网关失败的号码按回退规则交由后续网关继续发送。存在发送失败的号码时返回错误
+func (e *EasySms) SendBatch(ctx context.Context, to []*message.PhoneNumber, msg *message.Message) ([]RecipientResult, error) {
+	if len(to) == 0 {
+		return nil, errors.New("no recipient")
+	}
+
+	// 配置更新后，本次发送仍然使用开始时的配置、策略和网关
+	st := e.snapshot()
+
+	// 如果消息中没有指定网关，使用默认网关
+	gateways := msg.GetGateways()
+	if len(gateways) == 0 {
+		gateways = st.config.DefaultGateways
+	}
+
+	if len(gateways) == 0 {
+		return nil, errors.New("no gateway available")
+	}
+
+	results := make([]RecipientResult, len(to))
+	for i, phone := range to {
+		results[i] = RecipientResult{
+			To:      phone,
+			Results: make(map[string]Result),
+		}
+	}
+
+	routes, groups := routeBatch(st.strategy, gateways, to, msg)
+	if len(routes) == 1 && len(routes[0]) == 0 {
+		return nil, errors.New("no gateway available")
+	}
+
+	for k, orderedGateways := range routes {
+		pending := groups[k]
+		if len(orderedGateways) == 0 {
+			for _, i := range pending {
+				results[i].Error = errors.New("no gateway available")
+			}
+			continue
+		}
+
+		e.logger.Info("Sending message to %d recipients using gateways: %v", len(pending), orderedGateways)
+		e.sendBatchVia(ctx, st, orderedGateways, to, pending, msg, results)
+	}
+
+	failed := 0
+	for i := range results {
+		if results[i].Error == nil && len(results[i].Results) == 0 {
+			results[i].Error = ctx.Err()
+		}
+		if results[i].Error != nil {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		e.logger.Error("Batch sending finished with %d of %d recipients failed", failed, len(to))
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		return results, fmt.Errorf("%d of %d recipients failed", failed, len(to))
+	}
+
+	return results, nil
+}
+
+// routeBatch 确定每个号码的网关顺序，返回不同的网关顺序及使用该顺序的号码下标
+// 策略按收件人路由（strategy.RoutingStrategy）时不同号码可能使用不同的网关顺序，否则所有号码使用同一顺序
+func routeBatch(s strategy.Strategy, gateways []string, to []*message.PhoneNumber, msg *message.Message) ([][]string, [][]int) {
+	if !strategy.Routes(s) {
+		all := make([]int, len(to))
+		for i := range to {
+			all[i] = i
+		}
+		return [][]string{s.Apply(gateways)}, [][]int{all}
+	}
+
+	var routes [][]string
+	var groups [][]int
+	index := make(map[string]int)
+	for i, phone := range to {
+		ordered := orderGateways(s, gateways, phone, msg)
+		key := strings.Join(ordered, "\x00")
+		k, ok := index[key]
+		if !ok {
+			k = len(routes)
+			index[key] = k
+			routes = append(routes, ordered)
+			groups = append(groups, nil)
+		}
+		groups[k] = append(groups[k], i)
+	}
+	return routes, groups
+}
+
+// sendBatchVia 依次通过网关向 pending 中的号码发送，失败的号码按回退规则交由后续网关，结果写入 results
+func (e *EasySms) sendBatchVia(ctx context.Context, st *state, orderedGateways []string, to []*message.PhoneNumber, pending []int, msg *message.Message, results []RecipientResult) {
+	for _, gatewayName := range orderedGateways {
+		if len(pending) == 0 {
+			break
+		}
+
+		// 上下文已结束，停止尝试后续网关
+		if err := ctx.Err(); err != nil {
+			e.logger.Error("Batch sending aborted before gateway %s: %v", gatewayName, err)
+			break
+		}
+
+		var next, capable []int
+
+		// 网关不可用或逻辑模板解析失败时，所有号码按回退规则交由后续网关
+		gw, err := e.gateway(st, gatewayName)
+		var resolved *message.Message
+		if err == nil {
+			resolved, err = st.resolveMessage(gatewayName, msg)
+		}
+		if err != nil {
+			e.logger.Error("Gateway %s skipped: %v", gatewayName, err)
+			for _, i := range pending {
+				results[i].Results[gatewayName] = Result{Gateway: gatewayName, Status: StatusFailure, Error: err}
+				results[i].Error = err
+				if st.config.Fallback.Allow(err) {
+					next = append(next, i)
+				}
+			}
+			pending = next
+			continue
+		}
+
+		// 跳过网关无法发送的号码，直接交由后续网关
+		for _, i := range pending {
+			if err := unsupported(gw, to[i], resolved); err != nil {
+				results[i].Results[gatewayName] = Result{Gateway: gatewayName, Status: StatusSkipped, Error: err}
+				results[i].Error = err
+				next = append(next, i)
+				continue
+			}
+			capable = append(capable, i)
+		}
+		if len(capable) == 0 {
+			pending = next
+			continue
+		}
+
+		var gatewayResults []Result
+		if bg, ok := gw.(gateway.BatchGateway); ok && bg.BatchSize() > 1 {
+			gatewayResults = e.sendNativeBatch(ctx, st, gatewayName, bg, to, capable, resolved)
+		} else {
+			gatewayResults = e.sendEachRecipient(ctx, st, gatewayName, gw, to, capable, resolved)
+		}
+
+		// 失败的号码按回退规则决定是否交由后续网关发送
+		for k, i := range capable {
+			result := gatewayResults[k]
+			results[i].Results[gatewayName] = result
+			results[i].Error = result.Error
+			if result.Status != StatusSuccess && st.config.Fallback.Allow(result.Error) {
+				next = append(next, i)
+			}
+		}
+		sort.Ints(next)
+		pending = next
+	}
+}
+
+// sendNativeBatch 通过网关的批量接口发送已为该网关解析的消息，按网关的单次上限分批调用
+// 返回的结果与 pending 一一对应
+func (e *EasySms) sendNativeBatch(ctx context.Context, st *state, gatewayName string, bg gateway.BatchGateway, to []*message.PhoneNumber, pending []int, msg *message.Message) []Result {
+	results := make([]Result, 0, len(pending))
+	policy := st.retryPolicy(gatewayName)
+	size := bg.BatchSize()
+
+	for start := 0; start < len(pending); start += size {
+		end := start + size
+		if end > len(pending) {
+			end = len(pending)
+		}
+
+		phones := make([]*message.PhoneNumber, 0, end-start)
+		for _, i := range pending[start:end] {
+			phones = append(phones, to[i])
+		}
+
+		e.logger.Debug("Sending batch of %d via gateway: %s", len(phones), gatewayName)
+
+		// 网关可能修改模板数据，每次调用使用消息副本
+		var items []gateway.BatchResult
+		err := policy.Do(ctx, func(attempt int) error {
+			if attempt > 1 {
+				e.logger.Warning("Retrying batch via gateway %s, attempt %d", gatewayName, attempt)
+			}
+			var sendErr error
+			items, sendErr = bg.SendBatch(ctx, phones, msg.Clone())
+			return sendErr
+		})
+		st.report(ctx, gatewayName, err)
+
+		for k := range phones {
+			switch {
+			case err != nil:
+				results = append(results, Result{Gateway: gatewayName, Status: StatusFailure, Error: gateway.WrapError(gatewayName, err)})
+			case k >= len(items):
+				results = append(results, Result{Gateway: gatewayName, Status: StatusFailure, Error: gateway.NewSendError(gatewayName, gateway.CategoryUnknown, "", "no result returned")})
+			case items[k].Err != nil:
+				results = append(results, Result{Gateway: gatewayName, Status: StatusFailure, Data: items[k].Data, Error: gateway.WrapError(gatewayName, items[k].Err)})
+			default:
+				results = append(results, Result{Gateway: gatewayName, Status: StatusSuccess, Data: items[k].Data})
+			}
+		}
+
+		if err != nil {
+			e.logger.Error("Failed to send batch via gateway %s: %v", gatewayName, err)
+		}
+	}
+
+	return results
+}
+
+// sendEachRecipient 网关不支持批量接口时，使用有限并发逐个发送已为该网关解析的消息
+// 返回的结果与 pending 一一对应
+func (e *EasySms) sendEachRecipient(ctx context.Context, st *state, gatewayName string, gw gateway.Gateway, to []*message.PhoneNumber, pending []int, msg *message.Message) []Result {
+	concurrency := st.config.BatchConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+
+	results := make([]Result, len(pending))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for k, i := range pending {
+		wg.Add(1)
+		sem <- struct{}{}
+		// 每个号码使用独立的消息副本，避免并发修改模板数据
+		go func(k int, phone *message.PhoneNumber, m *message.Message) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[k] = e.sendResolved(ctx, st, gatewayName, gw, phone, m)
+		}(k, to[i], msg.Clone())
+	}
+
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,25 @@
+package easysms
+
+import (
+	"github.com/anhao/go-easy-sms/gateway"
+	"github.com/anhao/go-easy-sms/message"
+)
+
+// unsupported 判断网关能否发送该号码和已为该网关解析的消息，网关声明了能力（gateway.CapableGateway）且无法发送时返回原因
+func unsupported(gw gateway.Gateway, to *message.PhoneNumber, msg *message.Message) error {
+	cg, ok := gw.(gateway.CapableGateway)
+	if !ok {
+		return nil
+	}
+	return cg.Capabilities().Supports(to, msg)
+}
+
+// allSkipped 判断是否所有网关都因无法发送该号码或消息而被跳过
+func allSkipped(results map[string]Result) bool {
+	for _, result := range results {
+		if result.Status != StatusSkipped {
+			return false
+		}
+	}
+	return len(results) > 0
+}
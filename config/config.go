@@ -1,6 +1,7 @@
 package config
 
 import (
+	"github.com/anhao/go-easy-sms/retry"
 	"github.com/anhao/go-easy-sms/strategy"
 )
 
@@ -12,11 +13,26 @@ type Config struct {
 	// 默认策略
 	Strategy strategy.Strategy
 
+	// 配置文件或环境变量中的策略名称，名称不变时重新加载不会替换 Strategy
+	StrategyName string
+
+	// 默认重试策略，可在网关配置中通过 "retry" 项单独覆盖
+	Retry *retry.Policy
+
+	// 网关失败后的回退规则，为空时总是回退到下一个网关
+	Fallback *FallbackPolicy
+
+	// 批量发送时网关不支持批量接口，逐个发送的最大并发数，默认 10
+	BatchConcurrency int
+
 	// 默认可用的网关
 	DefaultGateways []string
 
 	// 网关配置
 	GatewayConfigs map[string]map[string]any
+
+	// 逻辑模板，键为模板名，消息使用模板名时按网关解析为各自的模板 ID 和参数
+	Templates map[string]*Template
 }
 
 // NewConfig 创建一个新的配置实例
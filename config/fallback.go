@@ -0,0 +1,61 @@
+package config
+
+import (
+	"errors"
+
+	"github.com/anhao/go-easy-sms/gateway"
+)
+
+// FallbackPolicy 决定网关发送失败后是回退到下一个网关还是立即停止
+type FallbackPolicy struct {
+	// 遇到这些分类的错误时立即停止，不再尝试后续网关
+	StopOn []gateway.ErrorCategory
+
+	// 为 true 时 StopOn 只对服务商错误码确定的分类生效，根据错误信息推断的分类（见 gateway.SendError.Inferred）仍然回退
+	IgnoreInferred bool
+
+	// 自定义判断，返回 true 时回退到下一个网关，设置后忽略 StopOn
+	ShouldFallback func(err *gateway.SendError) bool
+}
+
+// StopOnUserErrors 返回遇到无效号码和敏感内容时立即停止的回退规则
+// 这类错误换网关发送也不会成功，还会被多个服务商重复计费；
+// 只对服务商错误码确定的分类生效，根据错误信息推断的分类可能不准确，仍然回退
+func StopOnUserErrors() *FallbackPolicy {
+	return &FallbackPolicy{
+		StopOn: []gateway.ErrorCategory{
+			gateway.CategoryInvalidNumber,
+			gateway.CategorySensitiveContent,
+		},
+		IgnoreInferred: true,
+	}
+}
+
+// Allow 判断网关返回 err 后是否回退到下一个网关，p 为 nil 时总是回退
+func (p *FallbackPolicy) Allow(err error) bool {
+	if p == nil {
+		return true
+	}
+
+	var sendErr *gateway.SendError
+	if !errors.As(err, &sendErr) {
+		sendErr = gateway.WrapError("", err)
+	}
+	if sendErr == nil {
+		return true
+	}
+
+	if p.ShouldFallback != nil {
+		return p.ShouldFallback(sendErr)
+	}
+	if p.IgnoreInferred && sendErr.Inferred {
+		return true
+	}
+
+	for _, category := range p.StopOn {
+		if sendErr.Category == category {
+			return false
+		}
+	}
+	return true
+}
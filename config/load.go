@@ -0,0 +1,353 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/anhao/go-easy-sms/retry"
+	"github.com/anhao/go-easy-sms/strategy"
+)
+
+// 配置文件格式
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// envPattern 匹配 ${VAR} 和 ${VAR:-default}
+var envPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// LoadFile 从 JSON 或 YAML 文件加载配置，按扩展名（.json、.yaml、.yml）判断格式
+// 字符串值中的 ${VAR} 会被替换为环境变量，未设置的变量可以使用 ${VAR:-default} 指定默认值
+//
+//	timeout: 5
+//	strategy: random
+//	default_gateways: [aliyun, qcloud]
+//	gateways:
+//	  aliyun:
+//	    access_key_id: ${ALIYUN_ACCESS_KEY_ID}
+//	    access_key_secret: ${ALIYUN_ACCESS_KEY_SECRET}
+//	    timeout: 3s
+func LoadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var format string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		format = FormatJSON
+	case ".yaml", ".yml":
+		format = FormatYAML
+	default:
+		return nil, fmt.Errorf("config: unsupported file format %s", filepath.Ext(path))
+	}
+
+	cfg, err := Parse(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("config: %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Parse 解析 JSON 或 YAML 格式的配置，format 为 FormatJSON 或 FormatYAML
+func Parse(data []byte, format string) (*Config, error) {
+	raw := make(map[string]any)
+	switch format {
+	case FormatJSON:
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format %s", format)
+	}
+
+	expanded, err := expandEnv(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := NewConfig()
+	if err := cfg.apply(expanded.(map[string]any)); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// FromEnv 从环境变量加载配置，变量名均以 prefix 开头，如 prefix 为 EASYSMS 时：
+//
+//	EASYSMS_TIMEOUT=5
+//	EASYSMS_STRATEGY=random
+//	EASYSMS_DEFAULT_GATEWAYS=aliyun,qcloud
+//	EASYSMS_BATCH_CONCURRENCY=10
+//	EASYSMS_GATEWAYS_ALIYUN__ACCESS_KEY_ID=xxx
+//
+// 网关配置的网关名称和配置项之间使用双下划线分隔，均转换为小写
+func FromEnv(prefix string) (*Config, error) {
+	cfg := NewConfig()
+	if err := cfg.ApplyEnv(prefix); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// ApplyEnv 使用环境变量覆盖配置，变量格式见 FromEnv，常用于在配置文件的基础上覆盖密钥
+func (c *Config) ApplyEnv(prefix string) error {
+	prefix = strings.ToUpper(strings.TrimSuffix(prefix, "_"))
+	if prefix != "" {
+		prefix += "_"
+	}
+
+	raw := make(map[string]any)
+	gateways := make(map[string]any)
+	for _, env := range os.Environ() {
+		key, value, ok := strings.Cut(env, "=")
+		if !ok || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		name := strings.ToLower(strings.TrimPrefix(key, prefix))
+		switch name {
+		case "timeout", "strategy", "batch_concurrency":
+			raw[name] = value
+		case "default_gateways":
+			var list []any
+			for _, gw := range strings.Split(value, ",") {
+				if gw = strings.TrimSpace(gw); gw != "" {
+					list = append(list, gw)
+				}
+			}
+			raw[name] = list
+		default:
+			rest := strings.TrimPrefix(name, "gateways_")
+			if rest == name {
+				continue
+			}
+			gatewayName, option, ok := strings.Cut(rest, "__")
+			if !ok || gatewayName == "" || option == "" {
+				continue
+			}
+			if gateways[gatewayName] == nil {
+				gateways[gatewayName] = make(map[string]any)
+			}
+			gateways[gatewayName].(map[string]any)[option] = value
+		}
+	}
+	if len(gateways) > 0 {
+		raw["gateways"] = gateways
+	}
+
+	return c.apply(raw)
+}
+
+// StrategyByName 按名称创建策略，支持 order 和 random
+func StrategyByName(name string) (strategy.Strategy, error) {
+	switch strings.ToLower(name) {
+	case "", "order":
+		return strategy.NewOrderStrategy(), nil
+	case "random":
+		return strategy.NewRandomStrategy(), nil
+	default:
+		return nil, fmt.Errorf("unknown strategy %s", name)
+	}
+}
+
+// apply 将解析后的配置合并到 c 中，网关配置按配置项合并
+func (c *Config) apply(raw map[string]any) error {
+	if v, ok := raw["timeout"]; ok {
+		timeout, err := parseSeconds(v)
+		if err != nil {
+			return fmt.Errorf("timeout: %w", err)
+		}
+		c.Timeout = timeout
+	}
+
+	// 策略名称不变时保留已有的策略，避免替换代码中包装过的策略并丢失其状态
+	if v, ok := raw["strategy"]; ok {
+		name, _ := v.(string)
+		name = strings.ToLower(name)
+		if name == "" {
+			name = "order"
+		}
+		if c.Strategy == nil || name != c.StrategyName {
+			s, err := StrategyByName(name)
+			if err != nil {
+				return err
+			}
+			c.Strategy = s
+		}
+		c.StrategyName = name
+	}
+
+	if v, ok := raw["retry"]; ok {
+		c.Retry = retry.FromConfig(v)
+	}
+
+	if v, ok := raw["batch_concurrency"]; ok {
+		n, err := parseInt(v)
+		if err != nil {
+			return fmt.Errorf("batch_concurrency: %w", err)
+		}
+		c.BatchConcurrency = n
+	}
+
+	if v, ok := raw["default_gateways"]; ok {
+		list, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("default_gateways: expected a list, got %T", v)
+		}
+		c.DefaultGateways = make([]string, 0, len(list))
+		for _, item := range list {
+			c.DefaultGateways = append(c.DefaultGateways, fmt.Sprint(item))
+		}
+	}
+
+	if v, ok := raw["gateways"]; ok {
+		gateways, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("gateways: expected a map, got %T", v)
+		}
+		if c.GatewayConfigs == nil {
+			c.GatewayConfigs = make(map[string]map[string]any)
+		}
+
+		names := make([]string, 0, len(gateways))
+		for name := range gateways {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			options, ok := gateways[name].(map[string]any)
+			if !ok {
+				return fmt.Errorf("gateways.%s: expected a map, got %T", name, gateways[name])
+			}
+			if c.GatewayConfigs[name] == nil {
+				c.GatewayConfigs[name] = make(map[string]any)
+			}
+			for key, value := range options {
+				// 网关超时统一转换为秒
+				if key == "timeout" {
+					timeout, err := parseSeconds(value)
+					if err != nil {
+						return fmt.Errorf("gateways.%s.timeout: %w", name, err)
+					}
+					value = timeout
+				}
+				c.GatewayConfigs[name][key] = value
+			}
+		}
+	}
+
+	if v, ok := raw["templates"]; ok {
+		// 逻辑模板的结构固定，通过 JSON 转换为 Template
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("templates: %w", err)
+		}
+		var templates map[string]*Template
+		if err := json.Unmarshal(data, &templates); err != nil {
+			return fmt.Errorf("templates: %w", err)
+		}
+		if c.Templates == nil {
+			c.Templates = make(map[string]*Template)
+		}
+		for name, t := range templates {
+			c.Templates[name] = t
+		}
+	}
+
+	return nil
+}
+
+// expandEnv 替换字符串值中的 ${VAR}，未设置且没有默认值的变量返回错误
+func expandEnv(v any) (any, error) {
+	switch val := v.(type) {
+	case string:
+		var missing string
+		expanded := envPattern.ReplaceAllStringFunc(val, func(s string) string {
+			m := envPattern.FindStringSubmatch(s)
+			if value, ok := os.LookupEnv(m[1]); ok {
+				return value
+			}
+			if strings.Contains(s, ":-") {
+				return m[2]
+			}
+			missing = m[1]
+			return s
+		})
+		if missing != "" {
+			return nil, fmt.Errorf("environment variable %s is not set", missing)
+		}
+		return expanded, nil
+	case map[string]any:
+		for key, item := range val {
+			expanded, err := expandEnv(item)
+			if err != nil {
+				return nil, err
+			}
+			val[key] = expanded
+		}
+		return val, nil
+	case []any:
+		for i, item := range val {
+			expanded, err := expandEnv(item)
+			if err != nil {
+				return nil, err
+			}
+			val[i] = expanded
+		}
+		return val, nil
+	default:
+		return v, nil
+	}
+}
+
+// parseSeconds 将数字（秒）或时间字符串（如 "500ms"、"3s"）转换为秒
+func parseSeconds(v any) (float64, error) {
+	switch val := v.(type) {
+	case float64:
+		return val, nil
+	case int:
+		return float64(val), nil
+	case string:
+		if f, err := strconv.ParseFloat(val, 64); err == nil {
+			return f, nil
+		}
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return 0, err
+		}
+		return d.Seconds(), nil
+	default:
+		return 0, fmt.Errorf("invalid duration %v", v)
+	}
+}
+
+// parseInt 将数字或数字字符串转换为整数
+func parseInt(v any) (int, error) {
+	switch val := v.(type) {
+	case int:
+		return val, nil
+	case float64:
+		return int(val), nil
+	case string:
+		return strconv.Atoi(val)
+	default:
+		return 0, fmt.Errorf("invalid integer %v", v)
+	}
+}
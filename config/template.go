@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/anhao/go-easy-sms/message"
+)
+
+// Template 是逻辑模板，将一个模板名（如 "login_otp"）映射到各网关的模板 ID 和参数
+// 消息的 Template 为已注册的逻辑模板名时，EasySms 会为每个尝试的网关分别解析
+type Template struct {
+	// 各网关的模板配置，键为网关名称，未配置的网关不会被使用
+	Gateways map[string]*GatewayTemplate
+}
+
+// GatewayTemplate 是逻辑模板在单个网关上的配置
+type GatewayTemplate struct {
+	// 网关的模板 ID
+	ID string
+
+	// 短信内容，用于只支持内容短信的网关，使用 {参数名} 引用逻辑参数
+	Content string
+
+	// 参数名映射，逻辑参数名 -> 网关参数名，未映射的参数保持原名
+	Params map[string]string
+
+	// 参数顺序（逻辑参数名），用于腾讯云等按位置传参的网关
+	// 设置后模板数据只包含这些参数，并以 "0"、"1"…… 为键按顺序传递
+	Order []string
+}
+
+// Template 获取已注册的逻辑模板
+func (c *Config) Template(name string) (*Template, bool) {
+	if c == nil || c.Templates == nil || name == "" {
+		return nil, false
+	}
+	t, ok := c.Templates[name]
+	return t, ok && t != nil
+}
+
+// Resolve 为网关生成使用其模板 ID 和参数的消息副本，网关未配置时返回错误
+func (t *Template) Resolve(gateway string, msg *message.Message) (*message.Message, error) {
+	gt, ok := t.Gateways[gateway]
+	if !ok || gt == nil {
+		return nil, fmt.Errorf("template %s is not configured for gateway %s", msg.GetTemplate(), gateway)
+	}
+	return gt.Apply(msg), nil
+}
+
+// Apply 返回使用网关模板 ID 和参数的消息副本
+func (t *GatewayTemplate) Apply(msg *message.Message) *message.Message {
+	resolved := msg.Clone()
+	data := msg.GetData()
+
+	resolved.SetTemplate(t.ID)
+	if t.Content != "" {
+		resolved.SetContent(t.render(data))
+	}
+
+	if len(t.Order) > 0 {
+		ordered := make(map[string]any, len(t.Order))
+		for i, name := range t.Order {
+			ordered[strconv.Itoa(i)] = data[name]
+		}
+		return resolved.SetData(ordered)
+	}
+
+	mapped := make(map[string]any, len(data))
+	for name, value := range data {
+		if alias, ok := t.Params[name]; ok && alias != "" {
+			name = alias
+		}
+		mapped[name] = value
+	}
+	return resolved.SetData(mapped)
+}
+
+// render 使用逻辑参数替换内容中的 {参数名}
+func (t *GatewayTemplate) render(data map[string]any) string {
+	pairs := make([]string, 0, len(data)*2)
+	for name, value := range data {
+		pairs = append(pairs, "{"+name+"}", fmt.Sprintf("%v", value))
+	}
+	return strings.NewReplacer(pairs...).Replace(t.Content)
+}
@@ -0,0 +1,94 @@
+package easysms
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/anhao/go-easy-sms/gateway"
+)
+
+var (
+	driversMu sync.RWMutex
+	drivers   = make(map[string]NewGatewayCreator)
+)
+
+// Register 注册全局网关驱动，所有 EasySms 实例都可以使用，通常在第三方网关包的 init 中调用：
+//
+//	func init() {
+//		easysms.Register("acme", func(config map[string]any) (gateway.Gateway, error) {
+//			return NewAcmeGateway(config), nil
+//		})
+//	}
+//
+// creator 为 nil 或重复注册同名驱动时 panic；实例上通过 RegisterGatewayCreator 注册的同名创建函数优先
+func Register(name string, creator NewGatewayCreator) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	if creator == nil {
+		panic("easysms: Register creator is nil")
+	}
+	if _, dup := drivers[name]; dup {
+		panic("easysms: Register called twice for driver " + name)
+	}
+	drivers[name] = creator
+}
+
+// Drivers 返回已注册的全局网关驱动名称，按名称排序
+func Drivers() []string {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// driver 查找全局网关驱动
+func driver(name string) (NewGatewayCreator, bool) {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	creator, ok := drivers[name]
+	return creator, ok
+}
+
+// 注册内置网关驱动
+func init() {
+	Register("aliyun", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewAliyunGateway(config), nil })
+	Register("aliyun_intl", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewAliyunIntlGateway(config), nil })
+	Register("aliyunrest", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewAliyunrestGateway(config), nil })
+	Register("baidu", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewBaiduGateway(config), nil })
+	Register("chuanglan", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewChuanglanGateway(config), nil })
+	Register("chuanglanv1", func(config map[string]any) (gateway.Gateway, error) {
+		return gateway.NewChuanglanv1Gateway(config), nil
+	})
+	Register("ctyun", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewCtyunGateway(config), nil })
+	Register("errorlog", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewErrorlogGateway(config), nil })
+	Register("huaxin", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewHuaxinGateway(config), nil })
+	Register("huyi", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewHuyiGateway(config), nil })
+	Register("juhe", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewJuheGateway(config), nil })
+	Register("kingtto", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewKingttoGateway(config), nil })
+	Register("luosimao", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewLuosimaoGateway(config), nil })
+	Register("maap", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewMaapGateway(config), nil })
+	Register("moduyun", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewModuyunGateway(config), nil })
+	Register("nowcn", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewNowcnGateway(config), nil })
+	Register("qcloud", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewQcloudGateway(config), nil })
+	Register("qiniu", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewQiniuGateway(config), nil })
+	Register("rongcloud", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewRongcloudGateway(config), nil })
+	Register("rongheyun", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewRongheyunGateway(config), nil })
+	Register("sendcloud", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewSendcloudGateway(config), nil })
+	Register("smsbao", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewSmsbaoGateway(config), nil })
+	Register("submail", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewSubmailGateway(config), nil })
+	Register("twilio", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewTwilioGateway(config), nil })
+	Register("ucloud", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewUcloudGateway(config), nil })
+	Register("ue35", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewUe35Gateway(config), nil })
+	Register("volcengine", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewVolcengineGateway(config), nil })
+	Register("yidongmasblack", func(config map[string]any) (gateway.Gateway, error) {
+		return gateway.NewYidongmasblackGateway(config), nil
+	})
+	Register("yunpian", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewYunpianGateway(config), nil })
+	Register("yuntongxun", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewYuntongxunGateway(config), nil })
+	Register("yunxin", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewYunxinGateway(config), nil })
+	Register("yunzhixun", func(config map[string]any) (gateway.Gateway, error) { return gateway.NewYunzhixunGateway(config), nil })
+}
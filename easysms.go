@@ -1,21 +1,31 @@
 package easysms
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/anhao/go-easy-sms/config"
 	"github.com/anhao/go-easy-sms/gateway"
 	"github.com/anhao/go-easy-sms/logger"
 	"github.com/anhao/go-easy-sms/message"
+	"github.com/anhao/go-easy-sms/receipt"
+	"github.com/anhao/go-easy-sms/retry"
 	"github.com/anhao/go-easy-sms/strategy"
 )
 
 // 状态常量
 const (
-	StatusSuccess = "success"
-	StatusFailure = "failure"
+	StatusSuccess  = "success"
+	StatusFailure  = "failure"
+	StatusCanceled = "canceled"
+	// StatusSkipped 网关无法发送该号码或消息（见 gateway.CapableGateway），未发起请求
+	StatusSkipped = "skipped"
 )
 
 // Result 表示发送短信的结果
@@ -41,6 +51,44 @@ func (e *GatewayError) Unwrap() error {
 	return e.Err
 }
 
+// InitError 汇总了创建 EasySms 时所有网关的配置错误，每一项都是 *GatewayError
+type InitError struct {
+	Errors []error
+}
+
+func (e *InitError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("easysms: %d gateway error(s): %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Unwrap 返回所有网关错误，Go 1.20 起 errors.Is 和 errors.As 通过它检查每个网关错误
+func (e *InitError) Unwrap() []error {
+	return e.Errors
+}
+
+// Is 判断是否有网关错误匹配 target，使 errors.Is 在 Go 1.20 之前也能检查每个网关错误
+func (e *InitError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As 查找第一个可以赋值给 target 的网关错误，使 errors.As 在 Go 1.20 之前也能检查每个网关错误
+func (e *InitError) As(target any) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
 // NewGatewayCreator 新的网关创建函数类型（优先级2：性能优化）
 type NewGatewayCreator func(config map[string]any) (gateway.Gateway, error)
 
@@ -64,12 +112,9 @@ func (r *GatewayRegistry) Register(name string, creator NewGatewayCreator) {
 	r.creators[name] = creator
 }
 
-// Create 创建网关实例
+// Create 创建网关实例，没有注册创建函数时使用 Register 注册的全局驱动
 func (r *GatewayRegistry) Create(name string, config map[string]any) (gateway.Gateway, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	if creator, ok := r.creators[name]; ok {
+	if creator, ok := r.lookup(name); ok {
 		return creator(config)
 	}
 
@@ -80,22 +125,43 @@ func (r *GatewayRegistry) Create(name string, config map[string]any) (gateway.Ga
 	}
 }
 
-// HasCreator 检查是否存在指定名称的创建函数
+// HasCreator 检查是否存在指定名称的创建函数或全局驱动
 func (r *GatewayRegistry) HasCreator(name string) bool {
+	_, ok := r.lookup(name)
+	return ok
+}
+
+// lookup 查找创建函数，注册表中的创建函数优先于全局驱动
+func (r *GatewayRegistry) lookup(name string) (NewGatewayCreator, bool) {
 	r.mu.RLock()
-	defer r.mu.RUnlock()
-	_, exists := r.creators[name]
-	return exists
+	creator, ok := r.creators[name]
+	r.mu.RUnlock()
+	if ok {
+		return creator, true
+	}
+	return driver(name)
 }
 
 // EasySms 是短信服务的主结构（优化后）
 type EasySms struct {
 	config   *config.Config
-	gateways map[string]gateway.Gateway
 	strategy strategy.Strategy
+
+	// 网关实例，只整体替换不修改，发送时可以不加锁地读取快照（见 snapshot）
+	gateways map[string]gateway.Gateway
+
 	registry *GatewayRegistry
 	logger   *logger.Logger
 	mu       sync.RWMutex // 优先级1：线程安全保护
+
+	// 按配置创建的网关使用的配置，更新配置时用于判断网关是否需要重建；手动注册的网关不在其中，同样只整体替换
+	built map[string]map[string]any
+
+	// 创建时自动注册网关的错误，由 NewWithError 返回
+	initErrors []error
+
+	// 串行执行配置更新
+	updateMu sync.Mutex
 }
 
 // New 创建一个新的 EasySms 实例
@@ -115,17 +181,28 @@ func New(cfg *config.Config) *EasySms {
 		strategy: cfg.Strategy,
 		registry: NewGatewayRegistry(),
 		logger:   logger.GetLogger(),
+		built:    make(map[string]map[string]any),
 	}
 
-	// 注册内置网关创建函数
-	sms.registerBuiltinGatewayCreators()
-
 	// 自动注册配置中的网关
-	sms.autoRegisterGateways()
+	sms.initErrors = sms.autoRegisterGateways()
 
 	return sms
 }
 
+// NewWithError 创建一个新的 EasySms 实例，并检查所有网关配置
+// 与 New 不同，配置无效或创建失败的网关、没有配置或创建函数的默认网关都会作为 *InitError 返回，
+// 适合在启动时发现配置错误；返回错误时 EasySms 实例仍然可用，只是不包含这些网关
+func NewWithError(cfg *config.Config) (*EasySms, error) {
+	sms := New(cfg)
+
+	errs := append(sms.initErrors, sms.checkDefaultGateways(sms.config, nil)...)
+	if len(errs) > 0 {
+		return sms, &InitError{Errors: errs}
+	}
+	return sms, nil
+}
+
 // SetLogger 设置日志记录器
 func (e *EasySms) SetLogger(l *logger.Logger) {
 	e.logger = l
@@ -136,7 +213,14 @@ func (e *EasySms) RegisterGateway(name string, gw gateway.Gateway) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	e.logger.Debug("Registering gateway: %s", name)
-	e.gateways[name] = gw
+	gateways := cloneMap(e.gateways)
+	gateways[name] = gw
+	e.gateways = gateways
+	if _, ok := e.built[name]; ok {
+		built := cloneMap(e.built)
+		delete(built, name)
+		e.built = built
+	}
 }
 
 // RegisterGatewayCreator 注册网关创建函数（新接口）
@@ -147,16 +231,19 @@ func (e *EasySms) RegisterGatewayCreator(name string, creator NewGatewayCreator)
 
 // Gateway 获取指定名称的网关（线程安全，优化性能）
 func (e *EasySms) Gateway(name string) (gateway.Gateway, error) {
-	// 首先尝试从缓存中获取（读锁）
-	e.mu.RLock()
-	if gw, ok := e.gateways[name]; ok {
-		e.mu.RUnlock()
+	return e.gateway(e.snapshot(), name)
+}
+
+// gateway 从 st 中获取网关，不存在时按 st 的配置创建
+func (e *EasySms) gateway(st *state, name string) (gateway.Gateway, error) {
+	// 首先尝试从缓存中获取
+	if gw, ok := st.gateways[name]; ok {
 		return gw, nil
 	}
-	e.mu.RUnlock()
 
 	// 检查配置是否存在
-	config, hasConfig := e.config.GatewayConfigs[name]
+	cfg := st.config
+	config, hasConfig := cfg.GatewayConfigs[name]
 	if !hasConfig {
 		return nil, &GatewayError{
 			GatewayName: name,
@@ -165,32 +252,162 @@ func (e *EasySms) Gateway(name string) (gateway.Gateway, error) {
 		}
 	}
 
-	// 尝试创建网关
-	if e.registry.HasCreator(name) {
-		e.logger.Debug("Creating gateway: %s", name)
-		gw, err := e.registry.Create(name, config)
+	// 尝试使用驱动创建网关
+	driver := gatewayDriver(name, config)
+	if e.registry.HasCreator(driver) {
+		if err := validateGatewayConfig(name, driver, config); err != nil {
+			return nil, err
+		}
+
+		e.logger.Debug("Creating gateway: %s (driver %s)", name, driver)
+		effective := gatewayConfig(config, cfg.Timeout)
+		gw, err := e.registry.Create(driver, effective)
 		if err != nil {
 			return nil, err
 		}
 
-		// 缓存创建的网关（写锁）
-		e.RegisterGateway(name, gw)
+		// 缓存创建的网关（写锁），创建期间配置已更新时不缓存，其他请求已创建时使用已缓存的实例
+		e.mu.Lock()
+		if e.config == cfg {
+			if cached, ok := e.gateways[name]; ok {
+				gw = cached
+			} else {
+				gateways, built := cloneMap(e.gateways), cloneMap(e.built)
+				gateways[name], built[name] = gw, effective
+				e.gateways, e.built = gateways, built
+			}
+		}
+		e.mu.Unlock()
 		return gw, nil
 	}
 
-	return nil, &GatewayError{
+	return nil, driverNotFound(name, driver)
+}
+
+// gatewayDriver 返回网关使用的驱动名称，配置了 driver 时使用 driver，否则使用网关名称
+// 同一驱动可以配置多个不同名称的网关实例，如使用不同账号的 aliyun_otp 和 aliyun_marketing
+func gatewayDriver(name string, cfg map[string]any) string {
+	if driver, ok := cfg[gateway.DriverKey].(string); ok && driver != "" {
+		return driver
+	}
+	return name
+}
+
+// driverNotFound 返回找不到网关驱动的错误
+func driverNotFound(name, driver string) error {
+	err := errors.New("gateway not found")
+	if driver != name {
+		err = fmt.Errorf("driver %s not found", driver)
+	}
+	return &GatewayError{
 		GatewayName: name,
 		Operation:   "lookup",
-		Err:         errors.New("gateway not found"),
+		Err:         err,
+	}
+}
+
+// validateGatewayConfig 按驱动校验内置网关的配置，配置项拼写错误、类型错误或缺少必填项时返回错误
+func validateGatewayConfig(name, driver string, cfg map[string]any) error {
+	if _, err := gateway.ParseConfig(driver, cfg); err != nil {
+		return &GatewayError{
+			GatewayName: name,
+			Operation:   "config",
+			Err:         err,
+		}
+	}
+	return nil
+}
+
+// gatewayConfig 返回创建网关使用的配置副本，网关未配置超时时使用全局超时
+func gatewayConfig(cfg map[string]any, timeout float64) map[string]any {
+	merged := make(map[string]any, len(cfg)+1)
+	for k, v := range cfg {
+		merged[k] = v
+	}
+	if _, ok := cfg["timeout"]; !ok && timeout > 0 {
+		merged["timeout"] = timeout
+	}
+	return merged
+}
+
+// ReceiptParser 根据网关配置获取网关的状态报告解析器
+// 状态报告的 Gateway 为网关实例名称，与 StatusPoller 查询得到的状态报告一致
+func (e *EasySms) ReceiptParser(gatewayName string) (receipt.ReceiptParser, error) {
+	cfg := e.currentConfig().GatewayConfigs[gatewayName]
+	p, err := receipt.New(gatewayDriver(gatewayName, cfg), cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	named := &namedParser{ReceiptParser: p, name: gatewayName}
+	if responder, ok := p.(receipt.Responder); ok {
+		return &namedResponder{namedParser: named, Responder: responder}, nil
+	}
+	return named, nil
+}
+
+// namedParser 将状态报告的网关名称设置为网关实例名称
+type namedParser struct {
+	receipt.ReceiptParser
+	name string
+}
+
+// Parse 实现 receipt.ReceiptParser 接口
+func (p *namedParser) Parse(r *http.Request) ([]*receipt.DeliveryReport, error) {
+	reports, err := p.ReceiptParser.Parse(r)
+	for _, report := range reports {
+		report.Gateway = p.name
+	}
+	return reports, err
+}
+
+// namedResponder 保留原解析器的应答
+type namedResponder struct {
+	*namedParser
+	receipt.Responder
+}
+
+// TemplateManager 获取网关的模板和签名管理接口，网关不支持时返回错误
+func (e *EasySms) TemplateManager(gatewayName string) (gateway.TemplateManager, error) {
+	gw, err := e.Gateway(gatewayName)
+	if err != nil {
+		return nil, err
+	}
+
+	tm, ok := gw.(gateway.TemplateManager)
+	if !ok {
+		return nil, &GatewayError{
+			GatewayName: gatewayName,
+			Operation:   "template management",
+			Err:         errors.New("not supported"),
+		}
 	}
+	return tm, nil
+}
+
+// orderGateways 使用策略确定网关顺序，策略实现 strategy.RoutingStrategy 时按收件人和消息路由
+func orderGateways(s strategy.Strategy, gateways []string, to *message.PhoneNumber, msg *message.Message) []string {
+	if rs, ok := s.(strategy.RoutingStrategy); ok {
+		return rs.Route(gateways, to, msg)
+	}
+	return s.Apply(gateways)
 }
 
 // Send 发送短信
 func (e *EasySms) Send(to *message.PhoneNumber, msg *message.Message) (map[string]Result, error) {
+	return e.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+// ctx 被取消或超时后不再尝试后续网关，并将其传递到网关的 HTTP 请求中
+func (e *EasySms) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (map[string]Result, error) {
+	// 配置更新后，本次发送仍然使用开始时的配置、策略和网关
+	st := e.snapshot()
+
 	// 如果消息中没有指定网关，使用默认网关
 	gateways := msg.GetGateways()
 	if len(gateways) == 0 {
-		gateways = e.config.DefaultGateways
+		gateways = st.config.DefaultGateways
 	}
 
 	if len(gateways) == 0 {
@@ -200,438 +417,351 @@ func (e *EasySms) Send(to *message.PhoneNumber, msg *message.Message) (map[strin
 	e.logger.Info("Sending message to %s using gateways: %v", to.String(), gateways)
 
 	// 使用策略确定网关顺序
-	orderedGateways := e.strategy.Apply(gateways)
+	orderedGateways := orderGateways(st.strategy, gateways, to, msg)
+	if len(orderedGateways) == 0 {
+		return nil, errors.New("no gateway available")
+	}
 
+	// 并发策略：同时向前 N 个网关发送，任一成功即返回
 	results := make(map[string]Result)
+	next := 0
 	var lastErr error
-	success := false
-
-	// 尝试每个网关，直到一个成功
-	for _, gatewayName := range orderedGateways {
-		e.logger.Debug("Trying gateway: %s", gatewayName)
+	if cs, ok := st.strategy.(strategy.ConcurrentStrategy); ok && cs.Concurrency() > 1 {
+		var success bool
+		success, next, lastErr = e.raceSend(ctx, st, orderedGateways, cs.Concurrency(), to, msg, results)
+		if success {
+			return results, nil
+		}
 
-		gateway, err := e.Gateway(gatewayName)
-		if err != nil {
-			e.logger.Error("Gateway %s not available: %v", gatewayName, err)
-			results[gatewayName] = Result{
-				Gateway: gatewayName,
-				Status:  StatusFailure,
-				Error:   err,
+		for _, gatewayName := range orderedGateways[:next] {
+			if err := e.stopFallback(st, gatewayName, results[gatewayName]); err != nil {
+				return results, err
 			}
-			lastErr = err
-			continue
 		}
+	}
 
-		// 尝试发送消息
-		e.logger.Debug("Sending message via gateway: %s", gatewayName)
-		resp, err := gateway.Send(to, msg)
-		if err != nil {
-			e.logger.Error("Failed to send message via gateway %s: %v", gatewayName, err)
-			results[gatewayName] = Result{
-				Gateway: gatewayName,
-				Status:  StatusFailure,
-				Error:   err,
-			}
-			lastErr = err
-			continue
+	// 依次尝试剩余网关，直到一个成功
+	for _, gatewayName := range orderedGateways[next:] {
+		// 上下文已结束，停止尝试后续网关
+		if err := ctx.Err(); err != nil {
+			e.logger.Error("Sending aborted before gateway %s: %v", gatewayName, err)
+			return results, err
 		}
 
-		// 成功
-		e.logger.Info("Successfully sent message via gateway: %s", gatewayName)
-		results[gatewayName] = Result{
+		result := e.sendViaGateway(ctx, st, gatewayName, to, msg)
+		results[gatewayName] = result
+		if result.Status == StatusSuccess {
+			return results, nil
+		}
+		if err := e.stopFallback(st, gatewayName, result); err != nil {
+			return results, err
+		}
+		// 优先返回发送失败的原因，其次是跳过的原因
+		if result.Status != StatusSkipped || lastErr == nil {
+			lastErr = result.Error
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return results, err
+	}
+
+	if allSkipped(results) {
+		e.logger.Error("No gateway supports the message: %v", lastErr)
+		return results, fmt.Errorf("no gateway supports the message: %w", lastErr)
+	}
+
+	e.logger.Error("All gateways failed: %v", lastErr)
+	return results, fmt.Errorf("all gateways failed: %w", lastErr)
+}
+
+// sendViaGateway 通过 st 中的网关发送短信并返回结果，回退、重试和模板均使用 st 的配置
+// 网关无法发送该号码或解析后的消息时不发起请求，返回 StatusSkipped
+func (e *EasySms) sendViaGateway(ctx context.Context, st *state, gatewayName string, to *message.PhoneNumber, msg *message.Message) Result {
+	e.logger.Debug("Trying gateway: %s", gatewayName)
+
+	gw, err := e.gateway(st, gatewayName)
+	if err != nil {
+		e.logger.Error("Gateway %s not available: %v", gatewayName, err)
+		return Result{
 			Gateway: gatewayName,
-			Status:  StatusSuccess,
-			Data:    resp,
+			Status:  StatusFailure,
+			Error:   err,
 		}
-		success = true
-		break
 	}
 
-	if !success {
-		e.logger.Error("All gateways failed: %v", lastErr)
-		return results, fmt.Errorf("all gateways failed: %v", lastErr)
+	// 按网关解析消息和逻辑模板
+	msg, err = st.resolveMessage(gatewayName, msg)
+	if err != nil {
+		e.logger.Error("Gateway %s skipped: %v", gatewayName, err)
+		return Result{
+			Gateway: gatewayName,
+			Status:  StatusFailure,
+			Error:   err,
+		}
 	}
 
-	return results, nil
+	if err := unsupported(gw, to, msg); err != nil {
+		e.logger.Debug("Gateway %s skipped: %v", gatewayName, err)
+		return Result{
+			Gateway: gatewayName,
+			Status:  StatusSkipped,
+			Error:   err,
+		}
+	}
+
+	return e.sendResolved(ctx, st, gatewayName, gw, to, msg)
 }
 
-// registerBuiltinGatewayCreators 注册内置网关创建函数（优先级3：消除硬编码）
-func (e *EasySms) registerBuiltinGatewayCreators() {
-	// 使用 map 批量注册，减少重复代码
-	builtinCreators := map[string]NewGatewayCreator{
-		"aliyun": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewAliyunGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "aliyun",
-					Operation:   "creation",
-					Err:         errors.New("failed to create aliyun gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"yunpian": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewYunpianGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "yunpian",
-					Operation:   "creation",
-					Err:         errors.New("failed to create yunpian gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"errorlog": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewErrorlogGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "errorlog",
-					Operation:   "creation",
-					Err:         errors.New("failed to create errorlog gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"qcloud": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewQcloudGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "qcloud",
-					Operation:   "creation",
-					Err:         errors.New("failed to create qcloud gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"chuanglan": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewChuanglanGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "chuanglan",
-					Operation:   "creation",
-					Err:         errors.New("failed to create chuanglan gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"ucloud": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewUcloudGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "ucloud",
-					Operation:   "creation",
-					Err:         errors.New("failed to create ucloud gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"baidu": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewBaiduGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "baidu",
-					Operation:   "creation",
-					Err:         errors.New("failed to create baidu gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"ctyun": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewCtyunGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "ctyun",
-					Operation:   "creation",
-					Err:         errors.New("failed to create ctyun gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"huaxin": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewHuaxinGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "huaxin",
-					Operation:   "creation",
-					Err:         errors.New("failed to create huaxin gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"submail": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewSubmailGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "submail",
-					Operation:   "creation",
-					Err:         errors.New("failed to create submail gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"smsbao": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewSmsbaoGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "smsbao",
-					Operation:   "creation",
-					Err:         errors.New("failed to create smsbao gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"aliyun_intl": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewAliyunIntlGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "aliyun_intl",
-					Operation:   "creation",
-					Err:         errors.New("failed to create aliyun_intl gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"aliyunrest": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewAliyunrestGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "aliyunrest",
-					Operation:   "creation",
-					Err:         errors.New("failed to create aliyunrest gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"chuanglanv1": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewChuanglanv1Gateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "chuanglanv1",
-					Operation:   "creation",
-					Err:         errors.New("failed to create chuanglanv1 gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"huyi": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewHuyiGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "huyi",
-					Operation:   "creation",
-					Err:         errors.New("failed to create huyi gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"juhe": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewJuheGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "juhe",
-					Operation:   "creation",
-					Err:         errors.New("failed to create juhe gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"kingtto": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewKingttoGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "kingtto",
-					Operation:   "creation",
-					Err:         errors.New("failed to create kingtto gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"luosimao": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewLuosimaoGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "luosimao",
-					Operation:   "creation",
-					Err:         errors.New("failed to create luosimao gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"maap": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewMaapGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "maap",
-					Operation:   "creation",
-					Err:         errors.New("failed to create maap gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"moduyun": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewModuyunGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "moduyun",
-					Operation:   "creation",
-					Err:         errors.New("failed to create moduyun gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"nowcn": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewNowcnGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "nowcn",
-					Operation:   "creation",
-					Err:         errors.New("failed to create nowcn gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"qiniu": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewQiniuGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "qiniu",
-					Operation:   "creation",
-					Err:         errors.New("failed to create qiniu gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"rongcloud": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewRongcloudGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "rongcloud",
-					Operation:   "creation",
-					Err:         errors.New("failed to create rongcloud gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"rongheyun": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewRongheyunGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "rongheyun",
-					Operation:   "creation",
-					Err:         errors.New("failed to create rongheyun gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"sendcloud": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewSendcloudGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "sendcloud",
-					Operation:   "creation",
-					Err:         errors.New("failed to create sendcloud gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"twilio": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewTwilioGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "twilio",
-					Operation:   "creation",
-					Err:         errors.New("failed to create twilio gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"yuntongxun": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewYuntongxunGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "yuntongxun",
-					Operation:   "creation",
-					Err:         errors.New("failed to create yuntongxun gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"volcengine": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewVolcengineGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "volcengine",
-					Operation:   "creation",
-					Err:         errors.New("failed to create volcengine gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"ue35": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewUe35Gateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "ue35",
-					Operation:   "creation",
-					Err:         errors.New("failed to create ue35 gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"yunxin": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewYunxinGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "yunxin",
-					Operation:   "creation",
-					Err:         errors.New("failed to create yunxin gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"yunzhixun": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewYunzhixunGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "yunzhixun",
-					Operation:   "creation",
-					Err:         errors.New("failed to create yunzhixun gateway"),
-				}
-			}
-			return gw, nil
-		},
-		"yidongmasblack": func(config map[string]any) (gateway.Gateway, error) {
-			gw := gateway.NewYidongmasblackGateway(config)
-			if gw == nil {
-				return nil, &GatewayError{
-					GatewayName: "yidongmasblack",
-					Operation:   "creation",
-					Err:         errors.New("failed to create yidongmasblack gateway"),
-				}
-			}
-			return gw, nil
-		},
+// sendResolved 通过网关发送已为该网关解析的消息，失败时按重试策略重试，并将结果反馈给策略
+func (e *EasySms) sendResolved(ctx context.Context, st *state, gatewayName string, gw gateway.Gateway, to *message.PhoneNumber, msg *message.Message) Result {
+	// 尝试发送消息，失败时按重试策略重试
+	policy := st.retryPolicy(gatewayName)
+	var resp any
+	err := policy.Do(ctx, func(attempt int) error {
+		if attempt > 1 {
+			e.logger.Warning("Retrying gateway %s, attempt %d", gatewayName, attempt)
+		}
+
+		// 网关可能修改模板数据，允许重试时每次使用消息副本
+		m := msg
+		if policy != nil && policy.MaxAttempts > 1 {
+			m = msg.Clone()
+		}
+
+		e.logger.Debug("Sending message via gateway: %s", gatewayName)
+		var sendErr error
+		resp, sendErr = gateway.SendWithContext(ctx, gw, to, m)
+		return sendErr
+	})
+	st.report(ctx, gatewayName, err)
+	if err != nil {
+		e.logger.Error("Failed to send message via gateway %s: %v", gatewayName, err)
+		// 统一转换为 SendError，便于调用方通过 errors.As 判断错误分类
+		return Result{
+			Gateway: gatewayName,
+			Status:  StatusFailure,
+			Error:   gateway.WrapError(gatewayName, err),
+		}
 	}
 
-	// 批量注册内置网关
-	for name, creator := range builtinCreators {
-		e.registry.Register(name, creator)
+	// 成功
+	e.logger.Info("Successfully sent message via gateway: %s", gatewayName)
+	return Result{
+		Gateway: gatewayName,
+		Status:  StatusSuccess,
+		Data:    resp,
 	}
 }
 
-// autoRegisterGateways 自动注册配置中的网关（优化版本）
-func (e *EasySms) autoRegisterGateways() {
-	for name, config := range e.config.GatewayConfigs {
-		if e.registry.HasCreator(name) {
-			e.logger.Debug("Auto registering gateway: %s", name)
-			gw, err := e.registry.Create(name, config)
-			if err != nil {
-				e.logger.Error("Failed to auto register gateway %s: %v", name, err)
-				continue
+// stopFallback 按 st 的回退规则判断网关失败后是否停止尝试后续网关，需要停止时返回错误
+func (e *EasySms) stopFallback(st *state, gatewayName string, result Result) error {
+	if result.Status != StatusFailure || st.config.Fallback.Allow(result.Error) {
+		return nil
+	}
+
+	e.logger.Error("Gateway %s failed and fallback stopped: %v", gatewayName, result.Error)
+	return fmt.Errorf("gateway %s failed without fallback: %w", gatewayName, result.Error)
+}
+
+// retryPolicy 获取网关的重试策略，网关配置中的 "retry" 项优先于全局配置
+func (st *state) retryPolicy(gatewayName string) *retry.Policy {
+	cfg := st.config
+	if gwCfg, ok := cfg.GatewayConfigs[gatewayName]; ok {
+		if policy := retry.FromConfig(gwCfg["retry"]); policy != nil {
+			return policy
+		}
+	}
+	return cfg.Retry
+}
+
+// report 将发送结果反馈给需要感知结果的策略
+// 因上下文结束导致的失败不是网关本身的问题，不予反馈
+func (st *state) report(ctx context.Context, gatewayName string, err error) {
+	fs, ok := st.strategy.(strategy.FeedbackStrategy)
+	if !ok {
+		return
+	}
+	if err != nil && ctx.Err() != nil {
+		return
+	}
+	fs.Report(gatewayName, err)
+}
+
+// raceSend 同时通过 gateways 中的前 n 个网关发送短信，采用第一个成功的结果并取消其余请求
+// 被跳过的网关不占用并发数，由后续网关补上；所有网关的结果都会记录到 results 中，被取消的网关状态为 StatusCanceled。
+// 返回是否成功、已尝试的网关数量和最后一个错误
+func (e *EasySms) raceSend(ctx context.Context, st *state, gateways []string, n int, to *message.PhoneNumber, msg *message.Message, results map[string]Result) (bool, int, error) {
+	raceCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ch := make(chan Result, len(gateways))
+	next, running := 0, 0
+	start := func() {
+		// 每个网关使用独立的消息副本，避免并发修改模板数据
+		go func(name string, m *message.Message) {
+			ch <- e.sendViaGateway(raceCtx, st, name, to, m)
+		}(gateways[next], msg.Clone())
+		next++
+		running++
+	}
+
+	for next < len(gateways) && running < n {
+		start()
+	}
+	e.logger.Debug("Racing gateways: %v", gateways[:next])
+
+	success := false
+	var lastErr error
+	for running > 0 {
+		result := <-ch
+		running--
+		switch {
+		case result.Status == StatusSuccess:
+			if !success {
+				success = true
+				cancel()
+			}
+		case result.Status == StatusSkipped:
+			if lastErr == nil {
+				lastErr = result.Error
+			}
+			if !success && next < len(gateways) {
+				start()
+			}
+		case success && errors.Is(result.Error, context.Canceled):
+			result.Status = StatusCanceled
+		default:
+			lastErr = result.Error
+		}
+		results[result.Gateway] = result
+	}
+
+	return success, next, lastErr
+}
+
+// autoRegisterGateways 自动注册配置中的网关，返回配置无效或创建失败的网关错误
+func (e *EasySms) autoRegisterGateways() []error {
+	gateways, built, errs := e.createGateways(e.config, nil, nil)
+
+	e.mu.Lock()
+	e.gateways = gateways
+	e.built = built
+	e.mu.Unlock()
+	return errs
+}
+
+// createGateways 创建配置中有驱动的网关，返回创建的网关及其使用的配置，以及配置无效或创建失败的网关错误
+// current 中的网关在创建时使用的配置（built）未变化时直接复用，不重新创建
+func (e *EasySms) createGateways(cfg *config.Config, current map[string]gateway.Gateway, built map[string]map[string]any) (map[string]gateway.Gateway, map[string]map[string]any, []error) {
+	names := make([]string, 0, len(cfg.GatewayConfigs))
+	for name := range cfg.GatewayConfigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	gateways := make(map[string]gateway.Gateway, len(names))
+	configs := make(map[string]map[string]any, len(names))
+	var errs []error
+	for _, name := range names {
+		gwCfg := cfg.GatewayConfigs[name]
+		driver := gatewayDriver(name, gwCfg)
+		if !e.registry.HasCreator(driver) {
+			continue
+		}
+
+		effective := gatewayConfig(gwCfg, cfg.Timeout)
+		if gw, ok := current[name]; ok && reflect.DeepEqual(built[name], effective) {
+			gateways[name] = gw
+			configs[name] = built[name]
+			continue
+		}
+
+		if err := validateGatewayConfig(name, driver, gwCfg); err != nil {
+			e.logger.Error("Invalid config for gateway %s: %v", name, err)
+			errs = append(errs, err)
+			continue
+		}
+
+		e.logger.Debug("Auto registering gateway: %s (driver %s)", name, driver)
+		gw, err := e.registry.Create(driver, effective)
+		if err == nil && gw == nil {
+			err = errors.New("creator returned nil gateway")
+		}
+		if err != nil {
+			e.logger.Error("Failed to auto register gateway %s: %v", name, err)
+			var gwErr *GatewayError
+			if !errors.As(err, &gwErr) {
+				err = &GatewayError{GatewayName: name, Operation: "creation", Err: err}
 			}
-			e.RegisterGateway(name, gw)
+			errs = append(errs, err)
+			continue
+		}
+		gateways[name] = gw
+		configs[name] = effective
+	}
+	return gateways, configs, errs
+}
+
+// checkDefaultGateways 检查默认网关是否都有配置和创建函数，registered 中已注册的网关视为可用
+func (e *EasySms) checkDefaultGateways(cfg *config.Config, registered map[string]gateway.Gateway) []error {
+	var errs []error
+	for _, name := range cfg.DefaultGateways {
+		if _, ok := registered[name]; ok {
+			continue
+		}
+		gwCfg, ok := cfg.GatewayConfigs[name]
+		if !ok {
+			errs = append(errs, &GatewayError{
+				GatewayName: name,
+				Operation:   "lookup",
+				Err:         errors.New("config not found"),
+			})
+			continue
+		}
+		if driver := gatewayDriver(name, gwCfg); !e.registry.HasCreator(driver) {
+			errs = append(errs, driverNotFound(name, driver))
 		}
 	}
+	return errs
+}
+
+// state 某一时刻的配置、策略和网关
+// 一次发送从开始到结束使用同一个 state，期间更新配置不影响本次发送的网关、回退规则、重试策略和模板
+type state struct {
+	config   *config.Config
+	strategy strategy.Strategy
+	gateways map[string]gateway.Gateway
+}
+
+// snapshot 返回当前的配置、策略和网关
+func (e *EasySms) snapshot() *state {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return &state{config: e.config, strategy: e.strategy, gateways: e.gateways}
+}
+
+// cloneMap 返回 m 的副本，用于整体替换 gateways 和 built
+func cloneMap[V any](m map[string]V) map[string]V {
+	clone := make(map[string]V, len(m)+1)
+	for k, v := range m {
+		clone[k] = v
+	}
+	return clone
+}
+
+// currentConfig 返回当前配置
+func (e *EasySms) currentConfig() *config.Config {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.config
+}
+
+// currentStrategy 返回当前策略
+func (e *EasySms) currentStrategy() strategy.Strategy {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.strategy
 }
 
 // SimpleSend 提供一个简单的发送接口
@@ -655,14 +785,8 @@ func (e *EasySms) SimpleSend(phone string, messageData map[string]any) (map[stri
 		case string:
 			msg.SetContent(c)
 		case func(string) string:
-			// 如果有指定网关，则使用第一个网关来获取内容
-			gatewayName := ""
-			if len(msg.GetGateways()) > 0 {
-				gatewayName = msg.GetGateways()[0]
-			} else if len(e.config.DefaultGateways) > 0 {
-				gatewayName = e.config.DefaultGateways[0]
-			}
-			msg.SetContent(c(gatewayName))
+			// 每尝试一个网关时重新调用，回退的网关也能获得对应的内容
+			msg.SetContentFunc(c)
 		}
 	}
 
@@ -672,14 +796,8 @@ func (e *EasySms) SimpleSend(phone string, messageData map[string]any) (map[stri
 		case string:
 			msg.SetTemplate(t)
 		case func(string) string:
-			// 如果有指定网关，则使用第一个网关来获取模板
-			gatewayName := ""
-			if len(msg.GetGateways()) > 0 {
-				gatewayName = msg.GetGateways()[0]
-			} else if len(e.config.DefaultGateways) > 0 {
-				gatewayName = e.config.DefaultGateways[0]
-			}
-			msg.SetTemplate(t(gatewayName))
+			// 每尝试一个网关时重新调用，回退的网关也能获得对应的模板
+			msg.SetTemplateFunc(t)
 		}
 	}
 
@@ -689,14 +807,8 @@ func (e *EasySms) SimpleSend(phone string, messageData map[string]any) (map[stri
 		case map[string]any:
 			msg.SetData(d)
 		case func(string) map[string]any:
-			// 如果有指定网关，则使用第一个网关来获取数据
-			gatewayName := ""
-			if len(msg.GetGateways()) > 0 {
-				gatewayName = msg.GetGateways()[0]
-			} else if len(e.config.DefaultGateways) > 0 {
-				gatewayName = e.config.DefaultGateways[0]
-			}
-			msg.SetData(d(gatewayName))
+			// 每尝试一个网关时重新调用，回退的网关也能获得对应的数据
+			msg.SetDataFunc(d)
 		}
 	}
 
@@ -1,11 +1,13 @@
 package gateway
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,8 +16,73 @@ import (
 	"encoding/base64"
 
 	"github.com/anhao/go-easy-sms/message"
+	"github.com/anhao/go-easy-sms/receipt"
 )
 
+// AliyunBatchSize 阿里云单次请求支持的最大号码数量
+const AliyunBatchSize = 1000
+
+// aliyunErrorCategories 阿里云错误码分类，阿里云国际和阿里云 REST 网关共用
+var aliyunErrorCategories = map[string]ErrorCategory{
+	"isv.MOBILE_NUMBER_ILLEGAL":         CategoryInvalidNumber,
+	"isv.DOMESTIC_NUMBER_NOT_SUPPORTED": CategoryInvalidNumber,
+	"isv.BLACK_KEY_CONTROL_LIMIT":       CategorySensitiveContent,
+	"isv.SMS_CONTENT_ILLEGAL":           CategorySensitiveContent,
+	"isv.SMS_SIGNATURE_ILLEGAL":         CategoryTemplate,
+	"isv.SMS_TEMPLATE_ILLEGAL":          CategoryTemplate,
+	"isv.TEMPLATE_MISSING_PARAMETERS":   CategoryTemplate,
+	"isv.SMS_SIGN_ILLEGAL":              CategoryTemplate,
+	"isv.INVALID_PARAMETERS":            CategoryInvalidParam,
+	"isv.INVALID_JSON_PARAM":            CategoryInvalidParam,
+	"isv.PARAM_LENGTH_LIMIT":            CategoryInvalidParam,
+	"isv.PARAM_NOT_SUPPORT_URL":         CategoryInvalidParam,
+	"isv.MOBILE_COUNT_OVER_LIMIT":       CategoryInvalidParam,
+	"isv.EXTEND_CODE_ERROR":             CategoryInvalidParam,
+	"isv.AMOUNT_NOT_ENOUGH":             CategoryInsufficientBalance,
+	"isv.OUT_OF_SERVICE":                CategoryInsufficientBalance,
+	"isv.BUSINESS_LIMIT_CONTROL":        CategoryRateLimited,
+	"isv.DAY_LIMIT_CONTROL":             CategoryRateLimited,
+	"Throttling.User":                   CategoryRateLimited,
+	"Throttling.Api":                    CategoryRateLimited,
+	"isv.ACCOUNT_NOT_EXISTS":            CategoryAuth,
+	"isv.ACCOUNT_ABNORMAL":              CategoryAuth,
+	"isv.PRODUCT_UN_SUBSCRIPT":          CategoryAuth,
+	"isv.PRODUCT_UNSUBSCRIBE":           CategoryAuth,
+	"isv.DENY_IP_RANGE":                 CategoryAuth,
+	"isp.RAM_PERMISSION_DENY":           CategoryAuth,
+	"InvalidAccessKeyId.NotFound":       CategoryAuth,
+	"InvalidAccessKeyId.Inactive":       CategoryAuth,
+	"SignatureDoesNotMatch":             CategoryAuth,
+	"isp.SYSTEM_ERROR":                  CategoryServer,
+	"isp.GATEWAY_ERROR":                 CategoryServer,
+	"ServiceUnavailable":                CategoryServer,
+}
+
+// AliyunConfig 阿里云短信网关配置
+type AliyunConfig struct {
+	CommonConfig
+
+	// AccessKey ID
+	AccessKeyID string `config:"access_key_id,required"`
+
+	// AccessKey Secret
+	AccessKeySecret string `config:"access_key_secret,required"`
+
+	// 短信签名
+	SignName string `config:"sign_name,required"`
+
+	// 接口地址，默认 http://dysmsapi.aliyuncs.com
+	Endpoint string `config:"endpoint"`
+
+	// 地域 ID，默认 cn-hangzhou
+	RegionID string `config:"region_id"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *AliyunConfig) Validate() error {
+	return requireFields(c)
+}
+
 // AliyunGateway 阿里云短信网关
 type AliyunGateway struct {
 	*BaseGateway
@@ -28,8 +95,38 @@ func NewAliyunGateway(config map[string]any) *AliyunGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，阿里云只支持模板短信和国内号码
+func (g *AliyunGateway) Capabilities() Capabilities {
+	return Capabilities{Template: true, Batch: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *AliyunGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *AliyunGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.send(ctx, to.GetNumber(), msg)
+}
+
+// BatchSize 单次请求支持的最大号码数量
+func (g *AliyunGateway) BatchSize() int {
+	return AliyunBatchSize
+}
+
+// SendBatch 使用逗号连接的 PhoneNumbers 批量发送短信
+// 阿里云只返回整体结果，成功时所有号码共享同一个响应
+func (g *AliyunGateway) SendBatch(ctx context.Context, to []*message.PhoneNumber, msg *message.Message) ([]BatchResult, error) {
+	result, err := g.send(ctx, joinNumbers(to, (*message.PhoneNumber).GetNumber), msg)
+	if err != nil {
+		return nil, err
+	}
+	return uniformBatchResults(len(to), result), nil
+}
+
+// send 向 phoneNumbers 发送短信，多个号码使用逗号分隔
+func (g *AliyunGateway) send(ctx context.Context, phoneNumbers string, msg *message.Message) (any, error) {
 	accessKeyID := g.GetConfigString("access_key_id")
 	accessKeySecret := g.GetConfigString("access_key_secret")
 	signName := g.GetConfigString("sign_name")
@@ -46,18 +143,9 @@ func (g *AliyunGateway) Send(to *message.PhoneNumber, msg *message.Message) (any
 
 	// 构建请求参数
 	params := map[string]string{
-		"AccessKeyId":      accessKeyID,
-		"Action":           "SendSms",
-		"Format":           "JSON",
-		"RegionId":         "cn-hangzhou",
-		"SignatureMethod":  "HMAC-SHA1",
-		"SignatureVersion": "1.0",
-		"SignatureNonce":   fmt.Sprintf("%d", time.Now().UnixNano()),
-		"Timestamp":        time.Now().UTC().Format("2006-01-02T15:04:05Z"),
-		"Version":          "2017-05-25",
-		"PhoneNumbers":     to.GetNumber(),
-		"SignName":         signName,
-		"TemplateCode":     templateCode,
+		"PhoneNumbers": phoneNumbers,
+		"SignName":     signName,
+		"TemplateCode": templateCode,
 	}
 
 	// 添加模板参数
@@ -69,6 +157,28 @@ func (g *AliyunGateway) Send(to *message.PhoneNumber, msg *message.Message) (any
 		params["TemplateParam"] = string(templateParamJSON)
 	}
 
+	return g.request(ctx, "SendSms", params)
+}
+
+// request 调用阿里云短信 API，补充公共参数并签名，Code 不为 OK 时返回错误
+func (g *AliyunGateway) request(ctx context.Context, action string, params map[string]string) (map[string]any, error) {
+	accessKeyID := g.GetConfigString("access_key_id")
+	accessKeySecret := g.GetConfigString("access_key_secret")
+	if accessKeyID == "" || accessKeySecret == "" {
+		return nil, errors.New("access_key_id and access_key_secret are required")
+	}
+
+	// 公共请求参数
+	params["AccessKeyId"] = accessKeyID
+	params["Action"] = action
+	params["Format"] = "JSON"
+	params["RegionId"] = g.GetConfigString("region_id", "cn-hangzhou")
+	params["SignatureMethod"] = "HMAC-SHA1"
+	params["SignatureVersion"] = "1.0"
+	params["SignatureNonce"] = fmt.Sprintf("%d", time.Now().UnixNano())
+	params["Timestamp"] = time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	params["Version"] = "2017-05-25"
+
 	// 计算签名
 	signature := g.computeSignature(accessKeySecret, params)
 	params["Signature"] = signature
@@ -88,9 +198,9 @@ func (g *AliyunGateway) Send(to *message.PhoneNumber, msg *message.Message) (any
 	// 发送 GET 请求
 	// 使用 BaseGateway 的 Get 方法，但传递完整的 URL 而不是分开的 endpoint 和 params
 	// 这样可以确保 URL 格式完全符合阿里云 API 的要求
-	result, err := g.Get(requestURL, nil, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
+	result, err := withStatusBody(g.GetContext(ctx, requestURL, nil, nil))
+	if result == nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 
 	// 检查响应状态
@@ -99,10 +209,181 @@ func (g *AliyunGateway) Send(to *message.PhoneNumber, msg *message.Message) (any
 		if msg, ok := result["Message"].(string); ok {
 			message = msg
 		}
-		return result, fmt.Errorf("aliyun gateway error: %s", message)
+		category, inferred := classify(aliyunErrorCategories, code, message)
+		return result, newSendError(g.GetName(), category, inferred, code, message, "aliyun gateway error: %s", message).withStatus(err)
 	}
 
-	return result, nil
+	return result, err
+}
+
+// MessageID 实现 StatusQuerier 接口，返回发送结果中的 BizId
+func (g *AliyunGateway) MessageID(result any) string {
+	if resultMap, ok := result.(map[string]any); ok {
+		return stringOf(resultMap["BizId"])
+	}
+	return ""
+}
+
+// QueryStatus 实现 StatusQuerier 接口，通过 QuerySendDetails 查询送达状态
+// 阿里云按发送日期查询，从当天起依次查询 StatusQueryLookback 覆盖的每一天
+func (g *AliyunGateway) QueryStatus(ctx context.Context, messageID string, to *message.PhoneNumber) (*receipt.DeliveryReport, error) {
+	now := time.Now().In(receipt.ChinaZone)
+	days := int(StatusQueryLookback / (24 * time.Hour))
+	for i := 0; i <= days; i++ {
+		day := now.AddDate(0, 0, -i)
+		result, err := g.request(ctx, "QuerySendDetails", map[string]string{
+			"PhoneNumber": to.GetNumber(),
+			"BizId":       messageID,
+			"SendDate":    day.Format("20060102"),
+			"PageSize":    "10",
+			"CurrentPage": "1",
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var details []any
+		if dtos, ok := result["SmsSendDetailDTOs"].(map[string]any); ok {
+			details, _ = dtos["SmsSendDetailDTO"].([]any)
+		}
+		for _, detail := range details {
+			if detailMap, ok := detail.(map[string]any); ok {
+				return g.detailReport(messageID, detailMap), nil
+			}
+		}
+	}
+
+	return pendingReport(g.GetName(), messageID, to), nil
+}
+
+// detailReport 将 SmsSendDetailDTO 转换为状态报告
+// SendStatus 为 1 表示等待回执，2 表示发送失败，3 表示发送成功
+func (g *AliyunGateway) detailReport(messageID string, detail map[string]any) *receipt.DeliveryReport {
+	errCode := stringOf(detail["ErrCode"])
+
+	status := receipt.StatusPending
+	switch stringOf(detail["SendStatus"]) {
+	case "2":
+		status = receipt.StatusFailed
+		if errCode == "EXPIRED" {
+			status = receipt.StatusExpired
+		}
+	case "3":
+		status = receipt.StatusDelivered
+	}
+
+	return &receipt.DeliveryReport{
+		Gateway:    g.GetName(),
+		MessageID:  messageID,
+		Phone:      stringOf(detail["PhoneNum"]),
+		Status:     status,
+		ErrorCode:  errCode,
+		SubmitTime: parseChinaTime("2006-01-02 15:04:05", stringOf(detail["SendDate"])),
+		ReportTime: parseChinaTime("2006-01-02 15:04:05", stringOf(detail["ReceiveDate"])),
+		Raw:        detail,
+	}
+}
+
+// AddTemplate 实现 TemplateManager 接口，通过 AddSmsTemplate 申请模板，返回 TemplateCode
+func (g *AliyunGateway) AddTemplate(ctx context.Context, req *TemplateRequest) (string, error) {
+	// 阿里云模板类型：0 验证码，1 短信通知，2 推广短信，3 国际/港澳台消息
+	templateType := int(req.Type)
+	if req.International {
+		templateType = 3
+	}
+
+	result, err := g.request(ctx, "AddSmsTemplate", map[string]string{
+		"TemplateType":    strconv.Itoa(templateType),
+		"TemplateName":    req.Name,
+		"TemplateContent": req.Content,
+		"Remark":          req.Remark,
+	})
+	if err != nil {
+		return "", err
+	}
+	return stringOf(result["TemplateCode"]), nil
+}
+
+// QueryTemplateStatus 实现 TemplateManager 接口，通过 QuerySmsTemplate 查询模板审核状态
+func (g *AliyunGateway) QueryTemplateStatus(ctx context.Context, id string) (*TemplateStatus, error) {
+	result, err := g.request(ctx, "QuerySmsTemplate", map[string]string{
+		"TemplateCode": id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &TemplateStatus{
+		ID:     id,
+		Name:   stringOf(result["TemplateName"]),
+		Status: aliyunReviewStatus(result["TemplateStatus"]),
+		Reason: stringOf(result["Reason"]),
+		Raw:    result,
+	}, nil
+}
+
+// DeleteTemplate 实现 TemplateManager 接口，通过 DeleteSmsTemplate 删除模板
+func (g *AliyunGateway) DeleteTemplate(ctx context.Context, id string) error {
+	_, err := g.request(ctx, "DeleteSmsTemplate", map[string]string{
+		"TemplateCode": id,
+	})
+	return err
+}
+
+// AddSign 实现 TemplateManager 接口，通过 AddSmsSign 申请签名，返回签名名称
+// Extra 中的参数（如 SignType、SignFileList.1.FileContents）原样传递
+func (g *AliyunGateway) AddSign(ctx context.Context, req *SignRequest) (string, error) {
+	params := map[string]string{
+		"SignName":   req.Name,
+		"SignSource": strconv.Itoa(req.Source),
+		"Remark":     req.Remark,
+	}
+	for k, v := range req.Extra {
+		params[k] = fmt.Sprintf("%v", v)
+	}
+
+	if _, err := g.request(ctx, "AddSmsSign", params); err != nil {
+		return "", err
+	}
+	return req.Name, nil
+}
+
+// QuerySignStatus 实现 TemplateManager 接口，通过 QuerySmsSign 查询签名审核状态
+func (g *AliyunGateway) QuerySignStatus(ctx context.Context, id string) (*SignStatus, error) {
+	result, err := g.request(ctx, "QuerySmsSign", map[string]string{
+		"SignName": id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &SignStatus{
+		ID:     id,
+		Name:   id,
+		Status: aliyunReviewStatus(result["SignStatus"]),
+		Reason: stringOf(result["Reason"]),
+		Raw:    result,
+	}, nil
+}
+
+// DeleteSign 实现 TemplateManager 接口，通过 DeleteSmsSign 删除签名
+func (g *AliyunGateway) DeleteSign(ctx context.Context, id string) error {
+	_, err := g.request(ctx, "DeleteSmsSign", map[string]string{
+		"SignName": id,
+	})
+	return err
+}
+
+// aliyunReviewStatus 转换阿里云的审核状态：0 审核中，1 审核通过，2 审核失败
+func aliyunReviewStatus(v any) ReviewStatus {
+	switch stringOf(v) {
+	case "1":
+		return ReviewApproved
+	case "2":
+		return ReviewRejected
+	default:
+		return ReviewPending
+	}
 }
 
 // computeSignature 计算签名
@@ -118,13 +399,13 @@ func (g *AliyunGateway) computeSignature(accessKeySecret string, params map[stri
 	var canonicalizedQueryString strings.Builder
 	for _, k := range keys {
 		canonicalizedQueryString.WriteString("&")
-		canonicalizedQueryString.WriteString(url.QueryEscape(k))
+		canonicalizedQueryString.WriteString(percentEncode(k))
 		canonicalizedQueryString.WriteString("=")
-		canonicalizedQueryString.WriteString(url.QueryEscape(params[k]))
+		canonicalizedQueryString.WriteString(percentEncode(params[k]))
 	}
 
 	// 构建待签名字符串
-	stringToSign := "GET&%2F&" + url.QueryEscape(canonicalizedQueryString.String()[1:])
+	stringToSign := "GET&%2F&" + percentEncode(canonicalizedQueryString.String()[1:])
 
 	// 计算HMAC-SHA1签名
 	key := accessKeySecret + "&"
@@ -134,3 +415,11 @@ func (g *AliyunGateway) computeSignature(accessKeySecret string, params map[stri
 
 	return signature
 }
+
+// percentEncode 按阿里云签名要求进行 RFC 3986 编码，空格编码为 %20，* 编码为 %2A，~ 不编码
+func percentEncode(s string) string {
+	encoded := url.QueryEscape(s)
+	encoded = strings.ReplaceAll(encoded, "+", "%20")
+	encoded = strings.ReplaceAll(encoded, "*", "%2A")
+	return strings.ReplaceAll(encoded, "%7E", "~")
+}
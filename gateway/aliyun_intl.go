@@ -1,6 +1,7 @@
 package gateway
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha1"
 	"encoding/base64"
@@ -35,6 +36,25 @@ const (
 	AliyunIntlSuccessCode = "OK"
 )
 
+// AliyunIntlConfig 阿里云国际短信网关配置
+type AliyunIntlConfig struct {
+	CommonConfig
+
+	// AccessKey ID
+	AccessKeyID string `config:"access_key_id,required"`
+
+	// AccessKey Secret
+	AccessKeySecret string `config:"access_key_secret,required"`
+
+	// 短信签名，也可以在消息数据中通过 sign_name 指定
+	SignName string `config:"sign_name"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *AliyunIntlConfig) Validate() error {
+	return requireFields(c)
+}
+
 // AliyunIntlGateway 阿里云国际短信网关
 type AliyunIntlGateway struct {
 	*BaseGateway
@@ -47,8 +67,18 @@ func NewAliyunIntlGateway(config map[string]any) *AliyunIntlGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，阿里云国际只支持模板短信
+func (g *AliyunIntlGateway) Capabilities() Capabilities {
+	return Capabilities{International: true, Template: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *AliyunIntlGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *AliyunIntlGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
 	// 获取消息数据
 	data := msg.GetData()
 
@@ -99,8 +129,8 @@ func (g *AliyunIntlGateway) Send(to *message.PhoneNumber, msg *message.Message)
 	params["Signature"] = g.generateSign(params)
 
 	// 发送请求
-	result, err := g.get(AliyunIntlEndpointURL, params)
-	if err != nil {
+	result, err := withStatusBody(g.get(ctx, AliyunIntlEndpointURL, params))
+	if result == nil {
 		return nil, err
 	}
 
@@ -117,10 +147,11 @@ func (g *AliyunIntlGateway) Send(to *message.PhoneNumber, msg *message.Message)
 			_, _ = fmt.Sscanf(responseCode, "%d", &errorCode)
 		}
 
-		return result, fmt.Errorf("阿里云国际短信发送失败: [%d] %s", errorCode, errorMsg)
+		category, inferred := classify(aliyunErrorCategories, responseCode, errorMsg)
+		return result, newSendError(g.GetName(), category, inferred, responseCode, errorMsg, "阿里云国际短信发送失败: [%d] %s", errorCode, errorMsg).withStatus(err)
 	}
 
-	return result, nil
+	return result, err
 }
 
 // generateSign 生成签名
@@ -156,7 +187,7 @@ func (g *AliyunIntlGateway) generateSign(params map[string]string) string {
 }
 
 // get 发送 GET 请求
-func (g *AliyunIntlGateway) get(endpoint string, params map[string]string) (map[string]any, error) {
+func (g *AliyunIntlGateway) get(ctx context.Context, endpoint string, params map[string]string) (map[string]any, error) {
 	// 使用 BaseGateway 的 Get 方法发送请求
-	return g.Get(endpoint, params, nil)
+	return g.GetContext(ctx, endpoint, params, nil)
 }
@@ -1,11 +1,13 @@
 package gateway
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/json"
 	"fmt"
 	"net/url"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -28,6 +30,39 @@ const (
 	AliyunrestEndpointPartnerID = "EasySms"
 )
 
+// aliyunrestErrorCategories 淘宝开放平台公共错误码分类
+var aliyunrestErrorCategories = map[string]ErrorCategory{
+	"7":  CategoryRateLimited,
+	"11": CategoryAuth,
+	"15": CategoryServer,
+	"25": CategoryAuth,
+	"26": CategoryAuth,
+	"27": CategoryAuth,
+	"28": CategoryAuth,
+	"29": CategoryAuth,
+	"40": CategoryInvalidParam,
+	"41": CategoryInvalidParam,
+}
+
+// AliyunrestConfig 阿里云 REST API短信网关配置
+type AliyunrestConfig struct {
+	CommonConfig
+
+	// App Key
+	AppKey string `config:"app_key,required"`
+
+	// App Secret
+	AppSecretKey string `config:"app_secret_key,required"`
+
+	// 短信签名
+	SignName string `config:"sign_name,required"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *AliyunrestConfig) Validate() error {
+	return requireFields(c)
+}
+
 // AliyunrestGateway 阿里云 REST API 短信网关
 type AliyunrestGateway struct {
 	*BaseGateway
@@ -40,8 +75,18 @@ func NewAliyunrestGateway(config map[string]any) *AliyunrestGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，阿里云 REST 只支持模板短信
+func (g *AliyunrestGateway) Capabilities() Capabilities {
+	return Capabilities{International: true, Template: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *AliyunrestGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *AliyunrestGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
 	// 构建 URL 参数
 	urlParams := map[string]string{
 		"app_key":     g.GetConfigString("app_key"),
@@ -84,8 +129,8 @@ func (g *AliyunrestGateway) Send(to *message.PhoneNumber, msg *message.Message)
 	endpoint := g.getEndpointURL(urlParams)
 
 	// 发送请求
-	result, err := g.post(endpoint, params)
-	if err != nil {
+	result, err := withStatusBody(g.post(ctx, endpoint, params))
+	if result == nil {
 		return nil, err
 	}
 
@@ -101,10 +146,18 @@ func (g *AliyunrestGateway) Send(to *message.PhoneNumber, msg *message.Message)
 			errorMsg = msgValue
 		}
 
-		return result, fmt.Errorf("阿里云 REST API 短信发送失败: [%d] %s", code, errorMsg)
+		// 业务错误码在 sub_code 中，与阿里云错误码一致
+		category, inferred := classify(aliyunrestErrorCategories, strconv.Itoa(code), errorMsg)
+		if subCode, ok := errorResponse["sub_code"].(string); ok {
+			if c, ok := aliyunErrorCategories[subCode]; ok {
+				category, inferred = c, false
+			}
+		}
+
+		return result, newSendError(g.GetName(), category, inferred, strconv.Itoa(code), errorMsg, "阿里云 REST API 短信发送失败: [%d] %s", code, errorMsg).withStatus(err)
 	}
 
-	return result, nil
+	return result, err
 }
 
 // getEndpointURL 构建请求地址
@@ -149,9 +202,9 @@ func (g *AliyunrestGateway) generateSign(params map[string]string) string {
 }
 
 // post 发送 POST 请求
-func (g *AliyunrestGateway) post(endpoint string, params map[string]string) (map[string]any, error) {
+func (g *AliyunrestGateway) post(ctx context.Context, endpoint string, params map[string]string) (map[string]any, error) {
 	// 使用 BaseGateway 的 Post 方法发送请求
-	return g.Post(endpoint, params, map[string]string{
+	return g.PostContext(ctx, endpoint, params, map[string]string{
 		"Content-Type": "application/x-www-form-urlencoded",
 	})
 }
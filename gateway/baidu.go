@@ -1,12 +1,14 @@
 package gateway
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"net/url"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -27,6 +29,38 @@ const (
 	BaiduSuccessCode = 1000
 )
 
+// baiduErrorCategories 百度云短信返回码分类
+var baiduErrorCategories = map[string]ErrorCategory{
+	"400": CategoryInvalidParam,
+	"401": CategoryAuth,
+	"403": CategoryAuth,
+	"429": CategoryRateLimited,
+	"500": CategoryServer,
+	"503": CategoryServer,
+}
+
+// BaiduConfig 百度云短信网关配置
+type BaiduConfig struct {
+	CommonConfig
+
+	// Access Key
+	AK string `config:"ak,required"`
+
+	// Secret Key
+	SK string `config:"sk,required"`
+
+	// 签名调用 ID
+	InvokeID string `config:"invoke_id,required"`
+
+	// 接口域名
+	Domain string `config:"domain"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *BaiduConfig) Validate() error {
+	return requireFields(c)
+}
+
 // BaiduGateway 百度云短信网关
 type BaiduGateway struct {
 	*BaseGateway
@@ -39,8 +73,18 @@ func NewBaiduGateway(config map[string]any) *BaiduGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，百度云只支持模板短信和国内号码
+func (g *BaiduGateway) Capabilities() Capabilities {
+	return Capabilities{Template: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *BaiduGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *BaiduGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
 	// 构建请求参数
 	params := map[string]any{
 		"signatureId": g.GetConfigString("invoke_id"),
@@ -79,8 +123,8 @@ func (g *BaiduGateway) Send(to *message.PhoneNumber, msg *message.Message) (any,
 
 	// 发送请求
 	endpoint := g.buildEndpoint()
-	result, err := g.request("POST", endpoint, headers, params)
-	if err != nil {
+	result, err := withStatusBody(g.request(ctx, "POST", endpoint, headers, params))
+	if result == nil {
 		return nil, err
 	}
 
@@ -91,10 +135,11 @@ func (g *BaiduGateway) Send(to *message.PhoneNumber, msg *message.Message) (any,
 			errorMsg = msg
 		}
 
-		return result, fmt.Errorf("百度云短信发送失败: [%d] %s", int(code), errorMsg)
+		category, inferred := classify(baiduErrorCategories, strconv.Itoa(int(code)), errorMsg)
+		return result, newSendError(g.GetName(), category, inferred, strconv.Itoa(int(code)), errorMsg, "百度云短信发送失败: [%d] %s", int(code), errorMsg).withStatus(err)
 	}
 
-	return result, nil
+	return result, err
 }
 
 // buildEndpoint 构建请求地址
@@ -176,8 +221,8 @@ func (g *BaiduGateway) hmacSha256(key, data string) string {
 }
 
 // request 发送 HTTP 请求
-func (g *BaiduGateway) request(_, endpoint string, headers map[string]string, params map[string]any) (map[string]any, error) {
+func (g *BaiduGateway) request(ctx context.Context, _, endpoint string, headers map[string]string, params map[string]any) (map[string]any, error) {
 	// 使用 BaseGateway 的 PostJSON 方法发送请求
 	// 百度云 API 只使用 POST 方法
-	return g.PostJSON(endpoint, params, headers)
+	return g.PostJSONContext(ctx, endpoint, params, headers)
 }
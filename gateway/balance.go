@@ -0,0 +1,51 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 余额单位
+const (
+	// BalanceUnitMessage 按短信条数计费的账号，余额为剩余条数
+	BalanceUnitMessage = "message"
+	// BalanceUnitCNY 按金额计费的账号，余额单位为人民币元
+	BalanceUnitCNY = "CNY"
+)
+
+// Balance 表示服务商账号的余额
+type Balance struct {
+	// 网关名称
+	Gateway string
+
+	// 余额，单位见 Unit
+	Amount float64
+
+	// 余额单位，剩余条数为 BalanceUnitMessage，金额为 ISO 4217 货币代码
+	Unit string
+
+	// 服务商返回的原始数据
+	Raw any
+}
+
+// BalanceQuerier 定义了支持查询账号余额的网关接口
+type BalanceQuerier interface {
+	Gateway
+
+	// QueryBalance 查询账号余额
+	QueryBalance(ctx context.Context) (*Balance, error)
+}
+
+// parseAmount 解析服务商返回的余额，兼容数字和字符串
+func parseAmount(v any) (float64, error) {
+	switch val := v.(type) {
+	case float64:
+		return val, nil
+	case string:
+		return strconv.ParseFloat(strings.TrimSpace(val), 64)
+	default:
+		return 0, fmt.Errorf("unexpected balance value: %v", v)
+	}
+}
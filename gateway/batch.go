@@ -0,0 +1,85 @@
+package gateway
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/anhao/go-easy-sms/message"
+)
+
+// BatchGateway 定义了支持批量发送的网关接口
+// 实现该接口的网关可以通过服务商的批量接口在一次请求中向多个号码发送同一条短信
+type BatchGateway interface {
+	Gateway
+
+	// BatchSize 单次请求支持的最大号码数量
+	BatchSize() int
+
+	// SendBatch 使用指定上下文向多个号码发送同一条短信
+	// 请求整体失败时返回 error，否则按 to 的顺序返回每个号码的结果
+	SendBatch(ctx context.Context, to []*message.PhoneNumber, msg *message.Message) ([]BatchResult, error)
+}
+
+// BatchResult 是批量发送中单个号码的结果
+type BatchResult struct {
+	// 服务商返回的数据
+	Data any
+
+	// 发送失败的原因，成功时为 nil
+	Err error
+}
+
+// uniformBatchResults 为所有号码生成相同的结果，用于服务商只返回整体结果的批量接口
+func uniformBatchResults(n int, data any) []BatchResult {
+	results := make([]BatchResult, n)
+	for i := range results {
+		results[i].Data = data
+	}
+	return results
+}
+
+// matchBatchResults 按号码将服务商返回的逐条结果对应到 to 的顺序
+// phoneOf 返回单条结果中的号码，resultOf 将单条结果转换为 BatchResult
+func matchBatchResults(gateway string, to []*message.PhoneNumber, items []any, phoneOf func(item map[string]any) string, resultOf func(item map[string]any) BatchResult) []BatchResult {
+	results := make([]BatchResult, len(to))
+	used := make([]bool, len(items))
+
+	for i, phone := range to {
+		results[i].Err = NewSendError(gateway, CategoryUnknown, "", "no result returned for "+phone.String())
+		for j, item := range items {
+			itemMap, ok := item.(map[string]any)
+			if !ok || used[j] || !samePhone(phone, phoneOf(itemMap)) {
+				continue
+			}
+			used[j] = true
+			results[i] = resultOf(itemMap)
+			break
+		}
+	}
+
+	return results
+}
+
+// samePhone 判断服务商返回的号码是否为 to，服务商返回的号码可能带有国际区号
+func samePhone(to *message.PhoneNumber, phone string) bool {
+	phone = strings.TrimPrefix(phone, "+")
+	if phone == to.GetNumber() {
+		return true
+	}
+
+	iddCode := to.GetIDDCode()
+	if iddCode == 0 {
+		iddCode = 86
+	}
+	return strings.TrimPrefix(phone, "00") == strconv.Itoa(iddCode)+to.GetNumber()
+}
+
+// joinNumbers 使用逗号连接号码
+func joinNumbers(to []*message.PhoneNumber, format func(*message.PhoneNumber) string) string {
+	numbers := make([]string, len(to))
+	for i, phone := range to {
+		numbers[i] = format(phone)
+	}
+	return strings.Join(numbers, ",")
+}
@@ -0,0 +1,76 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/anhao/go-easy-sms/message"
+)
+
+// ErrUnsupported 网关无法发送该号码或消息
+var ErrUnsupported = errors.New("unsupported by gateway")
+
+// Capabilities 描述网关能够发送的号码和消息
+type Capabilities struct {
+	// 支持中国大陆以外的号码
+	International bool
+
+	// 支持直接发送短信内容
+	Content bool
+
+	// 支持使用模板 ID 发送
+	Template bool
+
+	// 支持语音消息（message.VoiceMessage）
+	Voice bool
+
+	// 支持批量接口（BatchGateway）
+	Batch bool
+
+	// 支持 ASCII 以外的字符，如中文和 emoji
+	Unicode bool
+
+	// 内容的最大字符数，为 0 时不限制
+	MaxLength int
+}
+
+// CapableGateway 定义了声明自身能力的网关接口
+// EasySms 发送前会跳过无法发送该号码或消息的网关，不发起请求；未实现该接口的网关总是会被尝试
+type CapableGateway interface {
+	Gateway
+
+	// Capabilities 返回网关的能力
+	Capabilities() Capabilities
+}
+
+// Supports 判断能否发送该号码和消息，不能发送时返回包装了 ErrUnsupported 的错误
+func (c Capabilities) Supports(to *message.PhoneNumber, msg *message.Message) error {
+	content := msg.GetContent()
+
+	switch {
+	case msg.GetType() == message.VoiceMessage && !c.Voice:
+		return fmt.Errorf("%w: voice message", ErrUnsupported)
+	case to != nil && !to.InChineseMainland() && !c.International:
+		return fmt.Errorf("%w: international number %s", ErrUnsupported, to)
+	case !c.Content && msg.GetTemplate() == "":
+		return fmt.Errorf("%w: message without template", ErrUnsupported)
+	case !c.Template && content == "":
+		return fmt.Errorf("%w: message without content", ErrUnsupported)
+	case !c.Unicode && !isASCII(content):
+		return fmt.Errorf("%w: non-ASCII content", ErrUnsupported)
+	case c.MaxLength > 0 && utf8.RuneCountInString(content) > c.MaxLength:
+		return fmt.Errorf("%w: content longer than %d characters", ErrUnsupported, c.MaxLength)
+	}
+	return nil
+}
+
+// isASCII 判断字符串是否只包含 ASCII 字符
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -1,6 +1,7 @@
 package gateway
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/anhao/go-easy-sms/message"
@@ -16,8 +17,79 @@ const (
 	ChuanglanChannelValidateCode = "smsbj1"
 	// ChuanglanChannelPromotionCode 会员营销渠道code
 	ChuanglanChannelPromotionCode = "smssh1"
+	// ChuanglanBalanceURL 余额查询，v1 版本 API 共用
+	ChuanglanBalanceURL = "https://smssh1.253.com/msg/balance/json"
 )
 
+// chuanglanErrorCategories 创蓝错误码分类，v1 版本 API 共用
+var chuanglanErrorCategories = map[string]ErrorCategory{
+	"101": CategoryAuth,
+	"102": CategoryAuth,
+	"103": CategoryRateLimited,
+	"104": CategoryServer,
+	"105": CategorySensitiveContent,
+	"106": CategoryInvalidParam,
+	"107": CategoryInvalidNumber,
+	"108": CategoryInvalidParam,
+	"109": CategoryInsufficientBalance,
+	"110": CategoryInvalidParam,
+	"113": CategoryInvalidParam,
+	"116": CategoryTemplate,
+	"117": CategoryAuth,
+	"118": CategoryAuth,
+	"119": CategoryAuth,
+	"123": CategoryInvalidParam,
+	"124": CategoryTemplate,
+	"125": CategorySensitiveContent,
+	"129": CategoryInvalidParam,
+	"130": CategoryInvalidParam,
+	"133": CategoryInvalidNumber,
+	"135": CategoryRateLimited,
+}
+
+// ChuanglanConfig 创蓝短信网关配置
+type ChuanglanConfig struct {
+	CommonConfig
+
+	// 账号
+	Account string `config:"account,required"`
+
+	// 密码
+	Password string `config:"password,required"`
+
+	// 通道，smsbj1（验证码）或 smssh1（营销），默认 smsbj1
+	Channel string `config:"channel"`
+
+	// 国际短信账号
+	IntelAccount string `config:"intel_account"`
+
+	// 国际短信密码
+	IntelPassword string `config:"intel_password"`
+
+	// 营销短信签名
+	Sign string `config:"sign"`
+
+	// 营销短信退订提示
+	Unsubscribe string `config:"unsubscribe"`
+
+	// 状态报告校验用户名
+	ReceiptReceiver string `config:"receipt_receiver"`
+
+	// 状态报告校验密码
+	ReceiptPassword string `config:"receipt_password"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *ChuanglanConfig) Validate() error {
+	if err := requireFields(c); err != nil {
+		return err
+	}
+	if c.Channel != "" && c.Channel != ChuanglanChannelValidateCode && c.Channel != ChuanglanChannelPromotionCode {
+		return fmt.Errorf("%w: unknown channel %q", ErrInvalidConfig, c.Channel)
+	}
+	return nil
+}
+
 // ChuanglanGateway 创蓝短信网关
 type ChuanglanGateway struct {
 	*BaseGateway
@@ -30,8 +102,18 @@ func NewChuanglanGateway(config map[string]any) *ChuanglanGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，创蓝只支持内容短信
+func (g *ChuanglanGateway) Capabilities() Capabilities {
+	return Capabilities{International: true, Content: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *ChuanglanGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *ChuanglanGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
 	iddCode := to.GetIDDCode()
 	if iddCode == 0 {
 		iddCode = 86
@@ -63,8 +145,8 @@ func (g *ChuanglanGateway) Send(to *message.PhoneNumber, msg *message.Message) (
 
 	// 发送请求
 	endpoint := g.buildEndpoint(iddCode)
-	result, err := g.postJSON(endpoint, params)
-	if err != nil {
+	result, err := withStatusBody(g.postJSON(ctx, endpoint, params))
+	if result == nil {
 		return nil, err
 	}
 
@@ -76,15 +158,17 @@ func (g *ChuanglanGateway) Send(to *message.PhoneNumber, msg *message.Message) (
 		}
 
 		errorCode := 0
-		if code, ok := result["code"].(string); ok {
+		code, _ := result["code"].(string)
+		if code != "" {
 			// 尝试将 code 转换为整数
 			_, _ = fmt.Sscanf(code, "%d", &errorCode)
 		}
 
-		return result, fmt.Errorf("创蓝短信发送失败: [%d] %s", errorCode, errorMsg)
+		category, inferred := classify(chuanglanErrorCategories, code, errorMsg)
+		return result, newSendError(g.GetName(), category, inferred, code, errorMsg, "创蓝短信发送失败: [%d] %s", errorCode, errorMsg).withStatus(err)
 	}
 
-	return result, nil
+	return result, err
 }
 
 // buildEndpoint 构建请求地址
@@ -138,9 +222,45 @@ func (g *ChuanglanGateway) wrapChannelContent(content string, iddCode int) strin
 }
 
 // postJSON 发送 JSON 请求
-func (g *ChuanglanGateway) postJSON(url string, data map[string]any) (map[string]any, error) {
+func (g *ChuanglanGateway) postJSON(ctx context.Context, url string, data map[string]any) (map[string]any, error) {
 	// 使用 BaseGateway 的 PostJSON 方法发送请求
-	return g.PostJSON(url, data, map[string]string{
+	return g.PostJSONContext(ctx, url, data, map[string]string{
+		"Content-Type": "application/json",
+	})
+}
+
+// QueryBalance 实现 BalanceQuerier 接口，查询国内短信剩余条数
+func (g *ChuanglanGateway) QueryBalance(ctx context.Context) (*Balance, error) {
+	return chuanglanBalance(ctx, g.BaseGateway)
+}
+
+// chuanglanBalance 通过 balance 接口查询国内短信剩余条数，创蓝和创蓝 v1 版本 API 共用
+func chuanglanBalance(ctx context.Context, g *BaseGateway) (*Balance, error) {
+	result, err := g.PostJSONContext(ctx, ChuanglanBalanceURL, map[string]any{
+		"account":  g.GetConfigString("account"),
+		"password": g.GetConfigString("password"),
+	}, map[string]string{
 		"Content-Type": "application/json",
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	if code, ok := result["code"].(string); !ok || code != "0" {
+		errorMsg, _ := result["errorMsg"].(string)
+		category, inferred := classify(chuanglanErrorCategories, code, errorMsg)
+		return nil, newSendError(g.GetName(), category, inferred, code, errorMsg, "创蓝余额查询失败: [%s] %s", code, errorMsg)
+	}
+
+	amount, err := parseAmount(result["balance"])
+	if err != nil {
+		return nil, fmt.Errorf("创蓝余额查询失败: %w", err)
+	}
+
+	return &Balance{
+		Gateway: g.GetName(),
+		Amount:  amount,
+		Unit:    BalanceUnitMessage,
+		Raw:     result,
+	}, nil
 }
@@ -1,6 +1,7 @@
 package gateway
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/anhao/go-easy-sms/message"
@@ -18,6 +19,40 @@ const (
 	Chuanglanv1ChannelVariableCode = "variable"
 )
 
+// Chuanglanv1Config 创蓝 v1短信网关配置
+type Chuanglanv1Config struct {
+	CommonConfig
+
+	// 账号
+	Account string `config:"account,required"`
+
+	// 密码
+	Password string `config:"password,required"`
+
+	// 通道，v1/send（普通）或 variable（变量），默认 v1/send
+	Channel string `config:"channel"`
+
+	// 国际短信账号
+	IntelAccount string `config:"intel_account"`
+
+	// 国际短信密码
+	IntelPassword string `config:"intel_password"`
+
+	// 是否需要状态报告
+	NeedStatus bool `config:"needstatus"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *Chuanglanv1Config) Validate() error {
+	if err := requireFields(c); err != nil {
+		return err
+	}
+	if c.Channel != "" && c.Channel != Chuanglanv1ChannelNormalCode && c.Channel != Chuanglanv1ChannelVariableCode {
+		return fmt.Errorf("%w: unknown channel %q", ErrInvalidConfig, c.Channel)
+	}
+	return nil
+}
+
 // Chuanglanv1Gateway 创蓝 v1 版本 API 短信网关
 type Chuanglanv1Gateway struct {
 	*BaseGateway
@@ -30,8 +65,18 @@ func NewChuanglanv1Gateway(config map[string]any) *Chuanglanv1Gateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，变量通道使用模板，其他通道使用内容
+func (g *Chuanglanv1Gateway) Capabilities() Capabilities {
+	return Capabilities{International: true, Content: true, Template: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *Chuanglanv1Gateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *Chuanglanv1Gateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
 	// 获取国际区号
 	iddCode := to.GetIDDCode()
 	if iddCode == 0 {
@@ -77,8 +122,8 @@ func (g *Chuanglanv1Gateway) Send(to *message.PhoneNumber, msg *message.Message)
 	endpoint := g.buildEndpoint(iddCode)
 
 	// 发送请求
-	result, err := g.postJSON(endpoint, params)
-	if err != nil {
+	result, err := withStatusBody(g.postJSON(ctx, endpoint, params))
+	if result == nil {
 		return nil, err
 	}
 
@@ -89,10 +134,11 @@ func (g *Chuanglanv1Gateway) Send(to *message.PhoneNumber, msg *message.Message)
 			errorMsg = msg
 		}
 
-		return result, fmt.Errorf("创蓝 v1 版本 API 短信发送失败: %s", errorMsg)
+		category, inferred := classify(chuanglanErrorCategories, code, errorMsg)
+		return result, newSendError(g.GetName(), category, inferred, code, errorMsg, "创蓝 v1 版本 API 短信发送失败: %s", errorMsg).withStatus(err)
 	}
 
-	return result, nil
+	return result, err
 }
 
 // buildEndpoint 构建请求地址
@@ -128,9 +174,14 @@ func (g *Chuanglanv1Gateway) wrapChannelContent(content string, _ int) string {
 }
 
 // postJSON 发送 JSON 请求
-func (g *Chuanglanv1Gateway) postJSON(url string, data map[string]any) (map[string]any, error) {
+func (g *Chuanglanv1Gateway) postJSON(ctx context.Context, url string, data map[string]any) (map[string]any, error) {
 	// 使用 BaseGateway 的 PostJSON 方法发送请求
-	return g.PostJSON(url, data, map[string]string{
+	return g.PostJSONContext(ctx, url, data, map[string]string{
 		"Content-Type": "application/json",
 	})
 }
+
+// QueryBalance 实现 BalanceQuerier 接口，查询国内短信剩余条数
+func (g *Chuanglanv1Gateway) QueryBalance(ctx context.Context) (*Balance, error) {
+	return chuanglanBalance(ctx, g.BaseGateway)
+}
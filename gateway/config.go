@@ -0,0 +1,233 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidConfig 网关配置无效，如配置项拼写错误、类型错误或缺少必填项
+var ErrInvalidConfig = errors.New("invalid gateway config")
+
+// DriverKey 网关配置中指定驱动名称的配置项
+const DriverKey = "driver"
+
+// GatewayConfig 定义了网关的类型化配置
+// 配置结构体的字段通过 config 标签对应配置项，标签带 required 选项的字段为必填项
+type GatewayConfig interface {
+	// Validate 校验配置
+	Validate() error
+}
+
+// CommonConfig 所有网关共用的配置项，嵌入在各网关的配置结构体中
+type CommonConfig struct {
+	// 驱动名称，即网关类型，如 aliyun，未设置时使用网关名称
+	Driver string `config:"driver"`
+
+	// 请求超时时间（秒）
+	Timeout float64 `config:"timeout"`
+
+	// 网关单独的重试策略，格式见 retry.FromConfig
+	Retry any `config:"retry"`
+
+	// 余额告警阈值
+	BalanceThreshold float64 `config:"balance_threshold"`
+
+	// 余额严重不足阈值
+	BalanceCritical float64 `config:"balance_critical"`
+}
+
+// configCreators 内置网关的配置结构体
+var configCreators = map[string]func() GatewayConfig{
+	"aliyun":         func() GatewayConfig { return &AliyunConfig{} },
+	"aliyun_intl":    func() GatewayConfig { return &AliyunIntlConfig{} },
+	"aliyunrest":     func() GatewayConfig { return &AliyunrestConfig{} },
+	"baidu":          func() GatewayConfig { return &BaiduConfig{} },
+	"chuanglan":      func() GatewayConfig { return &ChuanglanConfig{} },
+	"chuanglanv1":    func() GatewayConfig { return &Chuanglanv1Config{} },
+	"ctyun":          func() GatewayConfig { return &CtyunConfig{} },
+	"errorlog":       func() GatewayConfig { return &ErrorlogConfig{} },
+	"huaxin":         func() GatewayConfig { return &HuaxinConfig{} },
+	"huyi":           func() GatewayConfig { return &HuyiConfig{} },
+	"juhe":           func() GatewayConfig { return &JuheConfig{} },
+	"kingtto":        func() GatewayConfig { return &KingttoConfig{} },
+	"luosimao":       func() GatewayConfig { return &LuosimaoConfig{} },
+	"maap":           func() GatewayConfig { return &MaapConfig{} },
+	"moduyun":        func() GatewayConfig { return &ModuyunConfig{} },
+	"nowcn":          func() GatewayConfig { return &NowcnConfig{} },
+	"qcloud":         func() GatewayConfig { return &QcloudConfig{} },
+	"qiniu":          func() GatewayConfig { return &QiniuConfig{} },
+	"rongcloud":      func() GatewayConfig { return &RongcloudConfig{} },
+	"rongheyun":      func() GatewayConfig { return &RongheyunConfig{} },
+	"sendcloud":      func() GatewayConfig { return &SendcloudConfig{} },
+	"smsbao":         func() GatewayConfig { return &SmsbaoConfig{} },
+	"submail":        func() GatewayConfig { return &SubmailConfig{} },
+	"twilio":         func() GatewayConfig { return &TwilioConfig{} },
+	"ucloud":         func() GatewayConfig { return &UcloudConfig{} },
+	"ue35":           func() GatewayConfig { return &Ue35Config{} },
+	"volcengine":     func() GatewayConfig { return &VolcengineConfig{} },
+	"yidongmasblack": func() GatewayConfig { return &YidongmasblackConfig{} },
+	"yunpian":        func() GatewayConfig { return &YunpianConfig{} },
+	"yuntongxun":     func() GatewayConfig { return &YuntongxunConfig{} },
+	"yunxin":         func() GatewayConfig { return &YunxinConfig{} },
+	"yunzhixun":      func() GatewayConfig { return &YunzhixunConfig{} },
+}
+
+// ParseConfig 将网关的通用配置解码为类型化配置并校验
+// name 不是内置网关时返回 nil 和 nil，自定义网关的配置由其创建函数自行处理
+func ParseConfig(name string, raw map[string]any) (GatewayConfig, error) {
+	creator, ok := configCreators[name]
+	if !ok {
+		return nil, nil
+	}
+
+	cfg := creator()
+	if err := DecodeConfig(raw, cfg); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// DecodeConfig 将通用配置解码到 out 指向的配置结构体，未知的配置项和无法转换的值返回错误
+// 数字、字符串和布尔值之间按 GetConfigString、GetConfigInt 等方法的规则转换
+func DecodeConfig(raw map[string]any, out any) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config: expected a pointer to struct, got %T", out)
+	}
+
+	fields := make(map[string]reflect.Value)
+	collectFields(v.Elem(), fields)
+
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field, ok := fields[key]
+		if !ok {
+			return fmt.Errorf("%w: unknown key %q", ErrInvalidConfig, key)
+		}
+		if err := setField(field, raw[key]); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidConfig, key, err)
+		}
+	}
+	return nil
+}
+
+// requireFields 检查配置结构体中带 required 选项的字段是否已设置
+func requireFields(cfg any) error {
+	v := reflect.ValueOf(cfg).Elem()
+	t := v.Type()
+
+	var missing []string
+	for i := 0; i < t.NumField(); i++ {
+		name, opts, _ := strings.Cut(t.Field(i).Tag.Get("config"), ",")
+		if opts == "required" && v.Field(i).IsZero() {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing %s", ErrInvalidConfig, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// collectFields 收集结构体（包括嵌入结构体）中带 config 标签的字段
+func collectFields(v reflect.Value, fields map[string]reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			collectFields(v.Field(i), fields)
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("config"), ",")
+		if name != "" {
+			fields[name] = v.Field(i)
+		}
+	}
+}
+
+// setField 将配置值转换为字段的类型并赋值
+func setField(field reflect.Value, value any) error {
+	switch field.Kind() {
+	case reflect.String:
+		switch v := value.(type) {
+		case string:
+			field.SetString(v)
+		case int:
+			field.SetString(strconv.Itoa(v))
+		case float64:
+			field.SetString(strconv.FormatFloat(v, 'f', -1, 64))
+		default:
+			return fmt.Errorf("expected a string, got %T", value)
+		}
+	case reflect.Int:
+		switch v := value.(type) {
+		case int:
+			field.SetInt(int64(v))
+		case float64:
+			if v != float64(int64(v)) {
+				return fmt.Errorf("expected an integer, got %v", v)
+			}
+			field.SetInt(int64(v))
+		case string:
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("expected an integer, got %q", v)
+			}
+			field.SetInt(int64(n))
+		default:
+			return fmt.Errorf("expected an integer, got %T", value)
+		}
+	case reflect.Float64:
+		switch v := value.(type) {
+		case float64:
+			field.SetFloat(v)
+		case int:
+			field.SetFloat(float64(v))
+		case string:
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return fmt.Errorf("expected a number, got %q", v)
+			}
+			field.SetFloat(f)
+		default:
+			return fmt.Errorf("expected a number, got %T", value)
+		}
+	case reflect.Bool:
+		switch v := value.(type) {
+		case bool:
+			field.SetBool(v)
+		case int:
+			field.SetBool(v != 0)
+		case string:
+			switch v {
+			case "true", "1", "yes":
+				field.SetBool(true)
+			case "false", "0", "no":
+				field.SetBool(false)
+			default:
+				return fmt.Errorf("expected a boolean, got %q", v)
+			}
+		default:
+			return fmt.Errorf("expected a boolean, got %T", value)
+		}
+	case reflect.Interface:
+		if value != nil {
+			field.Set(reflect.ValueOf(value))
+		}
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package gateway
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/rand"
 	"crypto/sha256"
@@ -21,6 +22,28 @@ const (
 	CtyunEndpointHost = "https://sms-global.ctapi.ctyun.cn"
 )
 
+// CtyunConfig 天翼云短信网关配置
+type CtyunConfig struct {
+	CommonConfig
+
+	// Access Key
+	AccessKey string `config:"access_key,required"`
+
+	// Secret Key
+	SecretKey string `config:"secret_key,required"`
+
+	// 短信签名
+	SignName string `config:"sign_name,required"`
+
+	// 模板编码
+	TemplateCode string `config:"template_code,required"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *CtyunConfig) Validate() error {
+	return requireFields(c)
+}
+
 // CtyunGateway 天翼云短信网关
 type CtyunGateway struct {
 	*BaseGateway
@@ -33,8 +56,18 @@ func NewCtyunGateway(config map[string]any) *CtyunGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，天翼云使用配置中的模板，只支持国内号码
+func (g *CtyunGateway) Capabilities() Capabilities {
+	return Capabilities{Content: true, Template: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *CtyunGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *CtyunGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
 	data := msg.GetData()
 	endpoint := CtyunEndpointHost + "/sms/api/v1"
 
@@ -48,7 +81,7 @@ func (g *CtyunGateway) Send(to *message.PhoneNumber, msg *message.Message) (any,
 	}
 
 	// 执行请求
-	result, err := g.execute(endpoint, params)
+	result, err := g.execute(ctx, endpoint, params)
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +90,7 @@ func (g *CtyunGateway) Send(to *message.PhoneNumber, msg *message.Message) (any,
 }
 
 // execute 执行请求
-func (g *CtyunGateway) execute(url string, data map[string]any) (map[string]any, error) {
+func (g *CtyunGateway) execute(ctx context.Context, url string, data map[string]any) (map[string]any, error) {
 	// 生成请求 ID
 	uuid := g.generateUUID()
 
@@ -100,8 +133,8 @@ func (g *CtyunGateway) execute(url string, data map[string]any) (map[string]any,
 	}
 
 	// 发送请求
-	result, err := g.postJSON(url, data, headers)
-	if err != nil {
+	result, err := withStatusBody(g.postJSON(ctx, url, data, headers))
+	if result == nil {
 		return nil, err
 	}
 
@@ -112,10 +145,11 @@ func (g *CtyunGateway) execute(url string, data map[string]any) (map[string]any,
 			errorMsg = msg
 		}
 
-		return result, fmt.Errorf("天翼云短信发送失败: [%s] %s", code, errorMsg)
+		category, inferred := classifyMessage(errorMsg)
+		return result, newSendError(g.GetName(), category, inferred, code, errorMsg, "天翼云短信发送失败: [%s] %s", code, errorMsg).withStatus(err)
 	}
 
-	return result, nil
+	return result, err
 }
 
 // sha256HMAC 计算 HMAC-SHA256
@@ -148,7 +182,7 @@ func (g *CtyunGateway) generateUUID() string {
 }
 
 // postJSON 发送 JSON 请求
-func (g *CtyunGateway) postJSON(url string, data map[string]any, headers map[string]string) (map[string]any, error) {
+func (g *CtyunGateway) postJSON(ctx context.Context, url string, data map[string]any, headers map[string]string) (map[string]any, error) {
 	// 使用 BaseGateway 的 PostJSON 方法发送请求
-	return g.PostJSON(url, data, headers)
+	return g.PostJSONContext(ctx, url, data, headers)
 }
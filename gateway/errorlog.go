@@ -1,6 +1,7 @@
 package gateway
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -11,6 +12,19 @@ import (
 	"github.com/anhao/go-easy-sms/message"
 )
 
+// ErrorlogConfig 错误日志短信网关配置
+type ErrorlogConfig struct {
+	CommonConfig
+
+	// 日志文件路径，默认为临时目录下的 easy-sms-error.log
+	File string `config:"file"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *ErrorlogConfig) Validate() error {
+	return requireFields(c)
+}
+
 // ErrorlogGateway 是一个将短信内容记录到错误日志文件的网关
 type ErrorlogGateway struct {
 	*BaseGateway
@@ -25,6 +39,16 @@ func NewErrorlogGateway(config map[string]any) *ErrorlogGateway {
 
 // Send 将短信内容记录到错误日志文件
 func (g *ErrorlogGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *ErrorlogGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
+	// 上下文已结束时不再写入日志
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// 获取日志文件路径，如果未指定则使用默认路径
 	file, ok := g.Config["file"].(string)
 	if !ok || file == "" {
@@ -0,0 +1,269 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	nethttp "net/http"
+	"strings"
+
+	"github.com/anhao/go-easy-sms/http"
+)
+
+// ErrorCategory 表示发送失败的错误分类
+type ErrorCategory int
+
+const (
+	// CategoryUnknown 未知错误
+	CategoryUnknown ErrorCategory = iota
+	// CategoryInvalidNumber 手机号码无效或不支持该国家/地区
+	CategoryInvalidNumber
+	// CategoryBlacklisted 手机号码在黑名单或退订名单中
+	CategoryBlacklisted
+	// CategorySensitiveContent 内容含有敏感词或被判定为违规
+	CategorySensitiveContent
+	// CategoryTemplate 模板或签名不存在、未审核或与内容不匹配
+	CategoryTemplate
+	// CategoryInvalidParam 请求参数错误
+	CategoryInvalidParam
+	// CategoryInsufficientBalance 余额不足
+	CategoryInsufficientBalance
+	// CategoryRateLimited 触发频率限制
+	CategoryRateLimited
+	// CategoryAuth 鉴权失败、账号异常或没有权限
+	CategoryAuth
+	// CategoryNetwork 网络错误或请求超时
+	CategoryNetwork
+	// CategoryServer 服务商系统错误
+	CategoryServer
+)
+
+// String 实现 Stringer 接口
+func (c ErrorCategory) String() string {
+	switch c {
+	case CategoryInvalidNumber:
+		return "invalid_number"
+	case CategoryBlacklisted:
+		return "blacklisted"
+	case CategorySensitiveContent:
+		return "sensitive_content"
+	case CategoryTemplate:
+		return "template"
+	case CategoryInvalidParam:
+		return "invalid_param"
+	case CategoryInsufficientBalance:
+		return "insufficient_balance"
+	case CategoryRateLimited:
+		return "rate_limited"
+	case CategoryAuth:
+		return "auth"
+	case CategoryNetwork:
+		return "network"
+	case CategoryServer:
+		return "server"
+	default:
+		return "unknown"
+	}
+}
+
+// Retryable 判断该分类的错误是否可以重试
+// 只有网络错误、服务商系统错误和频率限制是暂时性的，其余错误重试也不会成功
+func (c ErrorCategory) Retryable() bool {
+	switch c {
+	case CategoryNetwork, CategoryServer, CategoryRateLimited:
+		return true
+	default:
+		return false
+	}
+}
+
+// SendError 是网关发送失败时返回的结构化错误
+// 可以通过 errors.As 获取：
+//
+//	var sendErr *gateway.SendError
+//	if errors.As(err, &sendErr) && sendErr.Category == gateway.CategoryInsufficientBalance {
+//		// 余额不足
+//	}
+type SendError struct {
+	// 网关名称
+	Gateway string
+
+	// 错误分类
+	Category ErrorCategory
+
+	// 服务商返回的错误码
+	Code string
+
+	// 服务商返回的错误信息
+	Message string
+
+	// 底层错误，如网络错误
+	Err error
+
+	// 分类是否根据错误信息中的关键字推断，服务商错误码未收录时为 true，推断的分类可能不准确
+	Inferred bool
+
+	// 错误描述，为空时根据其他字段生成
+	text string
+}
+
+// NewSendError 创建一个发送错误
+func NewSendError(gateway string, category ErrorCategory, code, message string) *SendError {
+	return &SendError{
+		Gateway:  gateway,
+		Category: category,
+		Code:     code,
+		Message:  message,
+	}
+}
+
+// Error 实现 error 接口
+func (e *SendError) Error() string {
+	if e.text != "" {
+		return e.text
+	}
+	if e.Code != "" {
+		return fmt.Sprintf("%s gateway error: [%s] %s", e.Gateway, e.Code, e.Message)
+	}
+	return fmt.Sprintf("%s gateway error: %s", e.Gateway, e.Message)
+}
+
+// Unwrap 返回底层错误
+func (e *SendError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable 判断错误是否可以重试
+func (e *SendError) Retryable() bool {
+	return e.Category.Retryable()
+}
+
+// WrapError 将网关返回的错误转换为 SendError，gateway 为网关实例的名称
+// 错误链中已有 SendError 时直接返回，其网关名称与 gateway 不同时（如配置了 driver 的网关实例）返回使用 gateway 的副本；
+// 否则根据错误类型判断分类
+func WrapError(gateway string, err error) *SendError {
+	if err == nil {
+		return nil
+	}
+
+	var sendErr *SendError
+	if errors.As(err, &sendErr) {
+		if gateway == "" || sendErr.Gateway == gateway {
+			return sendErr
+		}
+		return sendErr.withGateway(gateway)
+	}
+
+	return &SendError{
+		Gateway:  gateway,
+		Category: ClassifyError(err),
+		Message:  err.Error(),
+		Err:      err,
+		text:     err.Error(),
+	}
+}
+
+// withGateway 返回网关名称为 gateway 的副本，自定义的错误描述前加上网关名称
+func (e *SendError) withGateway(gateway string) *SendError {
+	clone := *e
+	clone.Gateway = gateway
+	if clone.text != "" {
+		clone.text = gateway + ": " + clone.text
+	}
+	return &clone
+}
+
+// withStatus 关联请求返回的 HTTP 状态错误，服务商错误码未收录时按状态码分类
+func (e *SendError) withStatus(err error) *SendError {
+	if err == nil {
+		return e
+	}
+	e.Err = err
+	if e.Inferred || e.Category == CategoryUnknown {
+		e.Category = ClassifyError(err)
+		e.Inferred = false
+	}
+	return e
+}
+
+// ClassifyError 判断错误的分类
+func ClassifyError(err error) ErrorCategory {
+	if err == nil {
+		return CategoryUnknown
+	}
+
+	var sendErr *SendError
+	if errors.As(err, &sendErr) {
+		return sendErr.Category
+	}
+
+	var statusErr *http.StatusError
+	if errors.As(err, &statusErr) {
+		if statusErr.StatusCode == nethttp.StatusTooManyRequests {
+			return CategoryRateLimited
+		}
+		return CategoryServer
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return CategoryNetwork
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return CategoryNetwork
+	}
+
+	return CategoryUnknown
+}
+
+// newSendError 创建发送错误，错误描述由 format 和 args 生成，与各网关原有的错误信息保持一致
+// inferred 表示 category 由错误信息推断得出
+func newSendError(gateway string, category ErrorCategory, inferred bool, code, message, format string, args ...any) *SendError {
+	err := NewSendError(gateway, category, code, message)
+	err.Inferred = inferred
+	err.text = fmt.Sprintf(format, args...)
+	return err
+}
+
+// classify 根据服务商错误码判断分类，错误码未收录时根据错误信息推断，第二个返回值表示分类是否为推断得出
+func classify(categories map[string]ErrorCategory, code, message string) (ErrorCategory, bool) {
+	if category, ok := categories[code]; ok {
+		return category, false
+	}
+	return classifyMessage(message)
+}
+
+// messageKeywords 错误信息关键字与分类的对应关系，按顺序匹配
+var messageKeywords = []struct {
+	category ErrorCategory
+	keywords []string
+}{
+	{CategoryInsufficientBalance, []string{"余额", "额度", "欠费", "条数不足", "balance", "insufficient", "amount_not_enough"}},
+	{CategoryBlacklisted, []string{"黑名单", "退订", "blacklist", "black list", "unsubscribed"}},
+	{CategorySensitiveContent, []string{"敏感", "违规", "违禁", "屏蔽", "sensitive", "content_illegal"}},
+	{CategoryRateLimited, []string{"频率", "频繁", "过快", "次数超", "超过限制", "throttl", "too many", "rate limit", "frequency"}},
+	{CategoryAuth, []string{"密码", "账号", "帐号", "账户", "帐户", "鉴权", "认证", "权限", "ip地址", "非法ip", "token", "apikey", "api_key", "access key", "accesskey", "secret", "unauthorized", "forbidden", "authenticat", "signaturedoesnotmatch"}},
+	{CategoryTemplate, []string{"模板", "模版", "签名", "template", "sign"}},
+	{CategoryInvalidNumber, []string{"号码", "手机号", "invalid mobile", "invalid phone"}},
+	{CategoryServer, []string{"系统忙", "系统繁忙", "系统错误", "内部错误", "服务繁忙", "busy", "internal", "system error"}},
+	{CategoryInvalidParam, []string{"参数", "格式", "长度", "parameter", "invalid"}},
+}
+
+// classifyMessage 根据错误信息中的关键字推断分类，第二个返回值表示是否匹配到关键字
+func classifyMessage(message string) (ErrorCategory, bool) {
+	message = strings.ToLower(message)
+	if message == "" {
+		return CategoryUnknown, false
+	}
+
+	for _, item := range messageKeywords {
+		for _, keyword := range item.keywords {
+			if strings.Contains(message, keyword) {
+				return item.category, true
+			}
+		}
+	}
+	return CategoryUnknown, false
+}
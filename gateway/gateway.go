@@ -2,6 +2,8 @@ package gateway
 
 import (
 	"context"
+	"errors"
+	"sort"
 	"strconv"
 	"time"
 
@@ -18,6 +20,27 @@ type Gateway interface {
 	Send(to *message.PhoneNumber, msg *message.Message) (any, error)
 }
 
+// ContextGateway 定义了支持上下文的短信网关接口
+// 实现该接口的网关会将 ctx 的取消、超时以及请求域的值传递到底层 HTTP 请求
+type ContextGateway interface {
+	Gateway
+
+	// SendContext 使用指定上下文发送短信
+	SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error)
+}
+
+// SendWithContext 使用指定上下文通过网关发送短信
+// 如果网关未实现 ContextGateway，则在 ctx 未结束时退化为调用 Send
+func SendWithContext(ctx context.Context, g Gateway, to *message.PhoneNumber, msg *message.Message) (any, error) {
+	if cg, ok := g.(ContextGateway); ok {
+		return cg.SendContext(ctx, to, msg)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return g.Send(to, msg)
+}
+
 // BaseGateway 提供了网关的基本实现
 type BaseGateway struct {
 	Name       string
@@ -27,24 +50,18 @@ type BaseGateway struct {
 
 // NewBaseGateway 创建一个新的基础网关
 func NewBaseGateway(name string, config map[string]any) *BaseGateway {
-	// 获取超时配置
-	timeout := 5.0
-	if timeoutVal, ok := config["timeout"]; ok {
-		if t, ok := timeoutVal.(float64); ok {
-			timeout = t
-		}
+	g := &BaseGateway{
+		Name:   name,
+		Config: config,
 	}
 
-	// 创建HTTP客户端
-	client := http.NewClient(
-		http.WithTimeout(time.Duration(timeout) * time.Second),
+	// 创建HTTP客户端，超时配置单位为秒
+	timeout := g.GetConfigFloat("timeout", 5.0)
+	g.httpClient = http.NewClient(
+		http.WithTimeout(time.Duration(timeout * float64(time.Second))),
 	)
 
-	return &BaseGateway{
-		Name:       name,
-		Config:     config,
-		httpClient: client,
-	}
+	return g
 }
 
 // GetName 获取网关名称
@@ -60,8 +77,16 @@ func (g *BaseGateway) GetConfig() map[string]any {
 // GetConfigString 获取字符串类型的配置项
 func (g *BaseGateway) GetConfigString(key string, defaultValue ...string) string {
 	if val, ok := g.Config[key]; ok {
-		if strVal, ok := val.(string); ok {
-			return strVal
+		switch v := val.(type) {
+		case string:
+			return v
+		case int:
+			// 配置文件中的数字，如 sdk_app_id: 1400000000
+			return strconv.Itoa(v)
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			return strconv.FormatBool(v)
 		}
 	}
 	if len(defaultValue) > 0 {
@@ -135,65 +160,79 @@ func (g *BaseGateway) GetConfigBool(key string, defaultValue ...bool) bool {
 
 // Get 发送GET请求
 func (g *BaseGateway) Get(endpoint string, params map[string]string, headers map[string]string) (map[string]any, error) {
-	// 创建上下文，设置超时
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(g.GetConfigFloat("timeout", 5.0))*time.Second)
+	return g.GetContext(context.Background(), endpoint, params, headers)
+}
+
+// GetContext 使用指定上下文发送GET请求
+func (g *BaseGateway) GetContext(ctx context.Context, endpoint string, params map[string]string, headers map[string]string) (map[string]any, error) {
+	// 在上下文的基础上设置超时
+	ctx, cancel := g.WithTimeout(ctx)
 	defer cancel()
 
 	// 发送请求
 	resp, err := g.httpClient.Get(ctx, endpoint, params, headers)
-	if err != nil {
-		return nil, err
-	}
-
-	// 解析JSON响应
-	result, err := http.ParseJSONResponse(resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return result, nil
+	return parseResponse(resp, err)
 }
 
 // Post 发送POST请求（表单数据）
 func (g *BaseGateway) Post(endpoint string, params map[string]string, headers map[string]string) (map[string]any, error) {
-	// 创建上下文，设置超时
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(g.GetConfigFloat("timeout", 5.0))*time.Second)
+	return g.PostContext(context.Background(), endpoint, params, headers)
+}
+
+// PostContext 使用指定上下文发送POST请求（表单数据）
+func (g *BaseGateway) PostContext(ctx context.Context, endpoint string, params map[string]string, headers map[string]string) (map[string]any, error) {
+	// 在上下文的基础上设置超时
+	ctx, cancel := g.WithTimeout(ctx)
 	defer cancel()
 
 	// 发送请求
 	resp, err := g.httpClient.Post(ctx, endpoint, params, headers)
-	if err != nil {
-		return nil, err
-	}
-
-	// 解析JSON响应
-	result, err := http.ParseJSONResponse(resp)
-	if err != nil {
-		return nil, err
-	}
-
-	return result, nil
+	return parseResponse(resp, err)
 }
 
 // PostJSON 发送POST请求（JSON数据）
 func (g *BaseGateway) PostJSON(endpoint string, params map[string]any, headers map[string]string) (map[string]any, error) {
-	// 创建上下文，设置超时
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(g.GetConfigFloat("timeout", 5.0))*time.Second)
+	return g.PostJSONContext(context.Background(), endpoint, params, headers)
+}
+
+// PostJSONContext 使用指定上下文发送POST请求（JSON数据）
+func (g *BaseGateway) PostJSONContext(ctx context.Context, endpoint string, params map[string]any, headers map[string]string) (map[string]any, error) {
+	// 在上下文的基础上设置超时
+	ctx, cancel := g.WithTimeout(ctx)
 	defer cancel()
 
 	// 发送请求
 	resp, err := g.httpClient.PostJSON(ctx, endpoint, params, headers)
+	return parseResponse(resp, err)
+}
+
+// parseResponse 解析JSON响应
+func parseResponse(resp []byte, err error) (map[string]any, error) {
 	if err != nil {
 		return nil, err
 	}
+	return http.ParseJSONResponse(resp)
+}
 
-	// 解析JSON响应
-	result, err := http.ParseJSONResponse(resp)
-	if err != nil {
-		return nil, err
+// withStatusBody 请求返回 *http.StatusError 且响应体为 JSON 时，将解析后的响应体与错误一起返回，
+// 网关优先根据响应体中的服务商错误码判断分类，没有服务商错误时再使用状态码
+func withStatusBody(result map[string]any, err error) (map[string]any, error) {
+	var statusErr *http.StatusError
+	if result == nil && errors.As(err, &statusErr) {
+		if body, parseErr := http.ParseJSONResponse(statusErr.Body); parseErr == nil {
+			return body, err
+		}
 	}
+	return result, err
+}
 
-	return result, nil
+// WithTimeout 基于父上下文创建带网关超时配置的上下文
+// 父上下文的截止时间更早时以父上下文为准
+func (g *BaseGateway) WithTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithTimeout(ctx, time.Duration(g.GetConfigFloat("timeout", 5.0)*float64(time.Second)))
 }
 
 // GetHTTPClient 获取原始 HTTP 客户端
@@ -201,3 +240,31 @@ func (g *BaseGateway) PostJSON(endpoint string, params map[string]any, headers m
 func (g *BaseGateway) GetHTTPClient() *http.Client {
 	return g.httpClient
 }
+
+// orderedValues 按位置传参的网关使用，将模板数据转换为有序的参数列表
+// 键均为数字（如 "0"、"1"）时按数值排序，否则按键名排序，保证参数顺序稳定
+func orderedValues(data map[string]any) []any {
+	keys := make([]string, 0, len(data))
+	numeric := true
+	for k := range data {
+		keys = append(keys, k)
+		if _, err := strconv.Atoi(k); err != nil {
+			numeric = false
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if numeric {
+			a, _ := strconv.Atoi(keys[i])
+			b, _ := strconv.Atoi(keys[j])
+			return a < b
+		}
+		return keys[i] < keys[j]
+	})
+
+	values := make([]any, len(keys))
+	for i, k := range keys {
+		values[i] = data[k]
+	}
+	return values
+}
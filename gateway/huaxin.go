@@ -1,6 +1,7 @@
 package gateway
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/anhao/go-easy-sms/message"
@@ -14,6 +15,42 @@ const (
 	HuaxinSuccessStatus = "Success"
 )
 
+// huaxinErrorCategories 华信错误信息分类，华信和凯信通的接口只返回错误信息，没有错误码，按文档中的错误信息分类
+var huaxinErrorCategories = map[string]ErrorCategory{
+	"用户名或密码不能为空":          CategoryAuth,
+	"用户名或密码错误":            CategoryAuth,
+	"该企业用户设置了ip限制":        CategoryAuth,
+	"短信号码不能为空":            CategoryInvalidParam,
+	"短信内容不能为空":            CategoryInvalidParam,
+	"发送内容包含sql注入字符":       CategoryInvalidParam,
+	"对不起，您当前要发送的量大于您当前余额": CategoryInsufficientBalance,
+}
+
+// HuaxinConfig 华信短信网关配置
+type HuaxinConfig struct {
+	CommonConfig
+
+	// 企业 ID
+	UserID string `config:"user_id,required"`
+
+	// 账号
+	Account string `config:"account,required"`
+
+	// 密码
+	Password string `config:"password,required"`
+
+	// 接口服务器地址
+	IP string `config:"ip,required"`
+
+	// 扩展号
+	ExtNo string `config:"ext_no"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *HuaxinConfig) Validate() error {
+	return requireFields(c)
+}
+
 // HuaxinGateway 华信短信网关
 type HuaxinGateway struct {
 	*BaseGateway
@@ -26,8 +63,18 @@ func NewHuaxinGateway(config map[string]any) *HuaxinGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，华信只支持内容短信和国内号码
+func (g *HuaxinGateway) Capabilities() Capabilities {
+	return Capabilities{Content: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *HuaxinGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *HuaxinGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
 	// 构建请求地址
 	endpoint := g.buildEndpoint(g.GetConfigString("ip"))
 
@@ -44,8 +91,8 @@ func (g *HuaxinGateway) Send(to *message.PhoneNumber, msg *message.Message) (any
 	}
 
 	// 发送请求
-	result, err := g.request(endpoint, params)
-	if err != nil {
+	result, err := withStatusBody(g.request(ctx, endpoint, params))
+	if result == nil {
 		return nil, err
 	}
 
@@ -56,10 +103,11 @@ func (g *HuaxinGateway) Send(to *message.PhoneNumber, msg *message.Message) (any
 			errorMsg = msg
 		}
 
-		return result, fmt.Errorf("华信短信发送失败: %s", errorMsg)
+		category, inferred := classify(huaxinErrorCategories, errorMsg, errorMsg)
+		return result, newSendError(g.GetName(), category, inferred, "", errorMsg, "华信短信发送失败: %s", errorMsg).withStatus(err)
 	}
 
-	return result, nil
+	return result, err
 }
 
 // buildEndpoint 构建请求地址
@@ -68,9 +116,9 @@ func (g *HuaxinGateway) buildEndpoint(ip string) string {
 }
 
 // request 发送 HTTP 请求
-func (g *HuaxinGateway) request(endpoint string, params map[string]string) (map[string]any, error) {
+func (g *HuaxinGateway) request(ctx context.Context, endpoint string, params map[string]string) (map[string]any, error) {
 	// 使用 BaseGateway 的 Post 方法发送请求
-	return g.Post(endpoint, params, map[string]string{
+	return g.PostContext(ctx, endpoint, params, map[string]string{
 		"Content-Type": "application/x-www-form-urlencoded",
 	})
 }
@@ -1,6 +1,7 @@
 package gateway
 
 import (
+	"context"
 	"crypto/md5"
 	"fmt"
 	"strconv"
@@ -13,12 +14,65 @@ import (
 const (
 	// HuyiEndpointURL 互亿无线短信 API 地址
 	HuyiEndpointURL = "http://106.ihuyi.com/webservice/sms.php?method=Submit"
+	// HuyiBalanceURL 互亿无线余额查询 API 地址
+	HuyiBalanceURL = "http://106.ihuyi.com/webservice/sms.php?method=GetNum"
 	// HuyiEndpointFormat 互亿无线短信 API 格式
 	HuyiEndpointFormat = "json"
 	// HuyiSuccessCode 互亿无线短信 API 成功状态码
 	HuyiSuccessCode = 2
 )
 
+// huyiErrorCategories 互亿无线错误码分类
+var huyiErrorCategories = map[string]ErrorCategory{
+	"0":     CategoryServer,
+	"400":   CategoryAuth,
+	"401":   CategoryAuth,
+	"402":   CategoryAuth,
+	"403":   CategoryInvalidNumber,
+	"4030":  CategoryBlacklisted,
+	"404":   CategoryInvalidParam,
+	"405":   CategoryAuth,
+	"4050":  CategoryAuth,
+	"4051":  CategoryInsufficientBalance,
+	"4052":  CategoryAuth,
+	"406":   CategoryInvalidNumber,
+	"407":   CategorySensitiveContent,
+	"4070":  CategoryTemplate,
+	"4071":  CategoryTemplate,
+	"4072":  CategoryTemplate,
+	"40722": CategoryInvalidParam,
+	"4073":  CategoryInvalidParam,
+	"4074":  CategoryInvalidParam,
+	"4075":  CategoryTemplate,
+	"408":   CategoryAuth,
+	"4080":  CategoryRateLimited,
+	"4081":  CategoryRateLimited,
+	"4082":  CategoryRateLimited,
+	"4083":  CategoryRateLimited,
+	"4084":  CategoryRateLimited,
+	"4085":  CategoryRateLimited,
+	"4086":  CategoryServer,
+}
+
+// HuyiConfig 互亿无线短信网关配置
+type HuyiConfig struct {
+	CommonConfig
+
+	// API ID
+	APIID string `config:"api_id,required"`
+
+	// API Key
+	APIKey string `config:"api_key,required"`
+
+	// 短信签名
+	Signature string `config:"signature"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *HuyiConfig) Validate() error {
+	return requireFields(c)
+}
+
 // HuyiGateway 互亿无线短信网关
 type HuyiGateway struct {
 	*BaseGateway
@@ -31,8 +85,18 @@ func NewHuyiGateway(config map[string]any) *HuyiGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，互亿无线只支持内容短信
+func (g *HuyiGateway) Capabilities() Capabilities {
+	return Capabilities{International: true, Content: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *HuyiGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *HuyiGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
 	// 获取电话号码
 	mobile := ""
 	if to.GetIDDCode() != 0 {
@@ -61,8 +125,8 @@ func (g *HuyiGateway) Send(to *message.PhoneNumber, msg *message.Message) (any,
 	params["password"] = g.generateSign(params)
 
 	// 发送请求
-	result, err := g.post(HuyiEndpointURL, params)
-	if err != nil {
+	result, err := withStatusBody(g.post(ctx, HuyiEndpointURL, params))
+	if result == nil {
 		return nil, err
 	}
 
@@ -78,10 +142,11 @@ func (g *HuyiGateway) Send(to *message.PhoneNumber, msg *message.Message) (any,
 			errorCode = int(code)
 		}
 
-		return result, fmt.Errorf("互亿无线短信发送失败: [%d] %s", errorCode, errorMsg)
+		category, inferred := classify(huyiErrorCategories, strconv.Itoa(errorCode), errorMsg)
+		return result, newSendError(g.GetName(), category, inferred, strconv.Itoa(errorCode), errorMsg, "互亿无线短信发送失败: [%d] %s", errorCode, errorMsg).withStatus(err)
 	}
 
-	return result, nil
+	return result, err
 }
 
 // generateSign 生成签名
@@ -96,9 +161,40 @@ func (g *HuyiGateway) generateSign(params map[string]string) string {
 }
 
 // post 发送 POST 请求
-func (g *HuyiGateway) post(endpoint string, params map[string]string) (map[string]any, error) {
+func (g *HuyiGateway) post(ctx context.Context, endpoint string, params map[string]string) (map[string]any, error) {
 	// 使用 BaseGateway 的 Post 方法发送请求
-	return g.Post(endpoint, params, map[string]string{
+	return g.PostContext(ctx, endpoint, params, map[string]string{
 		"Content-Type": "application/x-www-form-urlencoded",
 	})
 }
+
+// QueryBalance 实现 BalanceQuerier 接口，通过 GetNum 接口查询剩余条数
+func (g *HuyiGateway) QueryBalance(ctx context.Context) (*Balance, error) {
+	result, err := g.post(ctx, HuyiBalanceURL, map[string]string{
+		"account":  g.GetConfigString("api_id"),
+		"password": g.GetConfigString("api_key"),
+		"format":   HuyiEndpointFormat,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if code, ok := result["code"].(float64); !ok || int(code) != HuyiSuccessCode {
+		errorMsg, _ := result["msg"].(string)
+		errorCode := strconv.Itoa(int(code))
+		category, inferred := classify(huyiErrorCategories, errorCode, errorMsg)
+		return nil, newSendError(g.GetName(), category, inferred, errorCode, errorMsg, "互亿无线余额查询失败: [%s] %s", errorCode, errorMsg)
+	}
+
+	amount, err := parseAmount(result["num"])
+	if err != nil {
+		return nil, fmt.Errorf("互亿无线余额查询失败: %w", err)
+	}
+
+	return &Balance{
+		Gateway: g.GetName(),
+		Amount:  amount,
+		Unit:    BalanceUnitMessage,
+		Raw:     result,
+	}, nil
+}
@@ -1,8 +1,10 @@
 package gateway
 
 import (
+	"context"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/anhao/go-easy-sms/message"
@@ -16,6 +18,34 @@ const (
 	JuheEndpointFormat = "json"
 )
 
+// juheErrorCategories 聚合数据错误码分类
+var juheErrorCategories = map[string]ErrorCategory{
+	"205401": CategoryInvalidNumber,
+	"205402": CategoryTemplate,
+	"205403": CategoryNetwork,
+	"205404": CategoryServer,
+	"205405": CategoryRateLimited,
+	"205406": CategoryTemplate,
+	"205407": CategoryInsufficientBalance,
+	"10001":  CategoryAuth,
+	"10002":  CategoryAuth,
+	"10003":  CategoryAuth,
+	"10012":  CategoryRateLimited,
+}
+
+// JuheConfig 聚合数据短信网关配置
+type JuheConfig struct {
+	CommonConfig
+
+	// App Key
+	AppKey string `config:"app_key,required"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *JuheConfig) Validate() error {
+	return requireFields(c)
+}
+
 // JuheGateway 聚合数据短信网关
 type JuheGateway struct {
 	*BaseGateway
@@ -28,8 +58,18 @@ func NewJuheGateway(config map[string]any) *JuheGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，聚合数据只支持模板短信和国内号码
+func (g *JuheGateway) Capabilities() Capabilities {
+	return Capabilities{Template: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *JuheGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *JuheGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
 	// 构建请求参数
 	params := map[string]string{
 		"mobile":    to.GetNumber(),
@@ -40,8 +80,8 @@ func (g *JuheGateway) Send(to *message.PhoneNumber, msg *message.Message) (any,
 	}
 
 	// 发送请求
-	result, err := g.get(JuheEndpointURL, params)
-	if err != nil {
+	result, err := withStatusBody(g.get(ctx, JuheEndpointURL, params))
+	if result == nil {
 		return nil, err
 	}
 
@@ -52,10 +92,12 @@ func (g *JuheGateway) Send(to *message.PhoneNumber, msg *message.Message) (any,
 			errorMsg = reason
 		}
 
-		return result, fmt.Errorf("聚合数据短信发送失败: [%d] %s", int(errorCode), errorMsg)
+		code := strconv.Itoa(int(errorCode))
+		category, inferred := classify(juheErrorCategories, code, errorMsg)
+		return result, newSendError(g.GetName(), category, inferred, code, errorMsg, "聚合数据短信发送失败: [%d] %s", int(errorCode), errorMsg).withStatus(err)
 	}
 
-	return result, nil
+	return result, err
 }
 
 // formatTemplateVars 格式化模板变量
@@ -78,7 +120,7 @@ func (g *JuheGateway) formatTemplateVars(vars map[string]any) string {
 }
 
 // get 发送 GET 请求
-func (g *JuheGateway) get(endpoint string, params map[string]string) (map[string]any, error) {
+func (g *JuheGateway) get(ctx context.Context, endpoint string, params map[string]string) (map[string]any, error) {
 	// 使用 BaseGateway 的 Get 方法发送请求
-	return g.Get(endpoint, params, nil)
+	return g.GetContext(ctx, endpoint, params, nil)
 }
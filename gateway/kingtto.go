@@ -1,6 +1,7 @@
 package gateway
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/anhao/go-easy-sms/message"
@@ -16,6 +17,25 @@ const (
 	KingttoSuccessStatus = "Success"
 )
 
+// KingttoConfig 凯信通短信网关配置
+type KingttoConfig struct {
+	CommonConfig
+
+	// 企业 ID
+	UserID string `config:"userid,required"`
+
+	// 账号
+	Account string `config:"account,required"`
+
+	// 密码
+	Password string `config:"password,required"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *KingttoConfig) Validate() error {
+	return requireFields(c)
+}
+
 // KingttoGateway 凯信通短信网关
 type KingttoGateway struct {
 	*BaseGateway
@@ -28,8 +48,18 @@ func NewKingttoGateway(config map[string]any) *KingttoGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，凯信通只支持内容短信和国内号码
+func (g *KingttoGateway) Capabilities() Capabilities {
+	return Capabilities{Content: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *KingttoGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *KingttoGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
 	// 构建请求参数
 	params := map[string]string{
 		"action":   KingttoEndpointMethod,
@@ -41,8 +71,8 @@ func (g *KingttoGateway) Send(to *message.PhoneNumber, msg *message.Message) (an
 	}
 
 	// 发送请求
-	result, err := g.post(KingttoEndpointURL, params)
-	if err != nil {
+	result, err := withStatusBody(g.post(ctx, KingttoEndpointURL, params))
+	if result == nil {
 		return nil, err
 	}
 
@@ -59,16 +89,17 @@ func (g *KingttoGateway) Send(to *message.PhoneNumber, msg *message.Message) (an
 			_, _ = fmt.Sscanf(point, "%d", &remainPoint)
 		}
 
-		return result, fmt.Errorf("金坷垃短信发送失败: %s", errorMsg)
+		category, inferred := classify(huaxinErrorCategories, errorMsg, errorMsg)
+		return result, newSendError(g.GetName(), category, inferred, "", errorMsg, "金坷垃短信发送失败: %s", errorMsg).withStatus(err)
 	}
 
-	return result, nil
+	return result, err
 }
 
 // post 发送 POST 请求
-func (g *KingttoGateway) post(endpoint string, params map[string]string) (map[string]any, error) {
+func (g *KingttoGateway) post(ctx context.Context, endpoint string, params map[string]string) (map[string]any, error) {
 	// 使用 BaseGateway 的 Post 方法发送请求
-	return g.Post(endpoint, params, map[string]string{
+	return g.PostContext(ctx, endpoint, params, map[string]string{
 		"Content-Type": "application/x-www-form-urlencoded",
 	})
 }
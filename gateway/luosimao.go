@@ -1,8 +1,10 @@
 package gateway
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
+	"strconv"
 
 	"github.com/anhao/go-easy-sms/message"
 )
@@ -17,6 +19,35 @@ const (
 	LuosimaoEndpointFormat = "json"
 )
 
+// luosimaoErrorCategories 螺丝帽错误码分类
+var luosimaoErrorCategories = map[string]ErrorCategory{
+	"-10": CategoryAuth,
+	"-11": CategoryAuth,
+	"-20": CategoryInsufficientBalance,
+	"-30": CategoryInvalidParam,
+	"-31": CategorySensitiveContent,
+	"-32": CategoryTemplate,
+	"-33": CategoryInvalidParam,
+	"-34": CategoryTemplate,
+	"-40": CategoryInvalidNumber,
+	"-41": CategoryBlacklisted,
+	"-42": CategoryRateLimited,
+	"-50": CategoryAuth,
+}
+
+// LuosimaoConfig 螺丝帽短信网关配置
+type LuosimaoConfig struct {
+	CommonConfig
+
+	// API Key
+	APIKey string `config:"api_key,required"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *LuosimaoConfig) Validate() error {
+	return requireFields(c)
+}
+
 // LuosimaoGateway 螺丝帽短信网关
 type LuosimaoGateway struct {
 	*BaseGateway
@@ -29,8 +60,18 @@ func NewLuosimaoGateway(config map[string]any) *LuosimaoGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，螺丝帽只支持内容短信和国内号码
+func (g *LuosimaoGateway) Capabilities() Capabilities {
+	return Capabilities{Content: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *LuosimaoGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *LuosimaoGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
 	// 构建请求地址
 	endpoint := g.buildEndpoint("sms-api", "send")
 
@@ -46,8 +87,8 @@ func (g *LuosimaoGateway) Send(to *message.PhoneNumber, msg *message.Message) (a
 	}
 
 	// 发送请求
-	result, err := g.post(endpoint, params, headers)
-	if err != nil {
+	result, err := withStatusBody(g.post(ctx, endpoint, params, headers))
+	if result == nil {
 		return nil, err
 	}
 
@@ -58,10 +99,12 @@ func (g *LuosimaoGateway) Send(to *message.PhoneNumber, msg *message.Message) (a
 			errorMsg = msg
 		}
 
-		return result, fmt.Errorf("螺丝帽短信发送失败: [%d] %s", int(errorCode), errorMsg)
+		code := strconv.Itoa(int(errorCode))
+		category, inferred := classify(luosimaoErrorCategories, code, errorMsg)
+		return result, newSendError(g.GetName(), category, inferred, code, errorMsg, "螺丝帽短信发送失败: [%d] %s", int(errorCode), errorMsg).withStatus(err)
 	}
 
-	return result, nil
+	return result, err
 }
 
 // buildEndpoint 构建请求地址
@@ -70,7 +113,7 @@ func (g *LuosimaoGateway) buildEndpoint(typeStr, function string) string {
 }
 
 // post 发送 POST 请求
-func (g *LuosimaoGateway) post(endpoint string, params map[string]string, headers map[string]string) (map[string]any, error) {
+func (g *LuosimaoGateway) post(ctx context.Context, endpoint string, params map[string]string, headers map[string]string) (map[string]any, error) {
 	// 使用 BaseGateway 的 Post 方法发送请求
 	// 合并请求头
 	mergedHeaders := map[string]string{
@@ -79,5 +122,5 @@ func (g *LuosimaoGateway) post(endpoint string, params map[string]string, header
 	for k, v := range headers {
 		mergedHeaders[k] = v
 	}
-	return g.Post(endpoint, params, mergedHeaders)
+	return g.PostContext(ctx, endpoint, params, mergedHeaders)
 }
@@ -1,8 +1,10 @@
 package gateway
 
 import (
+	"context"
 	"crypto/md5"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/anhao/go-easy-sms/message"
@@ -14,6 +16,25 @@ const (
 	MaapEndpointURL = "http://rcsapi.wo.cn:8000/umcinterface/sendtempletmsg"
 )
 
+// MaapConfig MAAP短信网关配置
+type MaapConfig struct {
+	CommonConfig
+
+	// 商户编码
+	CPCode string `config:"cpcode,required"`
+
+	// 接口密钥
+	Key string `config:"key,required"`
+
+	// 扩展码
+	ExCode string `config:"excode"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *MaapConfig) Validate() error {
+	return requireFields(c)
+}
+
 // MaapGateway MAAP 短信网关
 type MaapGateway struct {
 	*BaseGateway
@@ -26,14 +47,24 @@ func NewMaapGateway(config map[string]any) *MaapGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，MAAP 只支持模板短信和国内号码
+func (g *MaapGateway) Capabilities() Capabilities {
+	return Capabilities{Template: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *MaapGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *MaapGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
 	// 获取消息数据
 	data := msg.GetData()
 
 	// 将数据转换为逗号分隔的字符串
 	dataValues := make([]string, 0, len(data))
-	for _, v := range data {
+	for _, v := range orderedValues(data) {
 		dataValues = append(dataValues, fmt.Sprintf("%v", v))
 	}
 	dataStr := strings.Join(dataValues, ",")
@@ -51,8 +82,8 @@ func (g *MaapGateway) Send(to *message.PhoneNumber, msg *message.Message) (any,
 	params["sign"] = g.generateSign(params)
 
 	// 发送请求
-	result, err := g.postJSON(MaapEndpointURL, params)
-	if err != nil {
+	result, err := withStatusBody(g.postJSON(ctx, MaapEndpointURL, params))
+	if result == nil {
 		return nil, err
 	}
 
@@ -63,10 +94,11 @@ func (g *MaapGateway) Send(to *message.PhoneNumber, msg *message.Message) (any,
 			errorMsg = msg
 		}
 
-		return result, fmt.Errorf("MAAP 短信发送失败: [%d] %s", int(resultCode), errorMsg)
+		category, inferred := classifyMessage(errorMsg)
+		return result, newSendError(g.GetName(), category, inferred, strconv.Itoa(int(resultCode)), errorMsg, "MAAP 短信发送失败: [%d] %s", int(resultCode), errorMsg).withStatus(err)
 	}
 
-	return result, nil
+	return result, err
 }
 
 // generateSign 生成签名
@@ -87,9 +119,9 @@ func (g *MaapGateway) generateSign(params map[string]any) string {
 }
 
 // postJSON 发送 JSON 请求
-func (g *MaapGateway) postJSON(endpoint string, params map[string]any) (map[string]any, error) {
+func (g *MaapGateway) postJSON(ctx context.Context, endpoint string, params map[string]any) (map[string]any, error) {
 	// 使用 BaseGateway 的 PostJSON 方法发送请求
-	return g.PostJSON(endpoint, params, map[string]string{
+	return g.PostJSONContext(ctx, endpoint, params, map[string]string{
 		"Content-Type": "application/json",
 	})
 }
@@ -1,10 +1,12 @@
 package gateway
 
 import (
+	"context"
 	"crypto/sha256"
 	"fmt"
 	"math/rand"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/anhao/go-easy-sms/message"
@@ -16,6 +18,57 @@ const (
 	ModuyunEndpointURL = "https://live.moduyun.com/sms/v2/sendsinglesms"
 )
 
+// moduyunErrorCategories 摩杜云错误码分类，摩杜云的接口与腾讯云短信旧版接口兼容，错误码相同
+var moduyunErrorCategories = map[string]ErrorCategory{
+	"1001": CategoryAuth,
+	"1002": CategorySensitiveContent,
+	"1003": CategoryInvalidParam,
+	"1004": CategoryInvalidParam,
+	"1006": CategoryAuth,
+	"1008": CategoryNetwork,
+	"1009": CategoryAuth,
+	"1012": CategoryTemplate,
+	"1013": CategoryRateLimited,
+	"1014": CategoryTemplate,
+	"1015": CategoryBlacklisted,
+	"1016": CategoryInvalidNumber,
+	"1017": CategoryInvalidParam,
+	"1019": CategoryAuth,
+	"1020": CategoryAuth,
+	"1022": CategoryRateLimited,
+	"1023": CategoryRateLimited,
+	"1024": CategoryRateLimited,
+	"1025": CategoryRateLimited,
+	"1026": CategoryRateLimited,
+	"1031": CategoryInsufficientBalance,
+	"1032": CategoryAuth,
+	"1033": CategoryInsufficientBalance,
+	"1036": CategoryTemplate,
+	"1045": CategoryInvalidNumber,
+}
+
+// ModuyunConfig 摩杜云短信网关配置
+type ModuyunConfig struct {
+	CommonConfig
+
+	// Access Key
+	AccessKey string `config:"accesskey,required"`
+
+	// Secret Key
+	SecretKey string `config:"secretkey,required"`
+
+	// 短信类型，0 为普通短信，1 为营销短信
+	Type int `config:"type"`
+
+	// 签名 ID
+	SignID string `config:"signId"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *ModuyunConfig) Validate() error {
+	return requireFields(c)
+}
+
 // ModuyunGateway 摩杜云短信网关
 type ModuyunGateway struct {
 	*BaseGateway
@@ -28,8 +81,18 @@ func NewModuyunGateway(config map[string]any) *ModuyunGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，摩杜云只支持模板短信
+func (g *ModuyunGateway) Capabilities() Capabilities {
+	return Capabilities{International: true, Template: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *ModuyunGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *ModuyunGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
 	// 生成随机数
 	// 使用 Go 1.20+ 推荐的方式生成随机数
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
@@ -52,10 +115,7 @@ func (g *ModuyunGateway) Send(to *message.PhoneNumber, msg *message.Message) (an
 
 	// 获取消息数据
 	data := msg.GetData()
-	dataValues := make([]any, 0, len(data))
-	for _, v := range data {
-		dataValues = append(dataValues, v)
-	}
+	dataValues := orderedValues(data)
 
 	// 构建请求参数
 	params := map[string]any{
@@ -76,8 +136,8 @@ func (g *ModuyunGateway) Send(to *message.PhoneNumber, msg *message.Message) (an
 	params["sig"] = g.generateSign(params, random)
 
 	// 发送请求
-	result, err := g.postJSON(g.getEndpointURL(urlParams), params)
-	if err != nil {
+	result, err := withStatusBody(g.postJSON(ctx, g.getEndpointURL(urlParams), params))
+	if result == nil {
 		return nil, err
 	}
 
@@ -88,10 +148,11 @@ func (g *ModuyunGateway) Send(to *message.PhoneNumber, msg *message.Message) (an
 			errorMsg = msg
 		}
 
-		return result, fmt.Errorf("摩杜云短信发送失败: [%d] %s", int(resultCode), errorMsg)
+		category, inferred := classify(moduyunErrorCategories, strconv.Itoa(int(resultCode)), errorMsg)
+		return result, newSendError(g.GetName(), category, inferred, strconv.Itoa(int(resultCode)), errorMsg, "摩杜云短信发送失败: [%d] %s", int(resultCode), errorMsg).withStatus(err)
 	}
 
-	return result, nil
+	return result, err
 }
 
 // getEndpointURL 构建请求地址
@@ -125,9 +186,9 @@ func (g *ModuyunGateway) generateSign(params map[string]any, random int) string
 }
 
 // postJSON 发送 JSON 请求
-func (g *ModuyunGateway) postJSON(endpoint string, params map[string]any) (map[string]any, error) {
+func (g *ModuyunGateway) postJSON(ctx context.Context, endpoint string, params map[string]any) (map[string]any, error) {
 	// 使用 BaseGateway 的 PostJSON 方法发送请求
-	return g.PostJSON(endpoint, params, map[string]string{
+	return g.PostJSONContext(ctx, endpoint, params, map[string]string{
 		"Content-Type": "application/json",
 	})
 }
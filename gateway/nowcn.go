@@ -1,7 +1,9 @@
 package gateway
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/anhao/go-easy-sms/message"
 )
@@ -14,6 +16,25 @@ const (
 	NowcnSuccessCode = 0
 )
 
+// NowcnConfig 现在云短信网关配置
+type NowcnConfig struct {
+	CommonConfig
+
+	// 用户 ID
+	Key string `config:"key,required"`
+
+	// 密钥
+	Secret string `config:"secret,required"`
+
+	// 短信通道
+	APIType string `config:"api_type,required"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *NowcnConfig) Validate() error {
+	return requireFields(c)
+}
+
 // NowcnGateway 现在云短信网关
 type NowcnGateway struct {
 	*BaseGateway
@@ -26,8 +47,18 @@ func NewNowcnGateway(config map[string]any) *NowcnGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，时代互联只支持内容短信和国内号码
+func (g *NowcnGateway) Capabilities() Capabilities {
+	return Capabilities{Content: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *NowcnGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *NowcnGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
 	// 检查配置
 	if g.GetConfigString("key") == "" {
 		return nil, fmt.Errorf("key not found")
@@ -43,8 +74,8 @@ func (g *NowcnGateway) Send(to *message.PhoneNumber, msg *message.Message) (any,
 	}
 
 	// 发送请求
-	result, err := g.get(NowcnEndpointURL, params)
-	if err != nil {
+	result, err := withStatusBody(g.get(ctx, NowcnEndpointURL, params))
+	if result == nil {
 		return nil, err
 	}
 
@@ -55,14 +86,15 @@ func (g *NowcnGateway) Send(to *message.PhoneNumber, msg *message.Message) (any,
 			errorMsg = msg
 		}
 
-		return result, fmt.Errorf("现在云短信发送失败: [%d] %s", int(resultCode), errorMsg)
+		category, inferred := classifyMessage(errorMsg)
+		return result, newSendError(g.GetName(), category, inferred, strconv.Itoa(int(resultCode)), errorMsg, "现在云短信发送失败: [%d] %s", int(resultCode), errorMsg).withStatus(err)
 	}
 
-	return result, nil
+	return result, err
 }
 
 // get 发送 GET 请求
-func (g *NowcnGateway) get(endpoint string, params map[string]string) (map[string]any, error) {
+func (g *NowcnGateway) get(ctx context.Context, endpoint string, params map[string]string) (map[string]any, error) {
 	// 使用 BaseGateway 的 Get 方法发送请求
-	return g.Get(endpoint, params, nil)
+	return g.GetContext(ctx, endpoint, params, nil)
 }
@@ -1,6 +1,7 @@
 package gateway
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
@@ -8,11 +9,41 @@ import (
 	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/anhao/go-easy-sms/message"
+	"github.com/anhao/go-easy-sms/receipt"
 )
 
+// QcloudConfig 腾讯云短信网关配置
+type QcloudConfig struct {
+	CommonConfig
+
+	// 短信应用 ID
+	SDKAppID string `config:"sdk_app_id,required"`
+
+	// SecretId
+	SecretID string `config:"secret_id,required"`
+
+	// SecretKey
+	SecretKey string `config:"secret_key,required"`
+
+	// 短信签名，也可以在消息数据中通过 sign_name 指定
+	SignName string `config:"sign_name"`
+
+	// 接口地址
+	Endpoint string `config:"endpoint"`
+
+	// 地域，默认 ap-guangzhou
+	Region string `config:"region"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *QcloudConfig) Validate() error {
+	return requireFields(c)
+}
+
 // QcloudGateway 腾讯云短信网关
 // 参考文档: https://cloud.tencent.com/document/api/382/55981
 type QcloudGateway struct {
@@ -21,12 +52,14 @@ type QcloudGateway struct {
 
 // 腾讯云短信 API 常量
 const (
-	QcloudEndpointURL     = "https://sms.tencentcloudapi.com"
-	QcloudEndpointService = "sms"
-	QcloudEndpointMethod  = "SendSms"
-	QcloudEndpointVersion = "2021-01-11"
-	QcloudEndpointRegion  = "ap-guangzhou"
-	QcloudEndpointFormat  = "json"
+	QcloudEndpointURL      = "https://sms.tencentcloudapi.com"
+	QcloudEndpointService  = "sms"
+	QcloudEndpointMethod   = "SendSms"
+	QcloudEndpointVersion  = "2021-01-11"
+	QcloudEndpointRegion   = "ap-guangzhou"
+	QcloudEndpointFormat   = "json"
+	QcloudBatchSize        = 200
+	QcloudPullStatusMethod = "PullSmsSendStatusByPhoneNumber"
 )
 
 // NewQcloudGateway 创建一个新的腾讯云短信网关
@@ -36,8 +69,77 @@ func NewQcloudGateway(config map[string]any) *QcloudGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，腾讯云只支持模板短信
+func (g *QcloudGateway) Capabilities() Capabilities {
+	return Capabilities{International: true, Template: true, Batch: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *QcloudGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *QcloudGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
+	result, err := g.send(ctx, []string{g.formatPhone(to)}, msg)
+	if err != nil {
+		if result == nil {
+			return nil, err
+		}
+		return result, err
+	}
+
+	// 检查发送状态
+	if response, ok := result["Response"].(map[string]any); ok {
+		if statusSet, ok := response["SendStatusSet"].([]any); ok {
+			for _, status := range statusSet {
+				if statusMap, ok := status.(map[string]any); ok {
+					if err := g.statusError(statusMap); err != nil {
+						return result, err
+					}
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// BatchSize 单次请求支持的最大号码数量
+func (g *QcloudGateway) BatchSize() int {
+	return QcloudBatchSize
+}
+
+// SendBatch 通过 PhoneNumberSet 批量发送短信，按 SendStatusSet 返回每个号码的结果
+func (g *QcloudGateway) SendBatch(ctx context.Context, to []*message.PhoneNumber, msg *message.Message) ([]BatchResult, error) {
+	phones := make([]string, len(to))
+	for i, phone := range to {
+		phones[i] = g.formatPhone(phone)
+	}
+
+	result, err := g.send(ctx, phones, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var statusSet []any
+	if response, ok := result["Response"].(map[string]any); ok {
+		statusSet, _ = response["SendStatusSet"].([]any)
+	}
+
+	return matchBatchResults(g.GetName(), to, statusSet,
+		func(status map[string]any) string {
+			phone, _ := status["PhoneNumber"].(string)
+			return phone
+		},
+		func(status map[string]any) BatchResult {
+			return BatchResult{Data: status, Err: g.statusError(status)}
+		},
+	), nil
+}
+
+// send 向多个号码发送短信，请求整体失败时返回错误
+func (g *QcloudGateway) send(ctx context.Context, phones []string, msg *message.Message) (map[string]any, error) {
 	// 获取消息数据
 	data := msg.GetData()
 
@@ -50,28 +152,27 @@ func (g *QcloudGateway) Send(to *message.PhoneNumber, msg *message.Message) (any
 	// 删除 sign_name 字段，避免作为模板参数发送
 	delete(data, "sign_name")
 
-	// 处理电话号码
-	phone := to.String()
-	if to.GetIDDCode() != 0 {
-		phone = to.GetUniversalNumber()
-	}
-
 	// 构建请求参数
 	params := map[string]any{
-		"PhoneNumberSet": []string{phone},
+		"PhoneNumberSet": phones,
 		"SmsSdkAppId":    g.GetConfigString("sdk_app_id"),
 		"SignName":       signName,
 		"TemplateId":     msg.GetTemplate(),
 		"TemplateParamSet": func() []string {
 			// 将模板参数转换为字符串数组
 			var values []string
-			for _, v := range data {
+			for _, v := range orderedValues(data) {
 				values = append(values, fmt.Sprintf("%v", v))
 			}
 			return values
 		}(),
 	}
 
+	return g.request(ctx, QcloudEndpointMethod, params)
+}
+
+// request 调用腾讯云短信 API，使用 TC3-HMAC-SHA256 签名，返回 Error 时返回错误
+func (g *QcloudGateway) request(ctx context.Context, action string, params map[string]any) (map[string]any, error) {
 	// 获取当前时间戳
 	timestamp := time.Now().Unix()
 
@@ -88,44 +189,271 @@ func (g *QcloudGateway) Send(to *message.PhoneNumber, msg *message.Message) (any
 		"Authorization":  g.generateSign(params, timestamp),
 		"Host":           host,
 		"Content-Type":   "application/json; charset=utf-8",
-		"X-TC-Action":    QcloudEndpointMethod,
+		"X-TC-Action":    action,
 		"X-TC-Region":    g.GetConfigString("region", QcloudEndpointRegion),
 		"X-TC-Timestamp": strconv.FormatInt(timestamp, 10),
 		"X-TC-Version":   QcloudEndpointVersion,
 	}
 
 	// 发送请求
-	result, err := g.PostJSON(endpoint, params, headers)
-	if err != nil {
+	result, err := withStatusBody(g.PostJSONContext(ctx, endpoint, params, headers))
+	if result == nil {
 		return nil, err
 	}
 
-	// 检查错误
-	if result != nil {
-		if response, ok := result["Response"].(map[string]any); ok {
-			// 检查错误信息
-			if errorInfo, ok := response["Error"].(map[string]any); ok {
-				code, _ := errorInfo["Code"].(string)
-				message, _ := errorInfo["Message"].(string)
-				return result, fmt.Errorf("腾讯云短信发送失败: [%s] %s", code, message)
+	// 检查错误信息
+	if response, ok := result["Response"].(map[string]any); ok {
+		if errorInfo, ok := response["Error"].(map[string]any); ok {
+			code, _ := errorInfo["Code"].(string)
+			message, _ := errorInfo["Message"].(string)
+			category, inferred := qcloudErrorCategory(code, message)
+			return result, newSendError(g.GetName(), category, inferred, code, message, "腾讯云短信发送失败: [%s] %s", code, message).withStatus(err)
+		}
+	}
+
+	return result, err
+}
+
+// MessageID 实现 StatusQuerier 接口，返回发送结果中第一个号码的 SerialNo
+func (g *QcloudGateway) MessageID(result any) string {
+	resultMap, ok := result.(map[string]any)
+	if !ok {
+		return ""
+	}
+	if response, ok := resultMap["Response"].(map[string]any); ok {
+		if statusSet, ok := response["SendStatusSet"].([]any); ok && len(statusSet) > 0 {
+			if statusMap, ok := statusSet[0].(map[string]any); ok {
+				return stringOf(statusMap["SerialNo"])
 			}
+		}
+	}
+	return ""
+}
 
-			// 检查发送状态
-			if statusSet, ok := response["SendStatusSet"].([]any); ok {
-				for _, status := range statusSet {
-					if statusMap, ok := status.(map[string]any); ok {
-						code, _ := statusMap["Code"].(string)
-						if code != "Ok" {
-							message, _ := statusMap["Message"].(string)
-							return result, fmt.Errorf("腾讯云短信发送失败: [%s] %s", code, message)
-						}
-					}
-				}
+// QueryStatus 实现 StatusQuerier 接口，通过 PullSmsSendStatusByPhoneNumber 拉取号码最近的状态报告并按 SerialNo 查找
+func (g *QcloudGateway) QueryStatus(ctx context.Context, messageID string, to *message.PhoneNumber) (*receipt.DeliveryReport, error) {
+	result, err := g.request(ctx, QcloudPullStatusMethod, map[string]any{
+		"BeginTime":   time.Now().Add(-StatusQueryLookback).Unix(),
+		"Offset":      0,
+		"Limit":       100,
+		"PhoneNumber": g.formatPhone(to),
+		"SmsSdkAppId": g.GetConfigString("sdk_app_id"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var statusSet []any
+	if response, ok := result["Response"].(map[string]any); ok {
+		statusSet, _ = response["PullSmsSendStatusSet"].([]any)
+	}
+
+	for _, status := range statusSet {
+		statusMap, ok := status.(map[string]any)
+		if !ok || stringOf(statusMap["SerialNo"]) != messageID {
+			continue
+		}
+
+		reportStatus := receipt.StatusDelivered
+		if stringOf(statusMap["ReportStatus"]) != "SUCCESS" {
+			reportStatus = receipt.StatusFailed
+		}
+
+		report := &receipt.DeliveryReport{
+			Gateway:      g.GetName(),
+			MessageID:    messageID,
+			Phone:        stringOf(statusMap["SubscriberNumber"]),
+			Status:       reportStatus,
+			ErrorMessage: stringOf(statusMap["Description"]),
+			Raw:          statusMap,
+		}
+		if receiveTime, ok := statusMap["UserReceiveTime"].(float64); ok && receiveTime > 0 {
+			report.ReportTime = time.Unix(int64(receiveTime), 0)
+		}
+		return report, nil
+	}
+
+	return pendingReport(g.GetName(), messageID, to), nil
+}
+
+// AddTemplate 实现 TemplateManager 接口，通过 AddSmsTemplate 申请模板，返回 TemplateId
+func (g *QcloudGateway) AddTemplate(ctx context.Context, req *TemplateRequest) (string, error) {
+	// 腾讯云短信类型：0 普通短信，1 营销短信
+	smsType := 0
+	if req.Type == TemplateMarketing {
+		smsType = 1
+	}
+
+	result, err := g.request(ctx, "AddSmsTemplate", map[string]any{
+		"TemplateName":    req.Name,
+		"TemplateContent": req.Content,
+		"SmsType":         smsType,
+		"International":   qcloudInternational(req.International),
+		"Remark":          req.Remark,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	response, _ := result["Response"].(map[string]any)
+	status, _ := response["AddTemplateStatus"].(map[string]any)
+	return stringOf(status["TemplateId"]), nil
+}
+
+// QueryTemplateStatus 实现 TemplateManager 接口，通过 DescribeSmsTemplateList 查询模板审核状态
+// 依次在国内和国际/港澳台模板中查找
+func (g *QcloudGateway) QueryTemplateStatus(ctx context.Context, id string) (*TemplateStatus, error) {
+	templateID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template id %q: %w", id, err)
+	}
+
+	status, err := g.describe(ctx, "DescribeSmsTemplateList", "TemplateIdSet", templateID, "DescribeTemplateStatusSet")
+	if err != nil {
+		return nil, err
+	}
+
+	return &TemplateStatus{
+		ID:     id,
+		Name:   stringOf(status["TemplateName"]),
+		Status: qcloudReviewStatus(status["StatusCode"]),
+		Reason: stringOf(status["ReviewReply"]),
+		Raw:    status,
+	}, nil
+}
+
+// DeleteTemplate 实现 TemplateManager 接口，通过 DeleteSmsTemplate 删除模板
+func (g *QcloudGateway) DeleteTemplate(ctx context.Context, id string) error {
+	templateID, err := strconv.Atoi(id)
+	if err != nil {
+		return fmt.Errorf("invalid template id %q: %w", id, err)
+	}
+
+	_, err = g.request(ctx, "DeleteSmsTemplate", map[string]any{
+		"TemplateId": templateID,
+	})
+	return err
+}
+
+// AddSign 实现 TemplateManager 接口，通过 AddSmsSign 申请签名，返回 SignId
+// Source 对应 SignType，DocumentType、SignPurpose、ProofImage 等参数通过 Extra 传递
+func (g *QcloudGateway) AddSign(ctx context.Context, req *SignRequest) (string, error) {
+	params := map[string]any{
+		"SignName":      req.Name,
+		"SignType":      req.Source,
+		"International": qcloudInternational(req.International),
+		"Remark":        req.Remark,
+	}
+	for k, v := range req.Extra {
+		params[k] = v
+	}
+
+	result, err := g.request(ctx, "AddSmsSign", params)
+	if err != nil {
+		return "", err
+	}
+
+	response, _ := result["Response"].(map[string]any)
+	status, _ := response["AddSignStatus"].(map[string]any)
+	return stringOf(status["SignId"]), nil
+}
+
+// QuerySignStatus 实现 TemplateManager 接口，通过 DescribeSmsSignList 查询签名审核状态
+// 依次在国内和国际/港澳台签名中查找
+func (g *QcloudGateway) QuerySignStatus(ctx context.Context, id string) (*SignStatus, error) {
+	signID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sign id %q: %w", id, err)
+	}
+
+	status, err := g.describe(ctx, "DescribeSmsSignList", "SignIdSet", signID, "DescribeSignListStatusSet")
+	if err != nil {
+		return nil, err
+	}
+
+	return &SignStatus{
+		ID:     id,
+		Name:   stringOf(status["SignName"]),
+		Status: qcloudReviewStatus(status["StatusCode"]),
+		Reason: stringOf(status["ReviewReply"]),
+		Raw:    status,
+	}, nil
+}
+
+// DeleteSign 实现 TemplateManager 接口，通过 DeleteSmsSign 删除签名
+func (g *QcloudGateway) DeleteSign(ctx context.Context, id string) error {
+	signID, err := strconv.Atoi(id)
+	if err != nil {
+		return fmt.Errorf("invalid sign id %q: %w", id, err)
+	}
+
+	_, err = g.request(ctx, "DeleteSmsSign", map[string]any{
+		"SignId": signID,
+	})
+	return err
+}
+
+// describe 查询单个模板或签名的状态，依次在国内和国际/港澳台中查找
+func (g *QcloudGateway) describe(ctx context.Context, action, idSetKey string, id int, statusSetKey string) (map[string]any, error) {
+	for _, international := range []int{0, 1} {
+		result, err := g.request(ctx, action, map[string]any{
+			idSetKey:        []int{id},
+			"International": international,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		response, _ := result["Response"].(map[string]any)
+		statusSet, _ := response[statusSetKey].([]any)
+		for _, status := range statusSet {
+			if statusMap, ok := status.(map[string]any); ok {
+				return statusMap, nil
 			}
 		}
 	}
 
-	return result, nil
+	return nil, NewSendError(g.GetName(), CategoryInvalidParam, "", fmt.Sprintf("no status returned for %d", id))
+}
+
+// qcloudInternational 转换是否为国际/港澳台短信：0 国内，1 国际/港澳台
+func qcloudInternational(international bool) int {
+	if international {
+		return 1
+	}
+	return 0
+}
+
+// qcloudReviewStatus 转换腾讯云的审核状态：0 审核通过，1 审核中，-1 审核未通过或已撤回
+func qcloudReviewStatus(v any) ReviewStatus {
+	switch stringOf(v) {
+	case "0":
+		return ReviewApproved
+	case "-1":
+		return ReviewRejected
+	default:
+		return ReviewPending
+	}
+}
+
+// formatPhone 处理电话号码，国际号码使用 E.164 格式
+func (g *QcloudGateway) formatPhone(to *message.PhoneNumber) string {
+	if to.GetIDDCode() != 0 {
+		return to.GetUniversalNumber()
+	}
+	return to.String()
+}
+
+// statusError 检查单个号码的发送状态
+func (g *QcloudGateway) statusError(status map[string]any) error {
+	code, _ := status["Code"].(string)
+	if code == "Ok" {
+		return nil
+	}
+
+	message, _ := status["Message"].(string)
+	category, inferred := qcloudErrorCategory(code, message)
+	return newSendError(g.GetName(), category, inferred, code, message, "腾讯云短信发送失败: [%s] %s", code, message)
 }
 
 // generateSign 生成签名
@@ -156,10 +484,11 @@ func (g *QcloudGateway) generateSign(params map[string]any, timestamp int64) str
 		g.sha256Hex(canonicalRequest)
 
 	// 计算签名
-	secretDate := g.hmacSha256("TC3"+secretKey, date)
+	// 派生密钥使用原始的 HMAC 结果，只有最终签名转换为十六进制
+	secretDate := g.hmacSha256([]byte("TC3"+secretKey), date)
 	secretService := g.hmacSha256(secretDate, QcloudEndpointService)
 	secretSigning := g.hmacSha256(secretService, "tc3_request")
-	signature := hex.EncodeToString([]byte(g.hmacSha256(secretSigning, stringToSign)))
+	signature := hex.EncodeToString(g.hmacSha256(secretSigning, stringToSign))
 
 	// 构建授权字符串
 	return "TC3-HMAC-SHA256" +
@@ -175,8 +504,44 @@ func (g *QcloudGateway) sha256Hex(s string) string {
 }
 
 // hmacSha256 计算 HMAC-SHA256
-func (g *QcloudGateway) hmacSha256(key, data string) string {
-	h := hmac.New(sha256.New, []byte(key))
+func (g *QcloudGateway) hmacSha256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
 	h.Write([]byte(data))
-	return hex.EncodeToString(h.Sum(nil))
+	return h.Sum(nil)
+}
+
+// qcloudErrorCategories 腾讯云错误码分类
+var qcloudErrorCategories = map[string]ErrorCategory{
+	"FailedOperation.ContainSensitiveWord":                            CategorySensitiveContent,
+	"FailedOperation.InsufficientBalanceInSmsPackage":                 CategoryInsufficientBalance,
+	"FailedOperation.PhoneNumberInBlacklist":                          CategoryBlacklisted,
+	"FailedOperation.PhoneNumberOnBlacklist":                          CategoryBlacklisted,
+	"FailedOperation.SignatureIncorrectOrUnapproved":                  CategoryTemplate,
+	"FailedOperation.TemplateIncorrectOrUnapproved":                   CategoryTemplate,
+	"FailedOperation.TemplateParamSetNotMatchApprovedTemplate":        CategoryTemplate,
+	"InvalidParameterValue.IncorrectPhoneNumber":                      CategoryInvalidNumber,
+	"InvalidParameterValue.ProhibitedUseUrlInTemplateParameter":       CategorySensitiveContent,
+	"InvalidParameterValue.TemplateParameterFormatError":              CategoryTemplate,
+	"UnsupportedOperation.UnsupportedRegion":                          CategoryInvalidNumber,
+	"UnsupportedOperation.ContainDomesticAndInternationalPhoneNumber": CategoryInvalidParam,
+}
+
+// qcloudErrorCategory 判断腾讯云错误码的分类，未收录的错误码按前缀推断，第二个返回值表示分类是否由错误信息推断得出
+func qcloudErrorCategory(code, message string) (ErrorCategory, bool) {
+	if category, ok := qcloudErrorCategories[code]; ok {
+		return category, false
+	}
+
+	switch {
+	case strings.HasPrefix(code, "AuthFailure"), strings.HasPrefix(code, "UnauthorizedOperation"):
+		return CategoryAuth, false
+	case strings.HasPrefix(code, "LimitExceeded"), strings.HasPrefix(code, "RequestLimitExceeded"):
+		return CategoryRateLimited, false
+	case strings.HasPrefix(code, "InternalError"):
+		return CategoryServer, false
+	case strings.HasPrefix(code, "InvalidParameter"), strings.HasPrefix(code, "MissingParameter"):
+		return CategoryInvalidParam, false
+	}
+
+	return classifyMessage(message)
 }
@@ -1,6 +1,7 @@
 package gateway
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha1"
 	"encoding/base64"
@@ -20,6 +21,22 @@ const (
 	QiniuEndpointVersion = "v1"
 )
 
+// QiniuConfig 七牛云短信网关配置
+type QiniuConfig struct {
+	CommonConfig
+
+	// Access Key
+	AccessKey string `config:"access_key,required"`
+
+	// Secret Key
+	SecretKey string `config:"secret_key,required"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *QiniuConfig) Validate() error {
+	return requireFields(c)
+}
+
 // QiniuGateway 七牛云短信网关
 type QiniuGateway struct {
 	*BaseGateway
@@ -32,8 +49,18 @@ func NewQiniuGateway(config map[string]any) *QiniuGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，七牛云只支持模板短信和国内号码
+func (g *QiniuGateway) Capabilities() Capabilities {
+	return Capabilities{Template: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *QiniuGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *QiniuGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
 	// 构建请求地址
 	endpoint := g.buildEndpoint("sms", "message/single")
 
@@ -66,8 +93,8 @@ func (g *QiniuGateway) Send(to *message.PhoneNumber, msg *message.Message) (any,
 	headers["Authorization"] = g.generateSign(endpoint, "POST", string(jsonParams), headers["Content-Type"])
 
 	// 发送请求
-	result, err := g.postJSON(endpoint, params, headers)
-	if err != nil {
+	result, err := withStatusBody(g.postJSON(ctx, endpoint, params, headers))
+	if result == nil {
 		return nil, err
 	}
 
@@ -78,10 +105,16 @@ func (g *QiniuGateway) Send(to *message.PhoneNumber, msg *message.Message) (any,
 			errorMsg = msg
 		}
 
-		return result, fmt.Errorf("七牛云短信发送失败: %s", errorMsg)
+		// 七牛云的错误码是描述性的字符串，错误信息无法识别时按错误码推断
+		category, inferred := classifyMessage(errorMsg)
+		if !inferred {
+			category, inferred = classifyMessage(errorCode)
+		}
+
+		return result, newSendError(g.GetName(), category, inferred, errorCode, errorMsg, "七牛云短信发送失败: %s", errorMsg).withStatus(err)
 	}
 
-	return result, nil
+	return result, err
 }
 
 // buildEndpoint 构建请求地址
@@ -141,7 +174,7 @@ func (g *QiniuGateway) base64UrlSafeEncode(data []byte) string {
 }
 
 // postJSON 发送 JSON 请求
-func (g *QiniuGateway) postJSON(endpoint string, params map[string]any, headers map[string]string) (map[string]any, error) {
+func (g *QiniuGateway) postJSON(ctx context.Context, endpoint string, params map[string]any, headers map[string]string) (map[string]any, error) {
 	// 使用 BaseGateway 的 PostJSON 方法发送请求
-	return g.PostJSON(endpoint, params, headers)
+	return g.PostJSONContext(ctx, endpoint, params, headers)
 }
@@ -1,12 +1,14 @@
 package gateway
 
 import (
+	"context"
 	"crypto/sha1"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -28,6 +30,30 @@ const (
 	RongcloudSuccessCode = 200
 )
 
+// rongcloudErrorCategories 融云错误码分类
+var rongcloudErrorCategories = map[string]ErrorCategory{
+	"1000": CategoryServer,
+	"1002": CategoryInvalidParam,
+	"1004": CategoryAuth,
+	"1008": CategoryRateLimited,
+}
+
+// RongcloudConfig 融云短信网关配置
+type RongcloudConfig struct {
+	CommonConfig
+
+	// App Key
+	AppKey string `config:"app_key,required"`
+
+	// App Secret
+	AppSecret string `config:"app_secret,required"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *RongcloudConfig) Validate() error {
+	return requireFields(c)
+}
+
 // RongcloudGateway 融云短信网关
 type RongcloudGateway struct {
 	*BaseGateway
@@ -40,8 +66,18 @@ func NewRongcloudGateway(config map[string]any) *RongcloudGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，融云只支持模板短信和国内号码
+func (g *RongcloudGateway) Capabilities() Capabilities {
+	return Capabilities{Template: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *RongcloudGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *RongcloudGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
 	// 获取消息数据
 	data := msg.GetData()
 
@@ -104,7 +140,7 @@ func (g *RongcloudGateway) Send(to *message.PhoneNumber, msg *message.Message) (
 	}
 
 	// 发送请求
-	result, err := g.post(endpoint, params, headers)
+	result, err := g.post(ctx, endpoint, params, headers)
 	if err != nil {
 		return nil, err
 	}
@@ -116,7 +152,8 @@ func (g *RongcloudGateway) Send(to *message.PhoneNumber, msg *message.Message) (
 			errorMsg = msg
 		}
 
-		return result, fmt.Errorf("融云短信发送失败: [%d] %s", int(resultCode), errorMsg)
+		category, inferred := classify(rongcloudErrorCategories, strconv.Itoa(int(resultCode)), errorMsg)
+		return result, newSendError(g.GetName(), category, inferred, strconv.Itoa(int(resultCode)), errorMsg, "融云短信发送失败: [%d] %s", int(resultCode), errorMsg)
 	}
 
 	return result, nil
@@ -139,7 +176,7 @@ func (g *RongcloudGateway) buildEndpoint(action string) string {
 }
 
 // post 发送 POST 请求
-func (g *RongcloudGateway) post(endpoint string, params map[string]string, headers map[string]string) (map[string]any, error) {
+func (g *RongcloudGateway) post(ctx context.Context, endpoint string, params map[string]string, headers map[string]string) (map[string]any, error) {
 	// 构建表单数据
 	form := url.Values{}
 	for k, v := range params {
@@ -147,7 +184,7 @@ func (g *RongcloudGateway) post(endpoint string, params map[string]string, heade
 	}
 
 	// 创建请求
-	req, err := http.NewRequest("POST", endpoint, strings.NewReader(form.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
 	if err != nil {
 		return nil, err
 	}
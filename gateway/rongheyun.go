@@ -1,8 +1,10 @@
 package gateway
 
 import (
+	"context"
 	"crypto/md5"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/anhao/go-easy-sms/message"
@@ -16,6 +18,33 @@ const (
 	RongheyunSuccessCode = 200
 )
 
+// rongheyunErrorCategories 融合云错误码分类
+var rongheyunErrorCategories = map[string]ErrorCategory{
+	"4001": CategoryAuth,
+	"4002": CategoryAuth,
+	"4003": CategoryInvalidParam,
+	"4025": CategoryTemplate,
+}
+
+// RongheyunConfig 融合云短信网关配置
+type RongheyunConfig struct {
+	CommonConfig
+
+	// 用户名
+	Username string `config:"username,required"`
+
+	// 密码
+	Password string `config:"password,required"`
+
+	// 短信签名
+	Signature string `config:"signature,required"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *RongheyunConfig) Validate() error {
+	return requireFields(c)
+}
+
 // RongheyunGateway 融合云短信网关
 type RongheyunGateway struct {
 	*BaseGateway
@@ -28,8 +57,18 @@ func NewRongheyunGateway(config map[string]any) *RongheyunGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，助通只支持模板短信和国内号码
+func (g *RongheyunGateway) Capabilities() Capabilities {
+	return Capabilities{Template: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *RongheyunGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *RongheyunGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
 	// 获取当前时间戳
 	tKey := time.Now().Unix()
 
@@ -54,8 +93,8 @@ func (g *RongheyunGateway) Send(to *message.PhoneNumber, msg *message.Message) (
 	}
 
 	// 发送请求
-	result, err := g.postJSON(RongheyunEndpointURL, params)
-	if err != nil {
+	result, err := withStatusBody(g.postJSON(ctx, RongheyunEndpointURL, params))
+	if result == nil {
 		return nil, err
 	}
 
@@ -66,10 +105,11 @@ func (g *RongheyunGateway) Send(to *message.PhoneNumber, msg *message.Message) (
 			errorMsg = msg
 		}
 
-		return result, fmt.Errorf("融合云短信发送失败: [%d] %s", int(resultCode), errorMsg)
+		category, inferred := classify(rongheyunErrorCategories, strconv.Itoa(int(resultCode)), errorMsg)
+		return result, newSendError(g.GetName(), category, inferred, strconv.Itoa(int(resultCode)), errorMsg, "融合云短信发送失败: [%d] %s", int(resultCode), errorMsg).withStatus(err)
 	}
 
-	return result, nil
+	return result, err
 }
 
 // generatePassword 生成密码
@@ -86,9 +126,9 @@ func (g *RongheyunGateway) generatePassword(tKey int64) string {
 }
 
 // postJSON 发送 JSON 请求
-func (g *RongheyunGateway) postJSON(endpoint string, params map[string]any) (map[string]any, error) {
+func (g *RongheyunGateway) postJSON(ctx context.Context, endpoint string, params map[string]any) (map[string]any, error) {
 	// 使用 BaseGateway 的 PostJSON 方法发送请求
-	return g.PostJSON(endpoint, params, map[string]string{
+	return g.PostJSONContext(ctx, endpoint, params, map[string]string{
 		"Content-Type": "application/json; charset=UTF-8",
 	})
 }
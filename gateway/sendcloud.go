@@ -1,6 +1,7 @@
 package gateway
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/json"
 	"fmt"
@@ -8,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -20,6 +22,33 @@ const (
 	SendcloudEndpointTemplate = "http://www.sendcloud.net/smsapi/%s"
 )
 
+// sendcloudErrorCategories SendCloud 错误码分类
+var sendcloudErrorCategories = map[string]ErrorCategory{
+	"411": CategoryInvalidParam,
+	"412": CategoryInvalidNumber,
+	"413": CategoryInvalidParam,
+	"500": CategoryServer,
+}
+
+// SendcloudConfig SendCloud短信网关配置
+type SendcloudConfig struct {
+	CommonConfig
+
+	// 短信用户
+	SmsUser string `config:"sms_user,required"`
+
+	// 短信密钥
+	SmsKey string `config:"sms_key,required"`
+
+	// 是否在请求中携带时间戳
+	Timestamp bool `config:"timestamp"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *SendcloudConfig) Validate() error {
+	return requireFields(c)
+}
+
 // SendcloudGateway SendCloud 短信网关
 type SendcloudGateway struct {
 	*BaseGateway
@@ -32,8 +61,18 @@ func NewSendcloudGateway(config map[string]any) *SendcloudGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，SendCloud 只支持模板短信
+func (g *SendcloudGateway) Capabilities() Capabilities {
+	return Capabilities{International: true, Template: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *SendcloudGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *SendcloudGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
 	// 构建请求参数
 	params := map[string]string{
 		"smsUser":    g.GetConfigString("sms_user"),
@@ -57,7 +96,7 @@ func (g *SendcloudGateway) Send(to *message.PhoneNumber, msg *message.Message) (
 	params["signature"] = g.sign(params)
 
 	// 发送请求
-	result, err := g.post(fmt.Sprintf(SendcloudEndpointTemplate, "send"), params)
+	result, err := g.post(ctx, fmt.Sprintf(SendcloudEndpointTemplate, "send"), params)
 	if err != nil {
 		return nil, err
 	}
@@ -74,7 +113,8 @@ func (g *SendcloudGateway) Send(to *message.PhoneNumber, msg *message.Message) (
 			statusCode = int(code)
 		}
 
-		return result, fmt.Errorf("SendCloud 短信发送失败: [%d] %s", statusCode, errorMsg)
+		category, inferred := classify(sendcloudErrorCategories, strconv.Itoa(statusCode), errorMsg)
+		return result, newSendError(g.GetName(), category, inferred, strconv.Itoa(statusCode), errorMsg, "SendCloud 短信发送失败: [%d] %s", statusCode, errorMsg)
 	}
 
 	return result, nil
@@ -128,7 +168,7 @@ func (g *SendcloudGateway) sign(params map[string]string) string {
 }
 
 // post 发送 POST 请求
-func (g *SendcloudGateway) post(endpoint string, params map[string]string) (map[string]any, error) {
+func (g *SendcloudGateway) post(ctx context.Context, endpoint string, params map[string]string) (map[string]any, error) {
 	// 构建表单数据
 	form := url.Values{}
 	for k, v := range params {
@@ -136,7 +176,7 @@ func (g *SendcloudGateway) post(endpoint string, params map[string]string) (map[
 	}
 
 	// 创建请求
-	req, err := http.NewRequest("POST", endpoint, strings.NewReader(form.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
 	if err != nil {
 		return nil, err
 	}
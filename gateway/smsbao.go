@@ -5,7 +5,7 @@ import (
 	"crypto/md5"
 	"encoding/hex"
 	"fmt"
-	"time"
+	"strings"
 
 	"github.com/anhao/go-easy-sms/message"
 )
@@ -18,6 +18,34 @@ const (
 	SmsbaoSuccessCode = "0"
 )
 
+// smsbaoErrorCategories 短信宝错误码分类，与 errorStatuses 对应
+var smsbaoErrorCategories = map[string]ErrorCategory{
+	"-1": CategoryInvalidParam,
+	"-2": CategoryServer,
+	"30": CategoryAuth,
+	"40": CategoryAuth,
+	"41": CategoryInsufficientBalance,
+	"42": CategoryAuth,
+	"43": CategoryAuth,
+	"50": CategorySensitiveContent,
+}
+
+// SmsbaoConfig 短信宝短信网关配置
+type SmsbaoConfig struct {
+	CommonConfig
+
+	// 用户名
+	User string `config:"user,required"`
+
+	// 密码
+	Password string `config:"password,required"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *SmsbaoConfig) Validate() error {
+	return requireFields(c)
+}
+
 // SmsbaoGateway 短信宝网关
 type SmsbaoGateway struct {
 	*BaseGateway
@@ -42,8 +70,18 @@ func NewSmsbaoGateway(config map[string]any) *SmsbaoGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，短信宝只支持内容短信，国际号码使用 wsms 接口
+func (g *SmsbaoGateway) Capabilities() Capabilities {
+	return Capabilities{International: true, Content: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *SmsbaoGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *SmsbaoGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
 	// 获取短信内容
 	content := msg.GetContent()
 
@@ -71,7 +109,7 @@ func (g *SmsbaoGateway) Send(to *message.PhoneNumber, msg *message.Message) (any
 	endpoint := g.buildEndpoint(action)
 
 	// 发送请求
-	result, err := g.get(endpoint, params)
+	result, err := g.get(ctx, endpoint, params)
 	if err != nil {
 		return nil, err
 	}
@@ -82,7 +120,8 @@ func (g *SmsbaoGateway) Send(to *message.PhoneNumber, msg *message.Message) (any
 		if errorMsg == "" {
 			errorMsg = "未知错误"
 		}
-		return result, fmt.Errorf("短信宝短信发送失败: [%s] %s", result, errorMsg)
+		category, inferred := classify(smsbaoErrorCategories, result, errorMsg)
+		return result, newSendError(g.GetName(), category, inferred, result, errorMsg, "短信宝短信发送失败: [%s] %s", result, errorMsg)
 	}
 
 	return result, nil
@@ -101,10 +140,10 @@ func (g *SmsbaoGateway) md5(str string) string {
 }
 
 // get 发送 GET 请求
-func (g *SmsbaoGateway) get(endpoint string, params map[string]string) (string, error) {
+func (g *SmsbaoGateway) get(ctx context.Context, endpoint string, params map[string]string) (string, error) {
 	// 使用 BaseGateway 的 Get 方法发送请求
-	// 创建上下文，设置超时
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(g.GetConfigFloat("timeout", 5.0))*time.Second)
+	// 在上下文的基础上设置超时
+	ctx, cancel := g.WithTimeout(ctx)
 	defer cancel()
 
 	// 使用 BaseGateway 的 HTTP 客户端发送请求
@@ -117,3 +156,41 @@ func (g *SmsbaoGateway) get(endpoint string, params map[string]string) (string,
 	// 短信宝返回的是字符串，不是 JSON，所以需要特殊处理
 	return string(body), nil
 }
+
+// QueryBalance 实现 BalanceQuerier 接口，通过 query 接口查询剩余条数
+// 成功时返回 "0\n已发送条数,剩余条数"
+func (g *SmsbaoGateway) QueryBalance(ctx context.Context) (*Balance, error) {
+	result, err := g.get(ctx, g.buildEndpoint("query"), map[string]string{
+		"u": g.GetConfigString("user"),
+		"p": g.md5(g.GetConfigString("password")),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.SplitN(strings.TrimSpace(result), "\n", 2)
+	if lines[0] != SmsbaoSuccessCode {
+		errorMsg := g.errorStatuses[lines[0]]
+		if errorMsg == "" {
+			errorMsg = "未知错误"
+		}
+		category, inferred := classify(smsbaoErrorCategories, lines[0], errorMsg)
+		return nil, newSendError(g.GetName(), category, inferred, lines[0], errorMsg, "短信宝余额查询失败: [%s] %s", lines[0], errorMsg)
+	}
+
+	counts := []string{""}
+	if len(lines) > 1 {
+		counts = strings.Split(lines[1], ",")
+	}
+	amount, err := parseAmount(counts[len(counts)-1])
+	if err != nil {
+		return nil, fmt.Errorf("短信宝余额查询失败: %w", err)
+	}
+
+	return &Balance{
+		Gateway: g.GetName(),
+		Amount:  amount,
+		Unit:    BalanceUnitMessage,
+		Raw:     result,
+	}, nil
+}
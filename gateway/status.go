@@ -0,0 +1,59 @@
+package gateway
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/anhao/go-easy-sms/message"
+	"github.com/anhao/go-easy-sms/receipt"
+)
+
+// StatusQueryLookback 查询送达状态时向前查找的时间范围
+const StatusQueryLookback = 48 * time.Hour
+
+// StatusQuerier 定义了支持主动查询送达状态的网关接口
+// 适用于无法配置状态报告回调的账号，由调用方轮询服务商的查询接口
+type StatusQuerier interface {
+	Gateway
+
+	// MessageID 从发送结果中获取服务商返回的消息 ID，无法获取时返回空字符串
+	MessageID(result any) string
+
+	// QueryStatus 查询消息的送达状态
+	// 服务商尚未返回状态报告时返回状态为 receipt.StatusPending 的报告
+	QueryStatus(ctx context.Context, messageID string, to *message.PhoneNumber) (*receipt.DeliveryReport, error)
+}
+
+// pendingReport 生成尚未送达的状态报告
+func pendingReport(gateway, messageID string, to *message.PhoneNumber) *receipt.DeliveryReport {
+	return &receipt.DeliveryReport{
+		Gateway:   gateway,
+		MessageID: messageID,
+		Phone:     to.GetNumber(),
+		Status:    receipt.StatusPending,
+	}
+}
+
+// stringOf 将服务商返回的值转换为字符串，数字不使用科学计数法
+func stringOf(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+// parseChinaTime 按 layout 解析北京时间，解析失败时返回零值
+func parseChinaTime(layout, value string) time.Time {
+	t, err := time.ParseInLocation(layout, value, receipt.ChinaZone)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
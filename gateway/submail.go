@@ -1,8 +1,10 @@
 package gateway
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/anhao/go-easy-sms/message"
 )
@@ -17,6 +19,40 @@ const (
 	SubmailSuccessStatus = "success"
 )
 
+// submailErrorCategories 赛邮云错误码分类
+var submailErrorCategories = map[string]ErrorCategory{
+	"101": CategoryAuth,
+	"102": CategoryAuth,
+	"103": CategoryAuth,
+	"104": CategoryAuth,
+	"105": CategoryAuth,
+	"106": CategoryAuth,
+	"109": CategoryAuth,
+	"115": CategoryAuth,
+}
+
+// SubmailConfig 赛邮云短信网关配置
+type SubmailConfig struct {
+	CommonConfig
+
+	// App ID
+	AppID string `config:"app_id,required"`
+
+	// App Key
+	AppKey string `config:"app_key,required"`
+
+	// 默认模板项目标识
+	Project string `config:"project"`
+
+	// 状态推送签名密钥
+	SubhookKey string `config:"subhook_key"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *SubmailConfig) Validate() error {
+	return requireFields(c)
+}
+
 // SubmailGateway 赛邮云短信网关
 type SubmailGateway struct {
 	*BaseGateway
@@ -29,8 +65,18 @@ func NewSubmailGateway(config map[string]any) *SubmailGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，赛邮云支持内容和模板短信
+func (g *SubmailGateway) Capabilities() Capabilities {
+	return Capabilities{International: true, Content: true, Template: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *SubmailGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *SubmailGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
 	// 判断是否使用内容发送
 	isContent := msg.GetContent() != ""
 	var endpoint string
@@ -90,8 +136,8 @@ func (g *SubmailGateway) Send(to *message.PhoneNumber, msg *message.Message) (an
 	}
 
 	// 发送请求
-	result, err := g.request(endpoint, params)
-	if err != nil {
+	result, err := withStatusBody(g.request(ctx, endpoint, params))
+	if result == nil {
 		return nil, err
 	}
 
@@ -107,10 +153,11 @@ func (g *SubmailGateway) Send(to *message.PhoneNumber, msg *message.Message) (an
 			errorCode = int(code)
 		}
 
-		return result, fmt.Errorf("赛邮云短信发送失败: [%d] %s", errorCode, errorMsg)
+		category, inferred := classify(submailErrorCategories, strconv.Itoa(errorCode), errorMsg)
+		return result, newSendError(g.GetName(), category, inferred, strconv.Itoa(errorCode), errorMsg, "赛邮云短信发送失败: [%d] %s", errorCode, errorMsg).withStatus(err)
 	}
 
-	return result, nil
+	return result, err
 }
 
 // buildEndpoint 构建请求地址
@@ -125,9 +172,49 @@ func (g *SubmailGateway) inChineseMainland(to *message.PhoneNumber) bool {
 }
 
 // request 发送 HTTP 请求
-func (g *SubmailGateway) request(endpoint string, params map[string]string) (map[string]any, error) {
+func (g *SubmailGateway) request(ctx context.Context, endpoint string, params map[string]string) (map[string]any, error) {
 	// 使用 BaseGateway 的 Post 方法发送请求
-	return g.Post(endpoint, params, map[string]string{
+	return g.PostContext(ctx, endpoint, params, map[string]string{
 		"Content-Type": "application/x-www-form-urlencoded",
 	})
 }
+
+// QueryBalance 实现 BalanceQuerier 接口，通过 balance/sms 接口查询剩余条数
+// 余额为通用短信和事务类短信条数之和
+func (g *SubmailGateway) QueryBalance(ctx context.Context) (*Balance, error) {
+	result, err := g.request(ctx, g.buildEndpoint("balance/sms"), map[string]string{
+		"appid":     g.GetConfigString("app_id"),
+		"signature": g.GetConfigString("app_key"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if status, ok := result["status"].(string); !ok || status != SubmailSuccessStatus {
+		errorMsg, _ := result["msg"].(string)
+		errorCode := 0
+		if code, ok := result["code"].(float64); ok {
+			errorCode = int(code)
+		}
+		category, inferred := classify(submailErrorCategories, strconv.Itoa(errorCode), errorMsg)
+		return nil, newSendError(g.GetName(), category, inferred, strconv.Itoa(errorCode), errorMsg, "赛邮云余额查询失败: [%d] %s", errorCode, errorMsg)
+	}
+
+	var amount float64
+	for _, key := range []string{"balance", "transactional_balance"} {
+		if v, ok := result[key]; ok {
+			n, err := parseAmount(v)
+			if err != nil {
+				return nil, fmt.Errorf("赛邮云余额查询失败: %w", err)
+			}
+			amount += n
+		}
+	}
+
+	return &Balance{
+		Gateway: g.GetName(),
+		Amount:  amount,
+		Unit:    BalanceUnitMessage,
+		Raw:     result,
+	}, nil
+}
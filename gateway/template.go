@@ -0,0 +1,123 @@
+package gateway
+
+import "context"
+
+// TemplateType 表示短信模板的类型
+type TemplateType int
+
+const (
+	// TemplateVerification 验证码
+	TemplateVerification TemplateType = iota
+	// TemplateNotification 短信通知
+	TemplateNotification
+	// TemplateMarketing 推广短信
+	TemplateMarketing
+)
+
+// ReviewStatus 表示模板或签名的审核状态
+type ReviewStatus string
+
+const (
+	// ReviewPending 审核中
+	ReviewPending ReviewStatus = "pending"
+	// ReviewApproved 审核通过
+	ReviewApproved ReviewStatus = "approved"
+	// ReviewRejected 审核未通过
+	ReviewRejected ReviewStatus = "rejected"
+)
+
+// TemplateRequest 是申请短信模板的参数
+type TemplateRequest struct {
+	// 模板名称
+	Name string
+
+	// 模板内容，变量格式以服务商要求为准
+	Content string
+
+	// 模板类型
+	Type TemplateType
+
+	// 是否为国际/港澳台短信模板
+	International bool
+
+	// 申请说明
+	Remark string
+}
+
+// SignRequest 是申请短信签名的参数
+type SignRequest struct {
+	// 签名名称
+	Name string
+
+	// 签名来源（阿里云 SignSource、腾讯云 SignType），取值以服务商文档为准
+	Source int
+
+	// 是否为国际/港澳台短信签名
+	International bool
+
+	// 申请说明
+	Remark string
+
+	// 服务商特有的参数，如腾讯云的 DocumentType、SignPurpose、ProofImage
+	Extra map[string]any
+}
+
+// TemplateStatus 是短信模板的审核状态
+type TemplateStatus struct {
+	// 服务商的模板 ID（阿里云 TemplateCode、腾讯云 TemplateId）
+	ID string
+
+	// 模板名称
+	Name string
+
+	// 审核状态
+	Status ReviewStatus
+
+	// 审核未通过的原因
+	Reason string
+
+	// 服务商返回的原始数据
+	Raw any
+}
+
+// SignStatus 是短信签名的审核状态
+type SignStatus struct {
+	// 服务商的签名 ID（阿里云为签名名称、腾讯云 SignId）
+	ID string
+
+	// 签名名称
+	Name string
+
+	// 审核状态
+	Status ReviewStatus
+
+	// 审核未通过的原因
+	Reason string
+
+	// 服务商返回的原始数据
+	Raw any
+}
+
+// TemplateManager 定义了支持管理短信模板和签名的网关接口
+// 模板和签名以服务商返回的 ID 标识，阿里云的签名 ID 即签名名称
+type TemplateManager interface {
+	Gateway
+
+	// AddTemplate 申请短信模板，返回模板 ID
+	AddTemplate(ctx context.Context, req *TemplateRequest) (string, error)
+
+	// QueryTemplateStatus 查询模板的审核状态
+	QueryTemplateStatus(ctx context.Context, id string) (*TemplateStatus, error)
+
+	// DeleteTemplate 删除模板
+	DeleteTemplate(ctx context.Context, id string) error
+
+	// AddSign 申请短信签名，返回签名 ID
+	AddSign(ctx context.Context, req *SignRequest) (string, error)
+
+	// QuerySignStatus 查询签名的审核状态
+	QuerySignStatus(ctx context.Context, id string) (*SignStatus, error)
+
+	// DeleteSign 删除签名
+	DeleteSign(ctx context.Context, id string) error
+}
@@ -1,8 +1,10 @@
 package gateway
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
+	"strconv"
 
 	"github.com/anhao/go-easy-sms/message"
 )
@@ -11,8 +13,58 @@ import (
 const (
 	// TwilioEndpointURL Twilio 短信 API 地址模板
 	TwilioEndpointURL = "https://api.twilio.com/2010-04-01/Accounts/%s/Messages.json"
+	// TwilioBalanceURL Twilio 账户余额 API 地址模板
+	TwilioBalanceURL = "https://api.twilio.com/2010-04-01/Accounts/%s/Balance.json"
+	// TwilioMaxLength Twilio 短信内容的最大字符数
+	TwilioMaxLength = 1600
 )
 
+// twilioErrorCategories Twilio 错误码分类
+var twilioErrorCategories = map[string]ErrorCategory{
+	"20003": CategoryAuth,
+	"20005": CategoryAuth,
+	"20429": CategoryRateLimited,
+	"21211": CategoryInvalidNumber,
+	"21214": CategoryInvalidNumber,
+	"21408": CategoryInvalidNumber,
+	"21612": CategoryInvalidNumber,
+	"21614": CategoryInvalidNumber,
+	"21610": CategoryBlacklisted,
+	"21602": CategoryInvalidParam,
+	"21604": CategoryInvalidParam,
+	"21606": CategoryInvalidParam,
+	"21617": CategoryInvalidParam,
+	"30001": CategoryRateLimited,
+	"30002": CategoryAuth,
+	"30003": CategoryInvalidNumber,
+	"30005": CategoryInvalidNumber,
+	"30006": CategoryInvalidNumber,
+	"30007": CategorySensitiveContent,
+	"30008": CategoryServer,
+}
+
+// TwilioConfig Twilio短信网关配置
+type TwilioConfig struct {
+	CommonConfig
+
+	// Account SID
+	AccountSID string `config:"account_sid,required"`
+
+	// Auth Token
+	Token string `config:"token,required"`
+
+	// 发送号码
+	From string `config:"from,required"`
+
+	// 状态回调地址，用于校验状态报告签名
+	CallbackURL string `config:"callback_url"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *TwilioConfig) Validate() error {
+	return requireFields(c)
+}
+
 // TwilioGateway Twilio 短信网关
 type TwilioGateway struct {
 	*BaseGateway
@@ -30,8 +82,18 @@ func NewTwilioGateway(config map[string]any) *TwilioGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，Twilio 只支持内容短信，内容最长 1600 个字符
+func (g *TwilioGateway) Capabilities() Capabilities {
+	return Capabilities{International: true, Content: true, Unicode: true, MaxLength: TwilioMaxLength}
+}
+
 // Send 发送短信
 func (g *TwilioGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *TwilioGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
 	// 获取账号 SID
 	accountSid := g.GetConfigString("account_sid")
 
@@ -53,8 +115,8 @@ func (g *TwilioGateway) Send(to *message.PhoneNumber, msg *message.Message) (any
 	}
 
 	// 发送请求
-	result, err := g.post(endpoint, params, accountSid, g.GetConfigString("token"))
-	if err != nil {
+	result, err := withStatusBody(g.post(ctx, endpoint, params, accountSid, g.GetConfigString("token")))
+	if result == nil {
 		return nil, err
 	}
 
@@ -68,12 +130,17 @@ func (g *TwilioGateway) Send(to *message.PhoneNumber, msg *message.Message) (any
 		errorCode := 0
 		if code, ok := result["error_code"].(float64); ok {
 			errorCode = int(code)
+		} else if code, ok := result["code"].(float64); ok {
+			// 请求被拒绝时错误码在 code 中
+			errorCode = int(code)
 		}
 
-		return result, fmt.Errorf("twilio 短信发送失败: [%d] %s", errorCode, errorMsg)
+		code := strconv.Itoa(errorCode)
+		category, inferred := classify(twilioErrorCategories, code, errorMsg)
+		return result, newSendError(g.GetName(), category, inferred, code, errorMsg, "twilio 短信发送失败: [%d] %s", errorCode, errorMsg).withStatus(err)
 	}
 
-	return result, nil
+	return result, err
 }
 
 // buildEndpoint 构建请求地址
@@ -101,11 +168,43 @@ func (g *TwilioGateway) isErrorStatus(result map[string]any) bool {
 }
 
 // post 发送 POST 请求
-func (g *TwilioGateway) post(endpoint string, params map[string]string, username, password string) (map[string]any, error) {
+func (g *TwilioGateway) post(ctx context.Context, endpoint string, params map[string]string, username, password string) (map[string]any, error) {
 	// 使用 BaseGateway 的 Post 方法发送请求
 	headers := map[string]string{
 		"Content-Type":  "application/x-www-form-urlencoded",
 		"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password)),
 	}
-	return g.Post(endpoint, params, headers)
+	return g.PostContext(ctx, endpoint, params, headers)
+}
+
+// QueryBalance 实现 BalanceQuerier 接口，查询账户余额，单位为账户的结算货币
+func (g *TwilioGateway) QueryBalance(ctx context.Context) (*Balance, error) {
+	accountSid := g.GetConfigString("account_sid")
+	result, err := g.GetContext(ctx, fmt.Sprintf(TwilioBalanceURL, accountSid), nil, map[string]string{
+		"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte(accountSid+":"+g.GetConfigString("token"))),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 请求被拒绝时返回 code 和 message
+	if code, ok := result["code"].(float64); ok {
+		errorMsg, _ := result["message"].(string)
+		errorCode := strconv.Itoa(int(code))
+		category, inferred := classify(twilioErrorCategories, errorCode, errorMsg)
+		return nil, newSendError(g.GetName(), category, inferred, errorCode, errorMsg, "twilio 余额查询失败: [%s] %s", errorCode, errorMsg)
+	}
+
+	amount, err := parseAmount(result["balance"])
+	if err != nil {
+		return nil, fmt.Errorf("twilio 余额查询失败: %w", err)
+	}
+
+	currency, _ := result["currency"].(string)
+	return &Balance{
+		Gateway: g.GetName(),
+		Amount:  amount,
+		Unit:    currency,
+		Raw:     result,
+	}, nil
 }
@@ -1,10 +1,12 @@
 package gateway
 
 import (
+	"context"
 	"crypto/sha1"
 	"encoding/hex"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/anhao/go-easy-sms/message"
@@ -18,8 +20,42 @@ const (
 	UcloudEndpointAction = "SendUSMSMessage"
 	// UcloudSuccessCode UCloud 短信 API 成功状态码
 	UcloudSuccessCode = 0
+	// UcloudBatchSize UCloud 单次请求支持的最大号码数量
+	UcloudBatchSize = 100
 )
 
+// ucloudErrorCategories UCloud 错误码分类
+var ucloudErrorCategories = map[string]ErrorCategory{
+	"150": CategoryServer,
+	"160": CategoryInvalidParam,
+	"170": CategoryAuth,
+	"171": CategoryAuth,
+	"172": CategoryAuth,
+	"230": CategoryInvalidParam,
+}
+
+// UcloudConfig UCloud短信网关配置
+type UcloudConfig struct {
+	CommonConfig
+
+	// 公钥
+	PublicKey string `config:"public_key,required"`
+
+	// 私钥
+	PrivateKey string `config:"private_key,required"`
+
+	// 项目 ID
+	ProjectID string `config:"project_id"`
+
+	// 短信签名
+	SigContent string `config:"sig_content"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *UcloudConfig) Validate() error {
+	return requireFields(c)
+}
+
 // UcloudGateway UCloud 短信网关
 type UcloudGateway struct {
 	*BaseGateway
@@ -32,14 +68,49 @@ func NewUcloudGateway(config map[string]any) *UcloudGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，UCloud 只支持模板短信
+func (g *UcloudGateway) Capabilities() Capabilities {
+	return Capabilities{International: true, Template: true, Batch: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *UcloudGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *UcloudGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.send(ctx, []string{to.String()}, msg)
+}
+
+// BatchSize 单次请求支持的最大号码数量
+func (g *UcloudGateway) BatchSize() int {
+	return UcloudBatchSize
+}
+
+// SendBatch 通过 PhoneNumbers.N 批量发送短信
+// UCloud 只返回整体结果，成功时所有号码共享同一个响应
+func (g *UcloudGateway) SendBatch(ctx context.Context, to []*message.PhoneNumber, msg *message.Message) ([]BatchResult, error) {
+	phones := make([]string, len(to))
+	for i, phone := range to {
+		phones[i] = phone.String()
+	}
+
+	result, err := g.send(ctx, phones, msg)
+	if err != nil {
+		return nil, err
+	}
+	return uniformBatchResults(len(to), result), nil
+}
+
+// send 向多个号码发送短信
+func (g *UcloudGateway) send(ctx context.Context, phones []string, msg *message.Message) (any, error) {
 	// 构建请求参数
-	params := g.buildParams(to, msg)
+	params := g.buildParams(phones, msg)
 
 	// 发送请求
-	result, err := g.request(UcloudEndpointURL, params)
-	if err != nil {
+	result, err := withStatusBody(g.request(ctx, UcloudEndpointURL, params))
+	if result == nil {
 		return nil, err
 	}
 
@@ -50,14 +121,15 @@ func (g *UcloudGateway) Send(to *message.PhoneNumber, msg *message.Message) (any
 			errorMsg = msg
 		}
 
-		return result, fmt.Errorf("UCloud 短信发送失败: [%d] %s", int(retCode), errorMsg)
+		category, inferred := classify(ucloudErrorCategories, strconv.Itoa(int(retCode)), errorMsg)
+		return result, newSendError(g.GetName(), category, inferred, strconv.Itoa(int(retCode)), errorMsg, "UCloud 短信发送失败: [%d] %s", int(retCode), errorMsg).withStatus(err)
 	}
 
-	return result, nil
+	return result, err
 }
 
 // buildParams 构建请求参数
-func (g *UcloudGateway) buildParams(to *message.PhoneNumber, msg *message.Message) map[string]string {
+func (g *UcloudGateway) buildParams(phones []string, msg *message.Message) map[string]string {
 	data := msg.GetData()
 	params := map[string]string{
 		"Action":     UcloudEndpointAction,
@@ -111,7 +183,9 @@ func (g *UcloudGateway) buildParams(to *message.PhoneNumber, msg *message.Messag
 		}
 	} else {
 		// 使用传入的手机号码
-		params["PhoneNumbers.0"] = to.String()
+		for i, phone := range phones {
+			params[fmt.Sprintf("PhoneNumbers.%d", i)] = phone
+		}
 	}
 
 	// 处理项目 ID
@@ -152,7 +226,7 @@ func (g *UcloudGateway) getSignature(params map[string]string, privateKey string
 }
 
 // request 发送 HTTP 请求
-func (g *UcloudGateway) request(endpoint string, params map[string]string) (map[string]any, error) {
+func (g *UcloudGateway) request(ctx context.Context, endpoint string, params map[string]string) (map[string]any, error) {
 	// 使用 BaseGateway 的 Get 方法发送请求
-	return g.Get(endpoint, params, nil)
+	return g.GetContext(ctx, endpoint, params, nil)
 }
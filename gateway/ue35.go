@@ -6,7 +6,7 @@ import (
 	"encoding/xml"
 	"fmt"
 	"net/url"
-	"time"
+	"strconv"
 
 	"github.com/anhao/go-easy-sms/message"
 )
@@ -21,6 +21,22 @@ const (
 	Ue35SuccessCode = 1
 )
 
+// Ue35Config Ue35短信网关配置
+type Ue35Config struct {
+	CommonConfig
+
+	// 用户名
+	Username string `config:"username,required"`
+
+	// 密码
+	UserPwd string `config:"userpwd,required"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *Ue35Config) Validate() error {
+	return requireFields(c)
+}
+
 // Ue35Gateway Ue35 短信网关
 type Ue35Gateway struct {
 	*BaseGateway
@@ -33,8 +49,18 @@ func NewUe35Gateway(config map[string]any) *Ue35Gateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，UE35 只支持内容短信和国内号码
+func (g *Ue35Gateway) Capabilities() Capabilities {
+	return Capabilities{Content: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *Ue35Gateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *Ue35Gateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
 	// 构建请求参数
 	params := map[string]string{
 		"username": g.GetConfigString("username"),
@@ -44,8 +70,8 @@ func (g *Ue35Gateway) Send(to *message.PhoneNumber, msg *message.Message) (any,
 	}
 
 	// 发送请求
-	result, err := g.request(g.getEndpointURI(), params)
-	if err != nil {
+	result, err := withStatusBody(g.request(ctx, g.getEndpointURI(), params))
+	if result == nil {
 		return nil, err
 	}
 
@@ -56,10 +82,11 @@ func (g *Ue35Gateway) Send(to *message.PhoneNumber, msg *message.Message) (any,
 		if msg, ok := result["message"].(string); ok {
 			errMsg = msg
 		}
-		return nil, fmt.Errorf("ue35 gateway error: %s (code: %v)", errMsg, errorCode)
+		category, inferred := classifyMessage(errMsg)
+		return nil, newSendError(g.GetName(), category, inferred, strconv.Itoa(int(errorCode)), errMsg, "ue35 gateway error: %s (code: %v)", errMsg, errorCode)
 	}
 
-	return result, nil
+	return result, err
 }
 
 // getEndpointURI 获取端点 URI
@@ -68,7 +95,7 @@ func (g *Ue35Gateway) getEndpointURI() string {
 }
 
 // request 发送 HTTP 请求
-func (g *Ue35Gateway) request(endpoint string, params map[string]string) (map[string]any, error) {
+func (g *Ue35Gateway) request(ctx context.Context, endpoint string, params map[string]string) (map[string]any, error) {
 	// 构建 URL 查询参数
 	query := url.Values{}
 	for k, v := range params {
@@ -85,8 +112,8 @@ func (g *Ue35Gateway) request(endpoint string, params map[string]string) (map[st
 		"User-Agent":   "Go EasySms Client",
 	}
 
-	// 创建上下文，设置超时
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(g.GetConfigFloat("timeout", 5.0))*time.Second)
+	// 在上下文的基础上设置超时
+	ctx, cancel := g.WithTimeout(ctx)
 	defer cancel()
 
 	// 使用 BaseGateway 的 HTTP 客户端发送请求
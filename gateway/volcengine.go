@@ -1,6 +1,7 @@
 package gateway
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
@@ -32,6 +33,8 @@ const (
 	VolcengineAlgorithm = "HMAC-SHA256"
 	// VolcengineEndpointDefaultRegionID 火山引擎短信 API 默认区域ID
 	VolcengineEndpointDefaultRegionID = "cn-north-1"
+	// VolcengineBatchSize 火山引擎单次请求支持的最大号码数量
+	VolcengineBatchSize = 200
 )
 
 // VolcengineEndpoints 火山引擎短信 API 端点
@@ -40,6 +43,46 @@ var VolcengineEndpoints = map[string]string{
 	"ap-singapore-1": "https://sms.byteplusapi.com",
 }
 
+// volcengineErrorCategories 火山引擎错误码分类
+var volcengineErrorCategories = map[string]ErrorCategory{
+	"MissingParameter":       CategoryInvalidParam,
+	"InvalidParameter":       CategoryInvalidParam,
+	"InvalidTimestamp":       CategoryInvalidParam,
+	"InvalidAccessKey":       CategoryAuth,
+	"InvalidAuthorization":   CategoryAuth,
+	"SignatureDoesNotMatch":  CategoryAuth,
+	"AccessDenied":           CategoryAuth,
+	"FlowLimitExceeded":      CategoryRateLimited,
+	"InternalError":          CategoryServer,
+	"InternalServiceError":   CategoryServer,
+	"ServiceUnavailableTemp": CategoryServer,
+}
+
+// VolcengineConfig 火山引擎短信网关配置
+type VolcengineConfig struct {
+	CommonConfig
+
+	// Access Key ID
+	AccessKeyID string `config:"access_key_id,required"`
+
+	// Secret Access Key
+	AccessKeySecret string `config:"access_key_secret,required"`
+
+	// 地域，默认 cn-north-1
+	RegionID string `config:"region_id"`
+
+	// 短信签名，也可以在消息数据中通过 sign_name 指定
+	SignName string `config:"sign_name"`
+
+	// 消息组 ID，也可以在消息数据中通过 sms_account 指定
+	SmsAccount string `config:"sms_account"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *VolcengineConfig) Validate() error {
+	return requireFields(c)
+}
+
 // VolcengineGateway 火山引擎短信网关
 type VolcengineGateway struct {
 	*BaseGateway
@@ -52,14 +95,43 @@ func NewVolcengineGateway(config map[string]any) *VolcengineGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，火山引擎只支持模板短信和国内号码
+func (g *VolcengineGateway) Capabilities() Capabilities {
+	return Capabilities{Template: true, Batch: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *VolcengineGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *VolcengineGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.send(ctx, g.getPhoneNumbers(to, msg.GetData()), msg)
+}
+
+// BatchSize 单次请求支持的最大号码数量
+func (g *VolcengineGateway) BatchSize() int {
+	return VolcengineBatchSize
+}
+
+// SendBatch 使用逗号连接的 PhoneNumbers 批量发送短信
+// 火山引擎只返回整体结果，成功时所有号码共享同一个响应
+func (g *VolcengineGateway) SendBatch(ctx context.Context, to []*message.PhoneNumber, msg *message.Message) ([]BatchResult, error) {
+	result, err := g.send(ctx, joinNumbers(to, (*message.PhoneNumber).GetNumber), msg)
+	if err != nil {
+		return nil, err
+	}
+	return uniformBatchResults(len(to), result), nil
+}
+
+// send 向 phoneNumbers 发送短信，多个号码使用逗号分隔
+func (g *VolcengineGateway) send(ctx context.Context, phoneNumbers string, msg *message.Message) (any, error) {
 	// 获取消息数据
 	data := msg.GetData()
 	signName := g.getSignName(data)
 	smsAccount := g.getSmsAccount(data)
 	templateID := msg.GetTemplate()
-	phoneNumbers := g.getPhoneNumbers(to, data)
 	templateParam := g.getTemplateParam(msg, data)
 	tag := g.getTag(data)
 
@@ -84,8 +156,8 @@ func (g *VolcengineGateway) Send(to *message.PhoneNumber, msg *message.Message)
 	}
 
 	// 发送请求
-	result, err := g.request("POST", g.getEndpoint(), queries, payload)
-	if err != nil {
+	result, err := withStatusBody(g.request(ctx, "POST", g.getEndpoint(), queries, payload))
+	if result == nil {
 		return nil, err
 	}
 
@@ -94,11 +166,14 @@ func (g *VolcengineGateway) Send(to *message.PhoneNumber, msg *message.Message)
 		metadata := result["ResponseMetadata"].(map[string]any)
 		if metadata["Error"] != nil {
 			errorData := metadata["Error"].(map[string]any)
-			return nil, fmt.Errorf("volcengine gateway error: %s", errorData["Message"])
+			code, _ := errorData["Code"].(string)
+			errorMsg, _ := errorData["Message"].(string)
+			category, inferred := classify(volcengineErrorCategories, code, errorMsg)
+			return nil, newSendError(g.GetName(), category, inferred, code, errorMsg, "volcengine gateway error: %s", errorData["Message"])
 		}
 	}
 
-	return result, nil
+	return result, err
 }
 
 // getSignName 获取签名名称
@@ -151,7 +226,7 @@ func (g *VolcengineGateway) getEndpoint() string {
 }
 
 // request 发送 HTTP 请求
-func (g *VolcengineGateway) request(method, endpoint string, queries map[string]string, payload map[string]any) (map[string]any, error) {
+func (g *VolcengineGateway) request(ctx context.Context, method, endpoint string, queries map[string]string, payload map[string]any) (map[string]any, error) {
 	// 构建请求 URL
 	requestURL := endpoint
 	if len(queries) > 0 {
@@ -187,7 +262,7 @@ func (g *VolcengineGateway) request(method, endpoint string, queries map[string]
 	headers["Authorization"] = authHeader
 
 	// 使用 BaseGateway 的 PostJSON 方法发送请求
-	return g.PostJSON(requestURL, payload, headers)
+	return g.PostJSONContext(ctx, requestURL, payload, headers)
 }
 
 // generateAuthHeader 生成授权头
@@ -1,6 +1,7 @@
 package gateway
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/base64"
 	"encoding/json"
@@ -22,6 +23,42 @@ const (
 	YidongmasblackSuccessStatus = "true"
 )
 
+// yidongmasblackErrorCategories 移动云 MAS 错误码分类
+var yidongmasblackErrorCategories = map[string]ErrorCategory{
+	"IllegalMac":       CategoryAuth,
+	"InvalidUsrOrPwd":  CategoryAuth,
+	"IllegalSignId":    CategoryTemplate,
+	"NoSignId":         CategoryTemplate,
+	"InvalidMessage":   CategoryInvalidParam,
+	"TooManyMobiles":   CategoryInvalidParam,
+	"InvalidTemplates": CategoryTemplate,
+}
+
+// YidongmasblackConfig 移动MAS短信网关配置
+type YidongmasblackConfig struct {
+	CommonConfig
+
+	// 企业名称
+	EcName string `config:"ecName,required"`
+
+	// 接口账号
+	ApID string `config:"apId,required"`
+
+	// 接口密码
+	SecretKey string `config:"secretKey,required"`
+
+	// 签名编码
+	Sign string `config:"sign"`
+
+	// 扩展码
+	AddSerial string `config:"addSerial"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *YidongmasblackConfig) Validate() error {
+	return requireFields(c)
+}
+
 // YidongmasblackGateway 移动MAS模式短信网关
 type YidongmasblackGateway struct {
 	*BaseGateway
@@ -34,8 +71,18 @@ func NewYidongmasblackGateway(config map[string]any) *YidongmasblackGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，移动云 MAS 只支持内容短信和国内号码
+func (g *YidongmasblackGateway) Capabilities() Capabilities {
+	return Capabilities{Content: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *YidongmasblackGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *YidongmasblackGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
 	// 构建请求参数
 	params := map[string]any{
 		"ecName":    g.GetConfigString("ecName"),
@@ -50,7 +97,7 @@ func (g *YidongmasblackGateway) Send(to *message.PhoneNumber, msg *message.Messa
 	content := g.GenerateContent(params)
 
 	// 发送请求
-	result, err := g.postJSON(YidongmasblackEndpointURL, content)
+	result, err := g.postJSON(ctx, YidongmasblackEndpointURL, content)
 	if err != nil {
 		return nil, err
 	}
@@ -63,7 +110,8 @@ func (g *YidongmasblackGateway) Send(to *message.PhoneNumber, msg *message.Messa
 			errorCode = rspcod
 		}
 
-		return nil, fmt.Errorf("yidongmasblack gateway error: %s (code: %s)", success, errorCode)
+		category, inferred := classify(yidongmasblackErrorCategories, errorCode, "")
+		return nil, newSendError(g.GetName(), category, inferred, errorCode, success, "yidongmasblack gateway error: %s (code: %s)", success, errorCode)
 	}
 
 	return result, nil
@@ -95,9 +143,9 @@ func (g *YidongmasblackGateway) GenerateContent(params map[string]any) string {
 }
 
 // postJSON 发送 JSON 请求
-func (g *YidongmasblackGateway) postJSON(endpoint string, content string) (map[string]any, error) {
+func (g *YidongmasblackGateway) postJSON(ctx context.Context, endpoint string, content string) (map[string]any, error) {
 	// 创建请求
-	req, err := http.NewRequest("POST", endpoint, strings.NewReader(content))
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(content))
 	if err != nil {
 		return nil, err
 	}
@@ -1,28 +1,155 @@
 package gateway
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/anhao/go-easy-sms/message"
+	"github.com/anhao/go-easy-sms/receipt"
 )
 
+// YunpianBatchSize 云片单次请求支持的最大号码数量
+const YunpianBatchSize = 1000
+
+// yunpianErrorCategories 云片错误码分类
+var yunpianErrorCategories = map[string]ErrorCategory{
+	"1":   CategoryInvalidParam,
+	"2":   CategoryInvalidParam,
+	"3":   CategoryInsufficientBalance,
+	"4":   CategorySensitiveContent,
+	"5":   CategoryTemplate,
+	"6":   CategoryTemplate,
+	"7":   CategoryTemplate,
+	"8":   CategoryRateLimited,
+	"9":   CategoryRateLimited,
+	"10":  CategoryBlacklisted,
+	"13":  CategoryRateLimited,
+	"14":  CategoryAuth,
+	"17":  CategoryRateLimited,
+	"20":  CategoryInvalidNumber,
+	"22":  CategoryRateLimited,
+	"23":  CategoryInvalidNumber,
+	"27":  CategoryInsufficientBalance,
+	"28":  CategoryServer,
+	"33":  CategoryRateLimited,
+	"43":  CategoryRateLimited,
+	"-1":  CategoryAuth,
+	"-2":  CategoryAuth,
+	"-3":  CategoryAuth,
+	"-4":  CategoryRateLimited,
+	"-5":  CategoryRateLimited,
+	"-50": CategoryServer,
+	"-51": CategoryServer,
+	"-53": CategoryServer,
+}
+
+// YunpianConfig 云片短信网关配置
+type YunpianConfig struct {
+	CommonConfig
+
+	// API Key
+	APIKey string `config:"api_key,required"`
+
+	// 接口地址
+	Endpoint string `config:"endpoint"`
+
+	// 短信签名，内容中没有签名时自动添加
+	Signature string `config:"signature"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *YunpianConfig) Validate() error {
+	return requireFields(c)
+}
+
 // YunpianGateway 云片短信网关
 type YunpianGateway struct {
 	*BaseGateway
+
+	// pull_status 拉取到但尚未被查询的状态报告，按 sid 索引
+	statuses map[string]pulledStatus
+	mu       sync.Mutex
+}
+
+// pulledStatus 缓存的状态报告及其拉取时间
+type pulledStatus struct {
+	report   *receipt.DeliveryReport
+	pulledAt time.Time
 }
 
 // NewYunpianGateway 创建一个新的云片短信网关
 func NewYunpianGateway(config map[string]any) *YunpianGateway {
 	return &YunpianGateway{
 		BaseGateway: NewBaseGateway("yunpian", config),
+		statuses:    make(map[string]pulledStatus),
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，云片只支持内容短信和国内号码
+func (g *YunpianGateway) Capabilities() Capabilities {
+	return Capabilities{Content: true, Batch: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *YunpianGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *YunpianGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
+	result, err := g.request(ctx, "/v2/sms/single_send.json", to.GetNumber(), msg)
+	if result == nil {
+		return nil, err
+	}
+
+	// 检查响应状态
+	if err := g.codeError(result, err); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// BatchSize 单次请求支持的最大号码数量
+func (g *YunpianGateway) BatchSize() int {
+	return YunpianBatchSize
+}
+
+// SendBatch 通过 batch_send 接口批量发送短信，按返回的 data 列表得到每个号码的结果
+func (g *YunpianGateway) SendBatch(ctx context.Context, to []*message.PhoneNumber, msg *message.Message) ([]BatchResult, error) {
+	result, err := g.request(ctx, "/v2/sms/batch_send.json", joinNumbers(to, (*message.PhoneNumber).GetNumber), msg)
+	if result == nil {
+		return nil, err
+	}
+
+	// 请求整体失败时返回顶层的错误码
+	if _, ok := result["code"]; ok || err != nil {
+		if err := g.codeError(result, err); err != nil {
+			return nil, err
+		}
+	}
+
+	items, _ := result["data"].([]any)
+	return matchBatchResults(g.GetName(), to, items,
+		func(item map[string]any) string {
+			mobile, _ := item["mobile"].(string)
+			return mobile
+		},
+		func(item map[string]any) BatchResult {
+			return BatchResult{Data: item, Err: g.codeError(item, nil)}
+		},
+	), nil
+}
+
+// request 向 mobile 发送短信，多个号码使用逗号分隔
+func (g *YunpianGateway) request(ctx context.Context, path, mobile string, msg *message.Message) (map[string]any, error) {
 	apiKey := g.GetConfigString("api_key")
 	if apiKey == "" {
 		return nil, errors.New("api_key is required")
@@ -43,12 +170,12 @@ func (g *YunpianGateway) Send(to *message.PhoneNumber, msg *message.Message) (an
 	// 构建请求参数
 	data := url.Values{}
 	data.Add("apikey", apiKey)
-	data.Add("mobile", to.GetNumber())
+	data.Add("mobile", mobile)
 	data.Add("text", content)
 
 	// 构建请求URL
 	endpoint := g.GetConfigString("endpoint", "https://sms.yunpian.com")
-	requestURL := endpoint + "/v2/sms/single_send.json"
+	requestURL := endpoint + path
 
 	// 将 url.Values 转换为 map[string]string
 	params := make(map[string]string)
@@ -59,21 +186,168 @@ func (g *YunpianGateway) Send(to *message.PhoneNumber, msg *message.Message) (an
 	}
 
 	// 使用 BaseGateway 的 Post 方法发送请求
-	result, err := g.Post(requestURL, params, map[string]string{
+	result, err := withStatusBody(g.PostContext(ctx, requestURL, params, map[string]string{
 		"Content-Type": "application/x-www-form-urlencoded",
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
+	}))
+	if result == nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 
-	// 检查响应状态
+	return result, err
+}
+
+// codeError 检查响应中的错误码，没有错误码时返回请求的 HTTP 状态错误 statusErr
+func (g *YunpianGateway) codeError(result map[string]any, statusErr error) error {
 	if code, ok := result["code"].(float64); !ok || code != 0 {
 		message := "unknown error"
 		if msg, ok := result["msg"].(string); ok {
 			message = msg
 		}
-		return result, fmt.Errorf("yunpian gateway error: %s", message)
+		errorCode := strconv.Itoa(int(code))
+		category, inferred := classify(yunpianErrorCategories, errorCode, message)
+		return newSendError(g.GetName(), category, inferred, errorCode, message, "yunpian gateway error: %s", message).withStatus(statusErr)
 	}
+	return statusErr
+}
 
-	return result, nil
+// MessageID 实现 StatusQuerier 接口，返回发送结果中的 sid
+func (g *YunpianGateway) MessageID(result any) string {
+	if resultMap, ok := result.(map[string]any); ok {
+		return stringOf(resultMap["sid"])
+	}
+	return ""
+}
+
+// QueryStatus 实现 StatusQuerier 接口，通过 pull_status 接口获取送达状态
+// pull_status 返回账号下所有新的状态报告且每条只返回一次，未被本次查询的报告会被缓存，供后续查询使用
+func (g *YunpianGateway) QueryStatus(ctx context.Context, messageID string, to *message.PhoneNumber) (*receipt.DeliveryReport, error) {
+	if report := g.takeStatus(messageID); report != nil {
+		return report, nil
+	}
+
+	if err := g.pullStatus(ctx); err != nil {
+		return nil, err
+	}
+
+	if report := g.takeStatus(messageID); report != nil {
+		return report, nil
+	}
+	return pendingReport(g.GetName(), messageID, to), nil
+}
+
+// takeStatus 取出缓存中的状态报告
+func (g *YunpianGateway) takeStatus(messageID string) *receipt.DeliveryReport {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	status, ok := g.statuses[messageID]
+	if !ok {
+		return nil
+	}
+	delete(g.statuses, messageID)
+	return status.report
+}
+
+// pullStatus 拉取新的状态报告并加入缓存，超过 StatusQueryLookback 的缓存会被清理
+func (g *YunpianGateway) pullStatus(ctx context.Context) error {
+	apiKey := g.GetConfigString("api_key")
+	if apiKey == "" {
+		return errors.New("api_key is required")
+	}
+
+	ctx, cancel := g.WithTimeout(ctx)
+	defer cancel()
+
+	endpoint := g.GetConfigString("endpoint", "https://sms.yunpian.com")
+	body, err := g.GetHTTPClient().Post(ctx, endpoint+"/v2/sms/pull_status.json", map[string]string{
+		"apikey":    apiKey,
+		"page_size": "100",
+	}, map[string]string{
+		"Content-Type": "application/x-www-form-urlencoded",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+
+	// 成功时返回状态报告数组，失败时返回带错误码的对象
+	var items []map[string]any
+	if err := json.Unmarshal(body, &items); err != nil {
+		var result map[string]any
+		if jsonErr := json.Unmarshal(body, &result); jsonErr != nil {
+			return fmt.Errorf("failed to parse response: %w", jsonErr)
+		}
+		return g.codeError(result, nil)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	for id, status := range g.statuses {
+		if now.Sub(status.pulledAt) > StatusQueryLookback {
+			delete(g.statuses, id)
+		}
+	}
+
+	for _, item := range items {
+		errCode := stringOf(item["error_msg"])
+
+		status := receipt.StatusDelivered
+		if !strings.EqualFold(stringOf(item["report_status"]), "SUCCESS") {
+			status = receipt.StatusFailed
+			if errCode == "EXPIRED" {
+				status = receipt.StatusExpired
+			}
+		}
+
+		report := &receipt.DeliveryReport{
+			Gateway:    g.GetName(),
+			MessageID:  stringOf(item["sid"]),
+			Phone:      stringOf(item["mobile"]),
+			Status:     status,
+			ErrorCode:  errCode,
+			ReportTime: parseChinaTime("2006-01-02 15:04:05", stringOf(item["user_receive_time"])),
+			Raw:        item,
+		}
+		g.statuses[report.MessageID] = pulledStatus{report: report, pulledAt: now}
+	}
+
+	return nil
+}
+
+// QueryBalance 实现 BalanceQuerier 接口，通过 user/get 接口查询账户余额（元）
+func (g *YunpianGateway) QueryBalance(ctx context.Context) (*Balance, error) {
+	apiKey := g.GetConfigString("api_key")
+	if apiKey == "" {
+		return nil, errors.New("api_key is required")
+	}
+
+	endpoint := g.GetConfigString("endpoint", "https://sms.yunpian.com")
+	result, err := g.PostContext(ctx, endpoint+"/v2/user/get.json", map[string]string{
+		"apikey": apiKey,
+	}, map[string]string{
+		"Content-Type": "application/x-www-form-urlencoded",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	// 失败时返回带错误码的对象
+	if _, ok := result["code"]; ok {
+		if err := g.codeError(result, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	amount, err := parseAmount(result["balance"])
+	if err != nil {
+		return nil, fmt.Errorf("yunpian gateway error: %w", err)
+	}
+
+	return &Balance{
+		Gateway: g.GetName(),
+		Amount:  amount,
+		Unit:    BalanceUnitCNY,
+		Raw:     result,
+	}, nil
 }
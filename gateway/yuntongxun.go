@@ -1,6 +1,7 @@
 package gateway
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/base64"
 	"fmt"
@@ -30,6 +31,47 @@ const (
 	YuntongxunSuccessCode = "000000"
 )
 
+// yuntongxunErrorCategories 容联云通讯错误码分类
+var yuntongxunErrorCategories = map[string]ErrorCategory{
+	"160031": CategoryInvalidParam,
+	"160032": CategoryTemplate,
+	"160033": CategoryBlacklisted,
+	"160034": CategoryBlacklisted,
+	"160036": CategoryTemplate,
+	"160038": CategoryRateLimited,
+	"160039": CategoryRateLimited,
+	"160040": CategoryRateLimited,
+	"160041": CategoryRateLimited,
+	"160042": CategoryInvalidNumber,
+	"160043": CategoryTemplate,
+	"160050": CategoryServer,
+}
+
+// YuntongxunConfig 容联云通讯短信网关配置
+type YuntongxunConfig struct {
+	CommonConfig
+
+	// 主账号 ID
+	AccountSID string `config:"account_sid,required"`
+
+	// 主账号令牌
+	AccountToken string `config:"account_token,required"`
+
+	// 应用 ID
+	AppID string `config:"app_id,required"`
+
+	// 是否使用子账号
+	IsSubAccount bool `config:"is_sub_account"`
+
+	// 是否使用沙箱环境
+	Debug bool `config:"debug"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *YuntongxunConfig) Validate() error {
+	return requireFields(c)
+}
+
 // YuntongxunGateway 容联云通讯短信网关
 type YuntongxunGateway struct {
 	*BaseGateway
@@ -44,8 +86,18 @@ func NewYuntongxunGateway(config map[string]any) *YuntongxunGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，国内号码使用模板，国际号码使用内容
+func (g *YuntongxunGateway) Capabilities() Capabilities {
+	return Capabilities{International: true, Content: true, Template: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *YuntongxunGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *YuntongxunGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
 	// 重置国际短信标志
 	g.international = false
 
@@ -113,8 +165,8 @@ func (g *YuntongxunGateway) Send(to *message.PhoneNumber, msg *message.Message)
 	}
 
 	// 发送请求
-	result, err := g.postJSON(endpoint, data, headers)
-	if err != nil {
+	result, err := withStatusBody(g.postJSON(ctx, endpoint, data, headers))
+	if result == nil {
 		return nil, err
 	}
 
@@ -125,10 +177,12 @@ func (g *YuntongxunGateway) Send(to *message.PhoneNumber, msg *message.Message)
 			errorMsg = statusCode
 		}
 
-		return result, fmt.Errorf("容联云通讯短信发送失败: %s", errorMsg)
+		statusMsg, _ := result["statusMsg"].(string)
+		category, inferred := classify(yuntongxunErrorCategories, statusCode, statusMsg)
+		return result, newSendError(g.GetName(), category, inferred, statusCode, statusMsg, "容联云通讯短信发送失败: %s", errorMsg).withStatus(err)
 	}
 
-	return result, nil
+	return result, err
 }
 
 // buildEndpoint 构建请求地址
@@ -178,9 +232,9 @@ func (g *YuntongxunGateway) generateSignature(datetime string) string {
 }
 
 // postJSON 发送 JSON 请求
-func (g *YuntongxunGateway) postJSON(endpoint string, data map[string]any, headers map[string]string) (map[string]any, error) {
+func (g *YuntongxunGateway) postJSON(ctx context.Context, endpoint string, data map[string]any, headers map[string]string) (map[string]any, error) {
 	// 使用 BaseGateway 的 PostJSON 方法发送请求
-	return g.PostJSON(endpoint, data, headers)
+	return g.PostJSONContext(ctx, endpoint, data, headers)
 }
 
 // parseTemplateID 解析模板 ID
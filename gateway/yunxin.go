@@ -1,12 +1,14 @@
 package gateway
 
 import (
+	"context"
 	"crypto/sha1"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,6 +25,41 @@ const (
 	YunxinSuccessCode = 200
 )
 
+// yunxinErrorCategories 网易云信错误码分类
+var yunxinErrorCategories = map[string]ErrorCategory{
+	"315": CategoryAuth,
+	"403": CategoryAuth,
+	"414": CategoryInvalidParam,
+	"416": CategoryRateLimited,
+	"418": CategoryServer,
+	"419": CategoryRateLimited,
+	"500": CategoryServer,
+	"501": CategoryServer,
+	"503": CategoryServer,
+}
+
+// YunxinConfig 网易云信短信网关配置
+type YunxinConfig struct {
+	CommonConfig
+
+	// App Key
+	AppKey string `config:"app_key,required"`
+
+	// App Secret
+	AppSecret string `config:"app_secret,required"`
+
+	// 验证码长度，默认 4
+	CodeLength int `config:"code_length"`
+
+	// 是否需要上行
+	NeedUp bool `config:"need_up"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *YunxinConfig) Validate() error {
+	return requireFields(c)
+}
+
 // YunxinGateway 网易云信短信网关
 type YunxinGateway struct {
 	*BaseGateway
@@ -35,8 +72,18 @@ func NewYunxinGateway(config map[string]any) *YunxinGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，网易云信发送验证码时可以不指定模板
+func (g *YunxinGateway) Capabilities() Capabilities {
+	return Capabilities{International: true, Content: true, Template: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *YunxinGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *YunxinGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
 	// 获取消息数据
 	data := msg.GetData()
 
@@ -71,7 +118,7 @@ func (g *YunxinGateway) Send(to *message.PhoneNumber, msg *message.Message) (any
 	headers := g.buildHeaders()
 
 	// 发送请求
-	result, err := g.post(endpoint, params, headers)
+	result, err := g.post(ctx, endpoint, params, headers)
 	if err != nil {
 		return nil, err
 	}
@@ -83,7 +130,9 @@ func (g *YunxinGateway) Send(to *message.PhoneNumber, msg *message.Message) (any
 		if msg, ok := result["msg"].(string); ok {
 			errMsg = msg
 		}
-		return nil, fmt.Errorf("yunxin gateway error: %s (code: %v)", errMsg, code)
+		errorCode := strconv.Itoa(int(code))
+		category, inferred := classify(yunxinErrorCategories, errorCode, errMsg)
+		return nil, newSendError(g.GetName(), category, inferred, errorCode, errMsg, "yunxin gateway error: %s (code: %v)", errMsg, code)
 	}
 
 	return result, nil
@@ -191,7 +240,7 @@ func (g *YunxinGateway) buildTemplateParams(to *message.PhoneNumber, msg *messag
 }
 
 // post 发送 POST 请求
-func (g *YunxinGateway) post(endpoint string, params map[string]string, headers map[string]string) (map[string]any, error) {
+func (g *YunxinGateway) post(ctx context.Context, endpoint string, params map[string]string, headers map[string]string) (map[string]any, error) {
 	// 构建表单数据
 	form := url.Values{}
 	for k, v := range params {
@@ -199,7 +248,7 @@ func (g *YunxinGateway) post(endpoint string, params map[string]string, headers
 	}
 
 	// 创建请求
-	req, err := http.NewRequest("POST", endpoint, strings.NewReader(form.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
 	if err != nil {
 		return nil, err
 	}
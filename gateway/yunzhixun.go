@@ -1,6 +1,7 @@
 package gateway
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/anhao/go-easy-sms/message"
@@ -18,6 +19,32 @@ const (
 	YunzhixunEndpointTemplate = "https://open.ucpaas.com/ol/%s/%s"
 )
 
+// yunzhixunErrorCategories 云之讯错误码分类
+var yunzhixunErrorCategories = map[string]ErrorCategory{
+	"100001": CategoryInvalidParam,
+	"100015": CategoryInvalidNumber,
+	"105147": CategoryRateLimited,
+}
+
+// YunzhixunConfig 云之讯短信网关配置
+type YunzhixunConfig struct {
+	CommonConfig
+
+	// 账号 SID
+	SID string `config:"sid,required"`
+
+	// 账号令牌
+	Token string `config:"token,required"`
+
+	// 应用 ID
+	AppID string `config:"app_id,required"`
+}
+
+// Validate 实现 GatewayConfig 接口
+func (c *YunzhixunConfig) Validate() error {
+	return requireFields(c)
+}
+
 // YunzhixunGateway 云之讯短信网关
 type YunzhixunGateway struct {
 	*BaseGateway
@@ -30,8 +57,18 @@ func NewYunzhixunGateway(config map[string]any) *YunzhixunGateway {
 	}
 }
 
+// Capabilities 实现 CapableGateway 接口，云之讯只支持模板短信和国内号码
+func (g *YunzhixunGateway) Capabilities() Capabilities {
+	return Capabilities{Template: true, Unicode: true}
+}
+
 // Send 发送短信
 func (g *YunzhixunGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+// SendContext 使用指定上下文发送短信
+func (g *YunzhixunGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
 	// 获取消息数据
 	data := msg.GetData()
 
@@ -48,7 +85,7 @@ func (g *YunzhixunGateway) Send(to *message.PhoneNumber, msg *message.Message) (
 	params := g.buildParams(to, msg)
 
 	// 发送请求
-	return g.execute(endpoint, params)
+	return g.execute(ctx, endpoint, params)
 }
 
 // buildEndpoint 构建请求地址
@@ -91,10 +128,10 @@ func (g *YunzhixunGateway) buildParams(to *message.PhoneNumber, msg *message.Mes
 }
 
 // execute 执行请求
-func (g *YunzhixunGateway) execute(endpoint string, params map[string]any) (any, error) {
+func (g *YunzhixunGateway) execute(ctx context.Context, endpoint string, params map[string]any) (any, error) {
 	// 发送请求
-	result, err := g.postJSON(endpoint, params)
-	if err != nil {
+	result, err := withStatusBody(g.postJSON(ctx, endpoint, params))
+	if result == nil {
 		return nil, err
 	}
 
@@ -105,16 +142,17 @@ func (g *YunzhixunGateway) execute(endpoint string, params map[string]any) (any,
 		if msg, ok := result["msg"].(string); ok {
 			errMsg = msg
 		}
-		return nil, fmt.Errorf("yunzhixun gateway error: %s (code: %s)", errMsg, code)
+		category, inferred := classify(yunzhixunErrorCategories, code, errMsg)
+		return nil, newSendError(g.GetName(), category, inferred, code, errMsg, "yunzhixun gateway error: %s (code: %s)", errMsg, code)
 	}
 
-	return result, nil
+	return result, err
 }
 
 // postJSON 发送 JSON 请求
-func (g *YunzhixunGateway) postJSON(endpoint string, params map[string]any) (map[string]any, error) {
+func (g *YunzhixunGateway) postJSON(ctx context.Context, endpoint string, params map[string]any) (map[string]any, error) {
 	// 使用 BaseGateway 的 PostJSON 方法发送请求
-	return g.PostJSON(endpoint, params, map[string]string{
+	return g.PostJSONContext(ctx, endpoint, params, map[string]string{
 		"Content-Type": "application/json;charset=utf-8",
 		"Accept":       "application/json",
 	})
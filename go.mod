@@ -5,4 +5,5 @@ go 1.18
 require (
 	github.com/google/uuid v1.6.0
 	github.com/jarcoal/httpmock v1.4.0
+	gopkg.in/yaml.v3 v3.0.1
 )
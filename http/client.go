@@ -20,6 +20,24 @@ var (
 	once sync.Once
 )
 
+// StatusError 表示服务端返回了 5xx 或 429 状态码
+type StatusError struct {
+	StatusCode int
+
+	// 响应体，网关据此获取服务商错误码
+	Body []byte
+}
+
+// Error 实现 error 接口
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected http status: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Retryable 服务端错误和限流均可重试
+func (e *StatusError) Retryable() bool {
+	return true
+}
+
 // Client 是HTTP客户端的封装
 type Client struct {
 	client    *http.Client
@@ -183,6 +201,11 @@ func (c *Client) do(req *http.Request) ([]byte, error) {
 		return nil, err
 	}
 
+	// 服务端错误和限流返回 StatusError，响应体保留在 Body 中
+	if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: body}
+	}
+
 	return body, nil
 }
 
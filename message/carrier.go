@@ -0,0 +1,76 @@
+package message
+
+// Carrier 表示中国大陆手机号码所属的运营商
+type Carrier string
+
+// 运营商
+const (
+	// CarrierUnknown 未知运营商或非中国大陆号码
+	CarrierUnknown Carrier = ""
+	// CarrierChinaMobile 中国移动
+	CarrierChinaMobile Carrier = "china_mobile"
+	// CarrierChinaUnicom 中国联通
+	CarrierChinaUnicom Carrier = "china_unicom"
+	// CarrierChinaTelecom 中国电信
+	CarrierChinaTelecom Carrier = "china_telecom"
+	// CarrierChinaBroadnet 中国广电
+	CarrierChinaBroadnet Carrier = "china_broadnet"
+	// CarrierVirtual 虚拟运营商
+	CarrierVirtual Carrier = "virtual"
+)
+
+// carrierSegments 号段与运营商的对应关系，四位号段优先于三位号段
+var carrierSegments = map[string]Carrier{
+	// 中国移动
+	"134": CarrierChinaMobile, "135": CarrierChinaMobile, "136": CarrierChinaMobile, "137": CarrierChinaMobile,
+	"138": CarrierChinaMobile, "139": CarrierChinaMobile, "147": CarrierChinaMobile, "148": CarrierChinaMobile,
+	"150": CarrierChinaMobile, "151": CarrierChinaMobile, "152": CarrierChinaMobile, "157": CarrierChinaMobile,
+	"158": CarrierChinaMobile, "159": CarrierChinaMobile, "172": CarrierChinaMobile, "178": CarrierChinaMobile,
+	"182": CarrierChinaMobile, "183": CarrierChinaMobile, "184": CarrierChinaMobile, "187": CarrierChinaMobile,
+	"188": CarrierChinaMobile, "195": CarrierChinaMobile, "197": CarrierChinaMobile, "198": CarrierChinaMobile,
+
+	// 中国联通
+	"130": CarrierChinaUnicom, "131": CarrierChinaUnicom, "132": CarrierChinaUnicom, "145": CarrierChinaUnicom,
+	"146": CarrierChinaUnicom, "155": CarrierChinaUnicom, "156": CarrierChinaUnicom, "166": CarrierChinaUnicom,
+	"175": CarrierChinaUnicom, "176": CarrierChinaUnicom, "185": CarrierChinaUnicom, "186": CarrierChinaUnicom,
+	"196": CarrierChinaUnicom,
+
+	// 中国电信，1349 为电信卫星号段
+	"133": CarrierChinaTelecom, "149": CarrierChinaTelecom, "153": CarrierChinaTelecom, "173": CarrierChinaTelecom,
+	"177": CarrierChinaTelecom, "180": CarrierChinaTelecom, "181": CarrierChinaTelecom, "189": CarrierChinaTelecom,
+	"190": CarrierChinaTelecom, "191": CarrierChinaTelecom, "193": CarrierChinaTelecom, "199": CarrierChinaTelecom,
+	"1349": CarrierChinaTelecom,
+
+	// 中国广电
+	"192": CarrierChinaBroadnet,
+
+	// 虚拟运营商
+	"162": CarrierVirtual, "165": CarrierVirtual, "167": CarrierVirtual, "170": CarrierVirtual, "171": CarrierVirtual,
+}
+
+// LookupCarrier 按号段查询中国大陆手机号码（不含国际区号）的运营商，无法识别时返回 CarrierUnknown
+func LookupCarrier(number string) Carrier {
+	if len(number) != 11 || !isDigits(number) {
+		return CarrierUnknown
+	}
+	if carrier, ok := carrierSegments[number[:4]]; ok {
+		return carrier
+	}
+	return carrierSegments[number[:3]]
+}
+
+// Carrier 返回号码所属的运营商，非中国大陆号码返回 CarrierUnknown
+func (p *PhoneNumber) Carrier() Carrier {
+	if !p.InChineseMainland() {
+		return CarrierUnknown
+	}
+	return LookupCarrier(p.Number)
+}
+
+// String 实现 Stringer 接口
+func (c Carrier) String() string {
+	if c == CarrierUnknown {
+		return "unknown"
+	}
+	return string(c)
+}
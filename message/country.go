@@ -0,0 +1,173 @@
+package message
+
+import "strings"
+
+// Country 表示国家或地区的号码规则，用于解析和校验手机号码
+type Country struct {
+	// ISO 3166-1 二位代码，如 CN、GB
+	Region string
+
+	// 名称
+	Name string
+
+	// 国际区号
+	CallingCode int
+
+	// 国内格式中手机号码前的长途前缀，如英国的 0，国际格式中需要去掉
+	NationalPrefix string
+
+	// 手机号码（不含国际区号和国内长途前缀）的有效长度
+	Lengths []int
+
+	// 手机号码的号段前缀，为空时不校验号段
+	MobilePrefixes []string
+
+	// 格式化时的分组长度，剩余的数字并入最后一组
+	Groups []int
+}
+
+// countries 内置的国家和地区号码规则，共用国际区号的国家中排在前面的为默认国家
+var countries = []*Country{
+	// 亚洲
+	{Region: "CN", Name: "China", CallingCode: 86, Lengths: []int{11}, MobilePrefixes: []string{"13", "14", "15", "16", "17", "18", "19"}, Groups: []int{3, 4, 4}},
+	{Region: "HK", Name: "Hong Kong", CallingCode: 852, Lengths: []int{8}, MobilePrefixes: []string{"4", "5", "6", "7", "8", "9"}, Groups: []int{4, 4}},
+	{Region: "MO", Name: "Macao", CallingCode: 853, Lengths: []int{8}, MobilePrefixes: []string{"6"}, Groups: []int{4, 4}},
+	{Region: "TW", Name: "Taiwan", CallingCode: 886, NationalPrefix: "0", Lengths: []int{9}, MobilePrefixes: []string{"9"}, Groups: []int{3, 3, 3}},
+	{Region: "JP", Name: "Japan", CallingCode: 81, NationalPrefix: "0", Lengths: []int{10}, MobilePrefixes: []string{"70", "80", "90"}, Groups: []int{2, 4, 4}},
+	{Region: "KR", Name: "South Korea", CallingCode: 82, NationalPrefix: "0", Lengths: []int{9, 10}, MobilePrefixes: []string{"1"}, Groups: []int{2, 4, 4}},
+	{Region: "SG", Name: "Singapore", CallingCode: 65, Lengths: []int{8}, MobilePrefixes: []string{"8", "9"}, Groups: []int{4, 4}},
+	{Region: "MY", Name: "Malaysia", CallingCode: 60, NationalPrefix: "0", Lengths: []int{9, 10}, MobilePrefixes: []string{"1"}, Groups: []int{2, 3, 4}},
+	{Region: "TH", Name: "Thailand", CallingCode: 66, NationalPrefix: "0", Lengths: []int{9}, MobilePrefixes: []string{"6", "8", "9"}, Groups: []int{2, 3, 4}},
+	{Region: "VN", Name: "Vietnam", CallingCode: 84, NationalPrefix: "0", Lengths: []int{9}, MobilePrefixes: []string{"3", "5", "7", "8", "9"}, Groups: []int{2, 3, 4}},
+	{Region: "PH", Name: "Philippines", CallingCode: 63, NationalPrefix: "0", Lengths: []int{10}, MobilePrefixes: []string{"9"}, Groups: []int{3, 3, 4}},
+	{Region: "ID", Name: "Indonesia", CallingCode: 62, NationalPrefix: "0", Lengths: []int{9, 10, 11, 12}, MobilePrefixes: []string{"8"}, Groups: []int{3, 4, 4}},
+	{Region: "IN", Name: "India", CallingCode: 91, NationalPrefix: "0", Lengths: []int{10}, MobilePrefixes: []string{"6", "7", "8", "9"}, Groups: []int{5, 5}},
+	{Region: "PK", Name: "Pakistan", CallingCode: 92, NationalPrefix: "0", Lengths: []int{10}, MobilePrefixes: []string{"3"}, Groups: []int{3, 7}},
+	{Region: "BD", Name: "Bangladesh", CallingCode: 880, NationalPrefix: "0", Lengths: []int{10}, MobilePrefixes: []string{"1"}, Groups: []int{4, 6}},
+	{Region: "AE", Name: "United Arab Emirates", CallingCode: 971, NationalPrefix: "0", Lengths: []int{9}, MobilePrefixes: []string{"5"}, Groups: []int{2, 3, 4}},
+	{Region: "SA", Name: "Saudi Arabia", CallingCode: 966, NationalPrefix: "0", Lengths: []int{9}, MobilePrefixes: []string{"5"}, Groups: []int{2, 3, 4}},
+	{Region: "TR", Name: "Turkey", CallingCode: 90, NationalPrefix: "0", Lengths: []int{10}, MobilePrefixes: []string{"5"}, Groups: []int{3, 3, 4}},
+	{Region: "IL", Name: "Israel", CallingCode: 972, NationalPrefix: "0", Lengths: []int{9}, MobilePrefixes: []string{"5"}, Groups: []int{2, 3, 4}},
+
+	// 欧洲
+	{Region: "RU", Name: "Russia", CallingCode: 7, NationalPrefix: "8", Lengths: []int{10}, MobilePrefixes: []string{"9"}, Groups: []int{3, 3, 4}},
+	{Region: "KZ", Name: "Kazakhstan", CallingCode: 7, NationalPrefix: "8", Lengths: []int{10}, MobilePrefixes: []string{"7"}, Groups: []int{3, 3, 4}},
+	{Region: "GB", Name: "United Kingdom", CallingCode: 44, NationalPrefix: "0", Lengths: []int{10}, MobilePrefixes: []string{"7"}, Groups: []int{4, 6}},
+	{Region: "DE", Name: "Germany", CallingCode: 49, NationalPrefix: "0", Lengths: []int{10, 11}, MobilePrefixes: []string{"15", "16", "17"}, Groups: []int{3, 8}},
+	{Region: "FR", Name: "France", CallingCode: 33, NationalPrefix: "0", Lengths: []int{9}, MobilePrefixes: []string{"6", "7"}, Groups: []int{1, 2, 2, 2, 2}},
+	{Region: "IT", Name: "Italy", CallingCode: 39, Lengths: []int{9, 10}, MobilePrefixes: []string{"3"}, Groups: []int{3, 3, 4}},
+	{Region: "ES", Name: "Spain", CallingCode: 34, Lengths: []int{9}, MobilePrefixes: []string{"6", "7"}, Groups: []int{3, 3, 3}},
+	{Region: "NL", Name: "Netherlands", CallingCode: 31, NationalPrefix: "0", Lengths: []int{9}, MobilePrefixes: []string{"6"}, Groups: []int{1, 8}},
+	{Region: "BE", Name: "Belgium", CallingCode: 32, NationalPrefix: "0", Lengths: []int{9}, MobilePrefixes: []string{"4"}, Groups: []int{3, 2, 2, 2}},
+	{Region: "CH", Name: "Switzerland", CallingCode: 41, NationalPrefix: "0", Lengths: []int{9}, MobilePrefixes: []string{"7"}, Groups: []int{2, 3, 2, 2}},
+	{Region: "SE", Name: "Sweden", CallingCode: 46, NationalPrefix: "0", Lengths: []int{9}, MobilePrefixes: []string{"7"}, Groups: []int{2, 3, 2, 2}},
+	{Region: "PL", Name: "Poland", CallingCode: 48, Lengths: []int{9}, MobilePrefixes: []string{"4", "5", "6", "7", "8"}, Groups: []int{3, 3, 3}},
+	{Region: "PT", Name: "Portugal", CallingCode: 351, Lengths: []int{9}, MobilePrefixes: []string{"9"}, Groups: []int{3, 3, 3}},
+	{Region: "IE", Name: "Ireland", CallingCode: 353, NationalPrefix: "0", Lengths: []int{9}, MobilePrefixes: []string{"8"}, Groups: []int{2, 3, 4}},
+
+	// 美洲，加拿大按区号区分
+	{Region: "US", Name: "United States", CallingCode: 1, Lengths: []int{10}, Groups: []int{3, 3, 4}},
+	{Region: "CA", Name: "Canada", CallingCode: 1, Lengths: []int{10}, MobilePrefixes: []string{
+		"204", "226", "236", "249", "250", "263", "289", "306", "343", "354", "365", "367", "368", "382", "403", "416", "418", "428",
+		"431", "437", "438", "450", "468", "474", "506", "514", "519", "548", "579", "581", "584", "587", "604", "613", "639", "647",
+		"672", "683", "705", "709", "742", "753", "778", "780", "782", "807", "819", "825", "867", "873", "879", "902", "905",
+	}, Groups: []int{3, 3, 4}},
+	{Region: "MX", Name: "Mexico", CallingCode: 52, Lengths: []int{10}, Groups: []int{2, 4, 4}},
+	{Region: "BR", Name: "Brazil", CallingCode: 55, NationalPrefix: "0", Lengths: []int{11}, Groups: []int{2, 5, 4}},
+	{Region: "AR", Name: "Argentina", CallingCode: 54, NationalPrefix: "0", Lengths: []int{11}, MobilePrefixes: []string{"9"}, Groups: []int{1, 2, 4, 4}},
+	{Region: "CO", Name: "Colombia", CallingCode: 57, Lengths: []int{10}, MobilePrefixes: []string{"3"}, Groups: []int{3, 3, 4}},
+	{Region: "CL", Name: "Chile", CallingCode: 56, Lengths: []int{9}, MobilePrefixes: []string{"9"}, Groups: []int{1, 4, 4}},
+	{Region: "PE", Name: "Peru", CallingCode: 51, Lengths: []int{9}, MobilePrefixes: []string{"9"}, Groups: []int{3, 3, 3}},
+
+	// 大洋洲和非洲
+	{Region: "AU", Name: "Australia", CallingCode: 61, NationalPrefix: "0", Lengths: []int{9}, MobilePrefixes: []string{"4"}, Groups: []int{3, 3, 3}},
+	{Region: "NZ", Name: "New Zealand", CallingCode: 64, NationalPrefix: "0", Lengths: []int{8, 9, 10}, MobilePrefixes: []string{"2"}, Groups: []int{2, 3, 4}},
+	{Region: "ZA", Name: "South Africa", CallingCode: 27, NationalPrefix: "0", Lengths: []int{9}, MobilePrefixes: []string{"6", "7", "8"}, Groups: []int{2, 3, 4}},
+	{Region: "NG", Name: "Nigeria", CallingCode: 234, NationalPrefix: "0", Lengths: []int{10}, MobilePrefixes: []string{"7", "8", "9"}, Groups: []int{3, 3, 4}},
+	{Region: "EG", Name: "Egypt", CallingCode: 20, NationalPrefix: "0", Lengths: []int{10}, MobilePrefixes: []string{"1"}, Groups: []int{2, 4, 4}},
+	{Region: "KE", Name: "Kenya", CallingCode: 254, NationalPrefix: "0", Lengths: []int{9}, MobilePrefixes: []string{"1", "7"}, Groups: []int{3, 6}},
+}
+
+// LookupCountry 按 ISO 3166-1 二位代码查找号码规则，不区分大小写
+func LookupCountry(region string) (*Country, bool) {
+	region = strings.ToUpper(region)
+	for _, c := range countries {
+		if c.Region == region {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// CountriesByCallingCode 返回使用该国际区号的国家和地区，默认国家排在最前面
+func CountriesByCallingCode(code int) []*Country {
+	var result []*Country
+	for _, c := range countries {
+		if c.CallingCode == code {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// Countries 返回所有内置的号码规则
+func Countries() []*Country {
+	result := make([]*Country, len(countries))
+	copy(result, countries)
+	return result
+}
+
+// IsValid 判断不含国际区号和国内长途前缀的号码是否为该国家的有效手机号码
+func (c *Country) IsValid(national string) bool {
+	if !isDigits(national) {
+		return false
+	}
+
+	validLength := false
+	for _, length := range c.Lengths {
+		if len(national) == length {
+			validLength = true
+			break
+		}
+	}
+	if !validLength {
+		return false
+	}
+
+	if len(c.MobilePrefixes) == 0 {
+		return true
+	}
+	for _, prefix := range c.MobilePrefixes {
+		if strings.HasPrefix(national, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// format 按分组长度格式化号码
+func (c *Country) format(national string) string {
+	var parts []string
+	rest := national
+	for i, size := range c.Groups {
+		if i == len(c.Groups)-1 || len(rest) <= size {
+			break
+		}
+		parts = append(parts, rest[:size])
+		rest = rest[size:]
+	}
+	return strings.Join(append(parts, rest), " ")
+}
+
+// isDigits 判断字符串是否只包含数字
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -10,6 +10,10 @@ const (
 	VoiceMessage MessageType = "voice"
 )
 
+// MessageResolver 按网关解析消息
+// EasySms 每尝试一个网关前都会在消息副本上调用，可以为该网关设置不同的内容、模板和数据
+type MessageResolver func(gateway string, msg *Message)
+
 // Message 表示短信消息
 type Message struct {
 	// 消息类型
@@ -26,6 +30,9 @@ type Message struct {
 
 	// 支持的网关
 	Gateways []string
+
+	// 按网关解析消息的函数，按添加顺序调用
+	resolvers []MessageResolver
 }
 
 // NewMessage 创建一个新的消息
@@ -70,6 +77,48 @@ func (m *Message) GetData() map[string]any {
 	return m.Data
 }
 
+// SetContentFunc 设置按网关生成消息内容的函数
+func (m *Message) SetContentFunc(fn func(gateway string) string) *Message {
+	return m.AddResolver(func(gateway string, msg *Message) {
+		msg.Content = fn(gateway)
+	})
+}
+
+// SetTemplateFunc 设置按网关生成模板ID的函数
+func (m *Message) SetTemplateFunc(fn func(gateway string) string) *Message {
+	return m.AddResolver(func(gateway string, msg *Message) {
+		msg.Template = fn(gateway)
+	})
+}
+
+// SetDataFunc 设置按网关生成模板数据的函数
+func (m *Message) SetDataFunc(fn func(gateway string) map[string]any) *Message {
+	return m.AddResolver(func(gateway string, msg *Message) {
+		msg.Data = fn(gateway)
+	})
+}
+
+// AddResolver 添加按网关解析消息的函数
+func (m *Message) AddResolver(resolver MessageResolver) *Message {
+	m.resolvers = append(m.resolvers, resolver)
+	return m
+}
+
+// HasResolver 判断消息是否需要按网关解析
+func (m *Message) HasResolver() bool {
+	return len(m.resolvers) > 0
+}
+
+// Resolve 返回为指定网关解析后的消息副本，副本不再包含解析函数
+func (m *Message) Resolve(gateway string) *Message {
+	resolved := m.Clone()
+	resolved.resolvers = nil
+	for _, resolver := range m.resolvers {
+		resolver(gateway, resolved)
+	}
+	return resolved
+}
+
 // SetGateways 设置支持的网关
 func (m *Message) SetGateways(gateways []string) *Message {
 	m.Gateways = gateways
@@ -91,3 +140,28 @@ func (m *Message) SetType(messageType MessageType) *Message {
 func (m *Message) GetType() MessageType {
 	return m.Type
 }
+
+// Clone 复制消息
+// 模板数据和网关列表会被浅拷贝，网关修改副本的数据不会影响原消息
+func (m *Message) Clone() *Message {
+	clone := *m
+
+	if m.Data != nil {
+		clone.Data = make(map[string]any, len(m.Data))
+		for k, v := range m.Data {
+			clone.Data[k] = v
+		}
+	}
+
+	if m.Gateways != nil {
+		clone.Gateways = make([]string, len(m.Gateways))
+		copy(clone.Gateways, m.Gateways)
+	}
+
+	if m.resolvers != nil {
+		clone.resolvers = make([]MessageResolver, len(m.resolvers))
+		copy(clone.resolvers, m.resolvers)
+	}
+
+	return &clone
+}
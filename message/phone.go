@@ -1,13 +1,20 @@
 package message
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 )
 
+// ErrInvalidPhoneNumber 号码无法解析或不是有效的手机号码
+var ErrInvalidPhoneNumber = errors.New("invalid phone number")
+
 // PhoneNumber 表示电话号码
 type PhoneNumber struct {
 	Number  string // 电话号码
 	IDDCode int    // 国际区号
+	Region  string // ISO 3166-1 二位国家代码，由 ParsePhoneNumber 设置
 }
 
 // NewPhoneNumber 创建一个新的电话号码
@@ -22,6 +29,101 @@ func NewPhoneNumber(number string, iddCode ...int) *PhoneNumber {
 	}
 }
 
+// ParsePhoneNumber 解析并校验用户输入的手机号码
+// 支持 "+44 7911 123456"、"0044 7911 123456" 等国际格式，号码中的空格、横线、括号和点会被忽略；
+// 不带国际区号的号码按 defaultRegion（如 "CN"）指定的国家解析，并去掉国内长途前缀
+func ParsePhoneNumber(raw string, defaultRegion ...string) (*PhoneNumber, error) {
+	digits, international, err := normalizePhoneNumber(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if international {
+		// 国际区号为 1 到 3 位且互不为前缀
+		for size := 1; size <= 3 && size < len(digits); size++ {
+			code, _ := strconv.Atoi(digits[:size])
+			if candidates := CountriesByCallingCode(code); len(candidates) > 0 {
+				return matchPhoneNumber(raw, digits[size:], candidates)
+			}
+		}
+		return nil, fmt.Errorf("%w %q: unknown calling code", ErrInvalidPhoneNumber, raw)
+	}
+
+	if len(defaultRegion) == 0 || defaultRegion[0] == "" {
+		return nil, fmt.Errorf("%w %q: missing calling code", ErrInvalidPhoneNumber, raw)
+	}
+	country, ok := LookupCountry(defaultRegion[0])
+	if !ok {
+		return nil, fmt.Errorf("%w %q: unknown region %s", ErrInvalidPhoneNumber, raw, defaultRegion[0])
+	}
+
+	// 用户输入的国内号码可能带有国际区号但缺少 + 号
+	code := strconv.Itoa(country.CallingCode)
+	if !country.IsValid(stripNationalPrefix(country, digits)) && strings.HasPrefix(digits, code) {
+		if phone, err := matchPhoneNumber(raw, digits[len(code):], []*Country{country}); err == nil {
+			return phone, nil
+		}
+	}
+	return matchPhoneNumber(raw, digits, []*Country{country})
+}
+
+// normalizePhoneNumber 去掉分隔符，返回数字和是否为国际格式
+func normalizePhoneNumber(raw string) (string, bool, error) {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(raw) {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+		case r == ' ' || r == '-' || r == '(' || r == ')' || r == '.' || r == '/':
+		default:
+			return "", false, fmt.Errorf("%w %q: unexpected character %q", ErrInvalidPhoneNumber, raw, r)
+		}
+	}
+
+	digits := b.String()
+	international := strings.HasPrefix(strings.TrimSpace(raw), "+")
+	if !international && strings.HasPrefix(digits, "00") {
+		digits = digits[2:]
+		international = true
+	}
+	if digits == "" {
+		return "", false, fmt.Errorf("%w %q: no digits", ErrInvalidPhoneNumber, raw)
+	}
+	return digits, international, nil
+}
+
+// matchPhoneNumber 在候选国家中匹配号码，号段匹配的国家优先于不校验号段的默认国家
+func matchPhoneNumber(raw, national string, candidates []*Country) (*PhoneNumber, error) {
+	var fallback *Country
+	var fallbackNumber string
+	for _, c := range candidates {
+		number := stripNationalPrefix(c, national)
+		if !c.IsValid(number) {
+			continue
+		}
+		if len(c.MobilePrefixes) > 0 {
+			return &PhoneNumber{Number: number, IDDCode: c.CallingCode, Region: c.Region}, nil
+		}
+		if fallback == nil {
+			fallback, fallbackNumber = c, number
+		}
+	}
+
+	if fallback != nil {
+		return &PhoneNumber{Number: fallbackNumber, IDDCode: fallback.CallingCode, Region: fallback.Region}, nil
+	}
+	return nil, fmt.Errorf("%w %q: not a valid mobile number in %s", ErrInvalidPhoneNumber, raw, candidates[0].Region)
+}
+
+// stripNationalPrefix 去掉号码中多余的国内长途前缀
+func stripNationalPrefix(c *Country, national string) string {
+	if c.NationalPrefix != "" && !c.IsValid(national) && strings.HasPrefix(national, c.NationalPrefix) {
+		return national[len(c.NationalPrefix):]
+	}
+	return national
+}
+
 // GetNumber 获取电话号码
 func (p *PhoneNumber) GetNumber() string {
 	return p.Number
@@ -53,6 +155,60 @@ func (p *PhoneNumber) InChineseMainland() bool {
 	return p.IDDCode == 0 || p.IDDCode == 86
 }
 
+// Country 返回号码所属国家的号码规则，未设置国际区号时按中国大陆处理
+func (p *PhoneNumber) Country() (*Country, bool) {
+	if p.Region != "" {
+		return LookupCountry(p.Region)
+	}
+
+	code := p.IDDCode
+	if code == 0 {
+		code = 86
+	}
+	for _, c := range CountriesByCallingCode(code) {
+		if c.IsValid(p.Number) {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// Validate 校验号码是否为有效的手机号码，没有对应国家规则的号码视为无效
+func (p *PhoneNumber) Validate() error {
+	c, ok := p.Country()
+	if !ok || !c.IsValid(p.Number) {
+		return fmt.Errorf("%w %q", ErrInvalidPhoneNumber, p.E164())
+	}
+	return nil
+}
+
+// E164 获取 E.164 格式的号码 (+447911123456)，未设置国际区号时按中国大陆处理
+func (p *PhoneNumber) E164() string {
+	code := p.IDDCode
+	if code == 0 {
+		code = 86
+	}
+	return fmt.Sprintf("+%d%s", code, p.Number)
+}
+
+// NationalFormat 获取国内格式的号码 (07911 123456)
+func (p *PhoneNumber) NationalFormat() string {
+	c, ok := p.Country()
+	if !ok {
+		return p.Number
+	}
+	return c.NationalPrefix + c.format(p.Number)
+}
+
+// InternationalFormat 获取国际格式的号码 (+44 7911 123456)
+func (p *PhoneNumber) InternationalFormat() string {
+	c, ok := p.Country()
+	if !ok {
+		return p.E164()
+	}
+	return fmt.Sprintf("+%d %s", c.CallingCode, c.format(p.Number))
+}
+
 // String 实现 Stringer 接口
 func (p *PhoneNumber) String() string {
 	if p.IDDCode > 0 {
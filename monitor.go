@@ -0,0 +1,243 @@
+package easysms
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/anhao/go-easy-sms/gateway"
+	"github.com/anhao/go-easy-sms/strategy"
+)
+
+// DefaultBalanceCheckInterval 余额监控的默认检查间隔
+const DefaultBalanceCheckInterval = 10 * time.Minute
+
+// 网关配置中的余额阈值，单位与 gateway.Balance 的 Unit 相同
+const (
+	// BalanceThresholdKey 余额低于等于该值时告警
+	BalanceThresholdKey = "balance_threshold"
+	// BalanceCriticalKey 余额低于等于该值时告警，并在策略支持降级（见 strategy.Demotes）时降级网关
+	BalanceCriticalKey = "balance_critical"
+)
+
+// BalanceLevel 表示网关余额的告警级别
+type BalanceLevel int
+
+const (
+	// BalanceOK 余额充足
+	BalanceOK BalanceLevel = iota
+	// BalanceLow 余额低于 balance_threshold
+	BalanceLow
+	// BalanceCritical 余额低于 balance_critical
+	BalanceCritical
+)
+
+// String 实现 Stringer 接口
+func (l BalanceLevel) String() string {
+	switch l {
+	case BalanceLow:
+		return "low"
+	case BalanceCritical:
+		return "critical"
+	default:
+		return "ok"
+	}
+}
+
+// BalanceAlert 表示一次余额告警
+type BalanceAlert struct {
+	// 网关名称
+	Gateway string
+
+	// 告警级别，余额恢复时为 BalanceOK
+	Level BalanceLevel
+
+	// 触发告警的阈值
+	Threshold float64
+
+	// 查询到的余额
+	Balance *gateway.Balance
+
+	// 网关是否已在策略中被降级
+	Demoted bool
+}
+
+// BalanceMonitor 定期查询网关余额，余额低于网关配置中的阈值时告警
+// 只有配置了 balance_threshold 或 balance_critical 的网关会被检查；
+// 告警级别变化时才调用 OnAlert，避免同一级别重复告警
+type BalanceMonitor struct {
+	sms *EasySms
+
+	// 检查间隔，默认 DefaultBalanceCheckInterval
+	Interval time.Duration
+
+	// 告警级别变化（包括恢复为 BalanceOK）时的回调
+	OnAlert func(alert BalanceAlert)
+
+	levels  map[string]BalanceLevel
+	demoted map[string]strategy.Demoter // 由监控降级的网关及降级所在的策略
+	mu      sync.Mutex
+}
+
+// NewBalanceMonitor 创建余额监控，告警级别变化时调用 fn
+// 余额低于 balance_critical 时，如果 EasySms 的策略支持降级（如 strategy.DemoteStrategy 或包装它的 RuleStrategy），
+// 网关会被降级到末尾，余额恢复后自动还原；监控只还原由它降级的网关，不影响手动降级
+func (e *EasySms) NewBalanceMonitor(fn func(alert BalanceAlert)) *BalanceMonitor {
+	return &BalanceMonitor{
+		sms:      e,
+		Interval: DefaultBalanceCheckInterval,
+		OnAlert:  fn,
+		levels:   make(map[string]BalanceLevel),
+		demoted:  make(map[string]strategy.Demoter),
+	}
+}
+
+// Level 返回网关最近一次检查的告警级别
+func (m *BalanceMonitor) Level(gatewayName string) BalanceLevel {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.levels[gatewayName]
+}
+
+// Check 查询一次余额并与阈值比较，查询失败的网关保持上一次的告警级别
+func (m *BalanceMonitor) Check(ctx context.Context) {
+	var names []string
+	for _, name := range m.sms.gatewayNames() {
+		cfg := m.sms.currentConfig().GatewayConfigs[name]
+		_, hasThreshold := configFloat(cfg, BalanceThresholdKey)
+		_, hasCritical := configFloat(cfg, BalanceCriticalKey)
+		if hasThreshold || hasCritical {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	results, err := m.sms.queryBalances(ctx, names)
+	if err != nil {
+		m.sms.logger.Warning("Balance check incomplete: %v", err)
+	}
+
+	for name, result := range results {
+		if result.Error != nil {
+			continue
+		}
+
+		cfg := m.sms.currentConfig().GatewayConfigs[name]
+		threshold, hasThreshold := configFloat(cfg, BalanceThresholdKey)
+		critical, hasCritical := configFloat(cfg, BalanceCriticalKey)
+
+		amount := result.Balance.Amount
+		alert := BalanceAlert{Gateway: name, Level: BalanceOK, Balance: result.Balance}
+		switch {
+		case hasCritical && amount <= critical:
+			alert.Level = BalanceCritical
+			alert.Threshold = critical
+		case hasThreshold && amount <= threshold:
+			alert.Level = BalanceLow
+			alert.Threshold = threshold
+		}
+
+		alert.Demoted = m.demote(name, alert.Level == BalanceCritical)
+		m.update(alert)
+	}
+}
+
+// Run 按检查间隔持续检查，直到 ctx 结束，启动时立即检查一次
+func (m *BalanceMonitor) Run(ctx context.Context) {
+	interval := m.Interval
+	if interval <= 0 {
+		interval = DefaultBalanceCheckInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	m.Check(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Check(ctx)
+		}
+	}
+}
+
+// demote 在策略支持时降级或还原网关，返回网关是否处于降级状态
+// 只还原由监控降级的网关，调用方手动降级的网关保持原样；
+// 策略被 UpdateConfig 替换后，在原策略上还原，并在新策略上重新降级
+func (m *BalanceMonitor) demote(gatewayName string, critical bool) bool {
+	var demoter strategy.Demoter
+	if s := m.sms.currentStrategy(); strategy.Demotes(s) {
+		demoter = s.(strategy.Demoter)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if previous, ok := m.demoted[gatewayName]; ok {
+		if critical && previous == demoter {
+			return true
+		}
+		previous.Restore(gatewayName)
+		delete(m.demoted, gatewayName)
+	}
+
+	if !critical || demoter == nil {
+		return false
+	}
+
+	if !isDemoted(demoter, gatewayName) {
+		demoter.Demote(gatewayName)
+		m.demoted[gatewayName] = demoter
+	}
+	return true
+}
+
+// isDemoted 判断网关是否已被降级，策略无法查询降级状态时返回 false
+func isDemoted(demoter strategy.Demoter, gatewayName string) bool {
+	if d, ok := demoter.(interface{ Demoted(gateway string) bool }); ok {
+		return d.Demoted(gatewayName)
+	}
+	return false
+}
+
+// update 记录告警级别，级别变化时调用 OnAlert
+func (m *BalanceMonitor) update(alert BalanceAlert) {
+	m.mu.Lock()
+	previous := m.levels[alert.Gateway]
+	m.levels[alert.Gateway] = alert.Level
+	m.mu.Unlock()
+
+	if previous == alert.Level {
+		return
+	}
+
+	if alert.Level == BalanceOK {
+		m.sms.logger.Info("Balance of gateway %s recovered: %v %s", alert.Gateway, alert.Balance.Amount, alert.Balance.Unit)
+	} else {
+		m.sms.logger.Warning("Balance of gateway %s is %s: %v %s (threshold %v)", alert.Gateway, alert.Level, alert.Balance.Amount, alert.Balance.Unit, alert.Threshold)
+	}
+
+	if m.OnAlert != nil {
+		m.OnAlert(alert)
+	}
+}
+
+// configFloat 获取网关配置中的数值，兼容整数、浮点数和字符串
+func configFloat(cfg map[string]any, key string) (float64, bool) {
+	switch v := cfg[key].(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case string:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f, true
+		}
+	}
+	return 0, false
+}
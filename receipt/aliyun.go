@@ -0,0 +1,53 @@
+package receipt
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// AliyunParser 阿里云短信状态报告解析器
+// 阿里云以 JSON 数组的形式批量推送 SmsReport
+type AliyunParser struct{}
+
+// NewAliyunParser 创建一个新的阿里云状态报告解析器
+func NewAliyunParser(config map[string]any) *AliyunParser {
+	return &AliyunParser{}
+}
+
+// Parse 实现 ReceiptParser 接口
+func (p *AliyunParser) Parse(r *http.Request) ([]*DeliveryReport, error) {
+	items, err := decodeJSONItems(r)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]*DeliveryReport, 0, len(items))
+	for _, item := range items {
+		errCode := stringValue(item["err_code"])
+
+		status := StatusDelivered
+		if success, _ := item["success"].(bool); !success {
+			status = failedStatus(errCode)
+		}
+
+		reports = append(reports, &DeliveryReport{
+			Gateway:      "aliyun",
+			MessageID:    stringValue(item["biz_id"]),
+			Phone:        stringValue(item["phone_number"]),
+			Status:       status,
+			ErrorCode:    errCode,
+			ErrorMessage: stringValue(item["err_msg"]),
+			SubmitTime:   parseTime("2006-01-02 15:04:05", stringValue(item["send_time"])),
+			ReportTime:   parseTime("2006-01-02 15:04:05", stringValue(item["report_time"])),
+			Raw:          item,
+		})
+	}
+
+	return reports, nil
+}
+
+// Respond 实现 Responder 接口，阿里云要求返回 code 为 0 的 JSON
+func (p *AliyunParser) Respond(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "msg": "成功"})
+}
@@ -0,0 +1,49 @@
+package receipt
+
+import (
+	"net/http"
+)
+
+// ChuanglanParser 创蓝短信状态报告解析器
+// 创蓝以查询参数的形式推送状态报告，并携带后台配置的 receiver 和 pswd 用于校验
+type ChuanglanParser struct {
+	receiver string
+	password string
+}
+
+// NewChuanglanParser 创建一个新的创蓝状态报告解析器
+// 配置了 receipt_receiver 和 receipt_password 时校验回调中的 receiver 和 pswd
+func NewChuanglanParser(config map[string]any) *ChuanglanParser {
+	return &ChuanglanParser{
+		receiver: configString(config, "receipt_receiver"),
+		password: configString(config, "receipt_password"),
+	}
+}
+
+// Parse 实现 ReceiptParser 接口
+func (p *ChuanglanParser) Parse(r *http.Request) ([]*DeliveryReport, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	if p.receiver != "" && (r.Form.Get("receiver") != p.receiver || r.Form.Get("pswd") != p.password) {
+		return nil, ErrInvalidSignature
+	}
+
+	raw := make(map[string]any, len(r.Form))
+	for key := range r.Form {
+		raw[key] = r.Form.Get(key)
+	}
+
+	code := r.Form.Get("status")
+	return []*DeliveryReport{{
+		Gateway:      "chuanglan",
+		MessageID:    r.Form.Get("msgid"),
+		Phone:        r.Form.Get("mobile"),
+		Status:       statusOf(code),
+		ErrorCode:    code,
+		ErrorMessage: r.Form.Get("statusDesc"),
+		ReportTime:   parseTime("0601021504", r.Form.Get("reportTime")),
+		Raw:          raw,
+	}}, nil
+}
@@ -0,0 +1,53 @@
+package receipt
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// QcloudParser 腾讯云短信状态报告解析器
+// 腾讯云以 JSON 数组的形式推送短信下发状态
+type QcloudParser struct{}
+
+// NewQcloudParser 创建一个新的腾讯云状态报告解析器
+func NewQcloudParser(config map[string]any) *QcloudParser {
+	return &QcloudParser{}
+}
+
+// Parse 实现 ReceiptParser 接口
+func (p *QcloudParser) Parse(r *http.Request) ([]*DeliveryReport, error) {
+	items, err := decodeJSONItems(r)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]*DeliveryReport, 0, len(items))
+	for _, item := range items {
+		errCode := stringValue(item["errmsg"])
+
+		status := StatusDelivered
+		if !strings.EqualFold(stringValue(item["report_status"]), "SUCCESS") {
+			status = failedStatus(errCode)
+		}
+
+		reports = append(reports, &DeliveryReport{
+			Gateway:      "qcloud",
+			MessageID:    stringValue(item["sid"]),
+			Phone:        stringValue(item["mobile"]),
+			Status:       status,
+			ErrorCode:    errCode,
+			ErrorMessage: stringValue(item["description"]),
+			ReportTime:   parseTime("2006-01-02 15:04:05", stringValue(item["user_receive_time"])),
+			Raw:          item,
+		})
+	}
+
+	return reports, nil
+}
+
+// Respond 实现 Responder 接口，腾讯云要求返回 result 为 0 的 JSON
+func (p *QcloudParser) Respond(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"result": 0, "errmsg": "OK"})
+}
@@ -0,0 +1,214 @@
+package receipt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Status 表示短信的送达状态
+type Status string
+
+const (
+	// StatusDelivered 已送达
+	StatusDelivered Status = "delivered"
+	// StatusFailed 发送失败
+	StatusFailed Status = "failed"
+	// StatusExpired 超过有效期未送达
+	StatusExpired Status = "expired"
+	// StatusPending 已提交，尚未收到状态报告，仅出现在主动查询的结果中
+	StatusPending Status = "pending"
+)
+
+// ErrInvalidSignature 表示回调请求的签名校验失败
+var ErrInvalidSignature = errors.New("receipt: invalid signature")
+
+// ChinaZone 北京时间，国内服务商回调和查询接口中的时间均使用该时区
+var ChinaZone = time.FixedZone("CST", 8*3600)
+
+// DeliveryReport 是统一格式的短信状态报告
+type DeliveryReport struct {
+	// 网关名称
+	Gateway string
+
+	// 服务商返回的消息 ID，与发送结果中的 ID 对应
+	MessageID string
+
+	// 接收号码
+	Phone string
+
+	// 送达状态
+	Status Status
+
+	// 服务商的状态码或错误码
+	ErrorCode string
+
+	// 服务商的状态描述
+	ErrorMessage string
+
+	// 发送时间，服务商未提供时为零值
+	SubmitTime time.Time
+
+	// 送达（或失败）时间，服务商未提供时为零值
+	ReportTime time.Time
+
+	// 原始数据
+	Raw map[string]any
+}
+
+// ReceiptParser 将服务商推送的状态报告回调请求解析为 DeliveryReport
+// 一次回调可能包含多条状态报告
+type ReceiptParser interface {
+	Parse(r *http.Request) ([]*DeliveryReport, error)
+}
+
+// Responder 由需要特定应答内容的解析器实现，服务商据此判断回调是否处理成功
+type Responder interface {
+	Respond(w http.ResponseWriter)
+}
+
+// ParserCreator 根据网关配置创建状态报告解析器
+type ParserCreator func(config map[string]any) ReceiptParser
+
+// creators 内置网关的解析器
+var creators = map[string]ParserCreator{
+	"aliyun":     func(config map[string]any) ReceiptParser { return NewAliyunParser(config) },
+	"qcloud":     func(config map[string]any) ReceiptParser { return NewQcloudParser(config) },
+	"yunpian":    func(config map[string]any) ReceiptParser { return NewYunpianParser(config) },
+	"twilio":     func(config map[string]any) ReceiptParser { return NewTwilioParser(config) },
+	"chuanglan":  func(config map[string]any) ReceiptParser { return NewChuanglanParser(config) },
+	"submail":    func(config map[string]any) ReceiptParser { return NewSubmailParser(config) },
+	"volcengine": func(config map[string]any) ReceiptParser { return NewVolcengineParser(config) },
+}
+
+// New 根据网关名称和网关配置创建状态报告解析器
+func New(gateway string, config map[string]any) (ReceiptParser, error) {
+	creator, ok := creators[gateway]
+	if !ok {
+		return nil, fmt.Errorf("receipt: gateway %s does not support delivery reports", gateway)
+	}
+	return creator(config), nil
+}
+
+// Handler 返回处理状态报告回调的 http.Handler
+// 解析成功后调用 fn，并按服务商要求应答；解析失败时返回 400，签名错误时返回 403
+func Handler(p ReceiptParser, fn func(reports []*DeliveryReport)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reports, err := p.Parse(r)
+		if err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, ErrInvalidSignature) {
+				status = http.StatusForbidden
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		fn(reports)
+
+		if responder, ok := p.(Responder); ok {
+			responder.Respond(w)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// decodeJSONItems 解析 JSON 请求体，兼容单个对象和对象数组
+func decodeJSONItems(r *http.Request) ([]map[string]any, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '{' {
+		var item map[string]any
+		if err := unmarshal(body, &item); err != nil {
+			return nil, fmt.Errorf("receipt: failed to parse body: %w", err)
+		}
+		return []map[string]any{item}, nil
+	}
+
+	var items []map[string]any
+	if err := unmarshal(body, &items); err != nil {
+		return nil, fmt.Errorf("receipt: failed to parse body: %w", err)
+	}
+	return items, nil
+}
+
+// unmarshal 解析 JSON，数字保留为 json.Number，避免较长的消息 ID 丢失精度
+func unmarshal(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// configString 获取字符串类型的配置项
+func configString(config map[string]any, key string) string {
+	if val, ok := config[key].(string); ok {
+		return val
+	}
+	return ""
+}
+
+// stringValue 将回调数据中的值转换为字符串
+func stringValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// parseTime 按 layout 解析北京时间，解析失败时返回零值
+func parseTime(layout, value string) time.Time {
+	t, err := time.ParseInLocation(layout, value, ChinaZone)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// parseUnix 解析秒级时间戳，解析失败时返回零值
+func parseUnix(v any) time.Time {
+	sec, err := strconv.ParseInt(stringValue(v), 10, 64)
+	if err != nil || sec <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+// statusOf 根据运营商状态码判断送达状态
+// DELIVRD 为已送达，EXPIRED 为超时未送达，其余为失败
+func statusOf(code string) Status {
+	switch strings.ToUpper(code) {
+	case "DELIVRD", "DELIVERED", "SUCCESS", "0":
+		return StatusDelivered
+	case "EXPIRED":
+		return StatusExpired
+	default:
+		return StatusFailed
+	}
+}
+
+// failedStatus 根据错误码判断失败报告的状态，超时未送达的视为 StatusExpired
+func failedStatus(code string) Status {
+	if strings.EqualFold(code, "EXPIRED") {
+		return StatusExpired
+	}
+	return StatusFailed
+}
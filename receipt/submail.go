@@ -0,0 +1,65 @@
+package receipt
+
+import (
+	"crypto/md5"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+)
+
+// SubmailParser 赛邮云短信状态报告解析器
+// 赛邮云通过 SUBHOOK 以表单形式推送事件，signature 为 md5(token + SUBHOOK 密钥)
+type SubmailParser struct {
+	key string
+}
+
+// NewSubmailParser 创建一个新的赛邮云状态报告解析器
+// 配置了 subhook_key 时校验签名
+func NewSubmailParser(config map[string]any) *SubmailParser {
+	return &SubmailParser{
+		key: configString(config, "subhook_key"),
+	}
+}
+
+// Parse 实现 ReceiptParser 接口
+// 只有 delivered 和 dropped 事件会生成状态报告
+func (p *SubmailParser) Parse(r *http.Request) ([]*DeliveryReport, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	if p.key != "" {
+		sum := md5.Sum([]byte(r.PostForm.Get("token") + p.key))
+		if subtle.ConstantTimeCompare([]byte(hex.EncodeToString(sum[:])), []byte(r.PostForm.Get("signature"))) != 1 {
+			return nil, ErrInvalidSignature
+		}
+	}
+
+	code := r.PostForm.Get("report")
+
+	var status Status
+	switch r.PostForm.Get("events") {
+	case "delivered":
+		status = StatusDelivered
+	case "dropped":
+		status = failedStatus(code)
+	default:
+		return []*DeliveryReport{}, nil
+	}
+
+	raw := make(map[string]any, len(r.PostForm))
+	for key := range r.PostForm {
+		raw[key] = r.PostForm.Get(key)
+	}
+
+	return []*DeliveryReport{{
+		Gateway:      "submail",
+		MessageID:    r.PostForm.Get("send_id"),
+		Phone:        r.PostForm.Get("address"),
+		Status:       status,
+		ErrorCode:    code,
+		ErrorMessage: r.PostForm.Get("report_desc"),
+		ReportTime:   parseUnix(r.PostForm.Get("timestamp")),
+		Raw:          raw,
+	}}, nil
+}
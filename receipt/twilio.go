@@ -0,0 +1,96 @@
+package receipt
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// TwilioParser Twilio 短信状态报告解析器
+// Twilio 通过 StatusCallback 以表单形式推送消息状态，并在 X-Twilio-Signature 中携带签名
+type TwilioParser struct {
+	token       string
+	callbackURL string
+}
+
+// NewTwilioParser 创建一个新的 Twilio 状态报告解析器
+// 配置了 token 时校验签名；回调地址经过代理改写时需要通过 callback_url 指定 Twilio 请求的完整地址
+func NewTwilioParser(config map[string]any) *TwilioParser {
+	return &TwilioParser{
+		token:       configString(config, "token"),
+		callbackURL: configString(config, "callback_url"),
+	}
+}
+
+// Parse 实现 ReceiptParser 接口
+// queued、sent 等中间状态不会生成状态报告
+func (p *TwilioParser) Parse(r *http.Request) ([]*DeliveryReport, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	if p.token != "" && !p.verify(r) {
+		return nil, ErrInvalidSignature
+	}
+
+	var status Status
+	switch r.PostForm.Get("MessageStatus") {
+	case "delivered":
+		status = StatusDelivered
+	case "failed", "undelivered":
+		status = StatusFailed
+	default:
+		return []*DeliveryReport{}, nil
+	}
+
+	raw := make(map[string]any, len(r.PostForm))
+	for key := range r.PostForm {
+		raw[key] = r.PostForm.Get(key)
+	}
+
+	return []*DeliveryReport{{
+		Gateway:      "twilio",
+		MessageID:    r.PostForm.Get("MessageSid"),
+		Phone:        r.PostForm.Get("To"),
+		Status:       status,
+		ErrorCode:    r.PostForm.Get("ErrorCode"),
+		ErrorMessage: r.PostForm.Get("ErrorMessage"),
+		Raw:          raw,
+	}}, nil
+}
+
+// verify 校验签名：完整回调地址拼接按键排序的 POST 参数后，使用 Auth Token 计算 HMAC-SHA1
+func (p *TwilioParser) verify(r *http.Request) bool {
+	url := p.callbackURL
+	if url == "" {
+		scheme := "https"
+		if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+			scheme = proto
+		} else if r.TLS == nil {
+			scheme = "http"
+		}
+		url = scheme + "://" + r.Host + r.URL.RequestURI()
+	}
+
+	keys := make([]string, 0, len(r.PostForm))
+	for key := range r.PostForm {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var builder strings.Builder
+	builder.WriteString(url)
+	for _, key := range keys {
+		builder.WriteString(key)
+		builder.WriteString(r.PostForm.Get(key))
+	}
+
+	mac := hmac.New(sha1.New, []byte(p.token))
+	mac.Write([]byte(builder.String()))
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(expected), []byte(r.Header.Get("X-Twilio-Signature")))
+}
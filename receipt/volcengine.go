@@ -0,0 +1,41 @@
+package receipt
+
+import (
+	"net/http"
+)
+
+// VolcengineParser 火山引擎短信状态报告解析器
+// 火山引擎以 JSON 的形式推送短信回执，时间为秒级时间戳
+type VolcengineParser struct{}
+
+// NewVolcengineParser 创建一个新的火山引擎状态报告解析器
+func NewVolcengineParser(config map[string]any) *VolcengineParser {
+	return &VolcengineParser{}
+}
+
+// Parse 实现 ReceiptParser 接口
+func (p *VolcengineParser) Parse(r *http.Request) ([]*DeliveryReport, error) {
+	items, err := decodeJSONItems(r)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]*DeliveryReport, 0, len(items))
+	for _, item := range items {
+		code := stringValue(item["ErrorCode"])
+
+		reports = append(reports, &DeliveryReport{
+			Gateway:      "volcengine",
+			MessageID:    stringValue(item["MessageID"]),
+			Phone:        stringValue(item["PhoneNumber"]),
+			Status:       statusOf(code),
+			ErrorCode:    code,
+			ErrorMessage: stringValue(item["Description"]),
+			SubmitTime:   parseUnix(item["SendTime"]),
+			ReportTime:   parseUnix(item["ReceiveTime"]),
+			Raw:          item,
+		})
+	}
+
+	return reports, nil
+}
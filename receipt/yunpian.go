@@ -0,0 +1,62 @@
+package receipt
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// YunpianParser 云片短信状态报告解析器
+// 云片通过表单字段 sms_status 推送 JSON 数组
+type YunpianParser struct{}
+
+// NewYunpianParser 创建一个新的云片状态报告解析器
+func NewYunpianParser(config map[string]any) *YunpianParser {
+	return &YunpianParser{}
+}
+
+// Parse 实现 ReceiptParser 接口
+func (p *YunpianParser) Parse(r *http.Request) ([]*DeliveryReport, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	data := r.Form.Get("sms_status")
+	if data == "" {
+		return nil, errors.New("receipt: sms_status is required")
+	}
+
+	var items []map[string]any
+	if err := unmarshal([]byte(data), &items); err != nil {
+		return nil, fmt.Errorf("receipt: failed to parse sms_status: %w", err)
+	}
+
+	reports := make([]*DeliveryReport, 0, len(items))
+	for _, item := range items {
+		errCode := stringValue(item["error_msg"])
+
+		status := StatusDelivered
+		if !strings.EqualFold(stringValue(item["report_status"]), "SUCCESS") {
+			status = failedStatus(errCode)
+		}
+
+		reports = append(reports, &DeliveryReport{
+			Gateway:      "yunpian",
+			MessageID:    stringValue(item["sid"]),
+			Phone:        stringValue(item["mobile"]),
+			Status:       status,
+			ErrorCode:    errCode,
+			ErrorMessage: stringValue(item["error_detail"]),
+			ReportTime:   parseTime("2006-01-02 15:04:05", stringValue(item["user_receive_time"])),
+			Raw:          item,
+		})
+	}
+
+	return reports, nil
+}
+
+// Respond 实现 Responder 接口，云片要求返回 SUCCESS
+func (p *YunpianParser) Respond(w http.ResponseWriter) {
+	_, _ = w.Write([]byte("SUCCESS"))
+}
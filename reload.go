@@ -0,0 +1,178 @@
+package easysms
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/anhao/go-easy-sms/config"
+	"github.com/anhao/go-easy-sms/gateway"
+)
+
+// DefaultConfigWatchInterval 配置文件的默认检查间隔
+const DefaultConfigWatchInterval = 10 * time.Second
+
+// UpdateConfig 使用新配置替换当前配置，不需要重新创建 EasySms
+// 只重新创建配置有变化的网关，配置被删除的网关不再使用，通过 RegisterGateway 手动注册的网关保持不变；
+// cfg.Strategy 为 nil 时继续使用当前策略。配置、策略和网关原子地替换，正在进行的发送继续使用开始时的配置、策略和网关完成。
+// 存在配置无效、创建失败的网关或没有配置、驱动的默认网关时返回 *InitError，当前配置保持不变
+func (e *EasySms) UpdateConfig(cfg *config.Config) error {
+	if cfg == nil {
+		return errors.New("easysms: config is nil")
+	}
+
+	e.updateMu.Lock()
+	defer e.updateMu.Unlock()
+
+	e.mu.RLock()
+	current := make(map[string]gateway.Gateway, len(e.built))
+	manual := make(map[string]gateway.Gateway)
+	for name, gw := range e.gateways {
+		if _, ok := e.built[name]; ok {
+			current[name] = gw
+		} else {
+			manual[name] = gw
+		}
+	}
+	built := e.built
+	s := e.strategy
+	e.mu.RUnlock()
+
+	if cfg.Strategy != nil {
+		s = cfg.Strategy
+	}
+
+	// 手动注册的网关不按配置创建
+	next := *cfg
+	next.Strategy = s
+	next.GatewayConfigs = make(map[string]map[string]any, len(cfg.GatewayConfigs))
+	for name, gwCfg := range cfg.GatewayConfigs {
+		if _, ok := manual[name]; !ok {
+			next.GatewayConfigs[name] = gwCfg
+		}
+	}
+
+	gateways, configs, errs := e.createGateways(&next, current, built)
+	errs = append(errs, e.checkDefaultGateways(cfg, manual)...)
+	if len(errs) > 0 {
+		return &InitError{Errors: errs}
+	}
+
+	rebuilt := 0
+	for name, gw := range gateways {
+		if current[name] != gw {
+			rebuilt++
+		}
+	}
+	for name, gw := range manual {
+		gateways[name] = gw
+	}
+
+	next.GatewayConfigs = cfg.GatewayConfigs
+	e.mu.Lock()
+	e.config = &next
+	e.strategy = s
+	e.gateways = gateways
+	e.built = configs
+	e.mu.Unlock()
+
+	e.logger.Info("Config updated: %d gateway(s) rebuilt, %d gateway(s) in use", rebuilt, len(gateways))
+	return nil
+}
+
+// ConfigWatcher 定期检查配置文件，文件变化时重新加载并调用 UpdateConfig
+// 配置文件中的策略名称（config.Config.StrategyName）不变时继续使用当前策略
+type ConfigWatcher struct {
+	sms  *EasySms
+	path string
+
+	// 检查间隔，默认 DefaultConfigWatchInterval
+	Interval time.Duration
+
+	// 加载配置后使用该前缀的环境变量覆盖配置（见 config.Config.ApplyEnv），为空时不覆盖
+	EnvPrefix string
+
+	// 每次重新加载后的回调，加载或更新失败时 err 不为 nil，此时继续使用原配置，并在下次检查时重试
+	OnReload func(err error)
+
+	modTime time.Time
+	size    int64
+}
+
+// NewConfigWatcher 创建配置文件监视器，文件格式见 config.LoadFile
+// 创建时记录文件的当前状态，之后文件修改时才重新加载
+func (e *EasySms) NewConfigWatcher(path string) *ConfigWatcher {
+	w := &ConfigWatcher{
+		sms:      e,
+		path:     path,
+		Interval: DefaultConfigWatchInterval,
+	}
+	if info, err := os.Stat(path); err == nil {
+		w.modTime, w.size = info.ModTime(), info.Size()
+	}
+	return w
+}
+
+// Check 检查一次配置文件，文件修改时间或大小变化时重新加载，返回是否重新加载
+// 只有更新成功后才记录文件的状态，文件写入不完整或配置无效时，下次检查会重新加载
+func (w *ConfigWatcher) Check() bool {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		w.reloaded(err)
+		return false
+	}
+	if info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return false
+	}
+
+	cfg, err := config.LoadFile(w.path)
+	if err == nil && w.EnvPrefix != "" {
+		err = cfg.ApplyEnv(w.EnvPrefix)
+	}
+	if err == nil && cfg.StrategyName == w.sms.currentConfig().StrategyName {
+		// 策略名称不变时继续使用当前策略，保留熔断、权重和降级等状态
+		cfg.Strategy = nil
+	}
+	if err == nil {
+		err = w.sms.UpdateConfig(cfg)
+	}
+	if err == nil {
+		w.modTime, w.size = info.ModTime(), info.Size()
+	}
+	w.reloaded(err)
+	return true
+}
+
+// Run 按检查间隔持续检查，直到 ctx 结束
+func (w *ConfigWatcher) Run(ctx context.Context) {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultConfigWatchInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.Check()
+		}
+	}
+}
+
+// reloaded 记录重新加载的结果并调用 OnReload
+func (w *ConfigWatcher) reloaded(err error) {
+	if err != nil {
+		w.sms.logger.Error("Failed to reload config from %s: %v", w.path, err)
+	} else {
+		w.sms.logger.Info("Config reloaded from %s", w.path)
+	}
+
+	if w.OnReload != nil {
+		w.OnReload(err)
+	}
+}
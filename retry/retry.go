@@ -0,0 +1,180 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"time"
+)
+
+// 默认退避时间
+const (
+	DefaultBaseBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff  = 2 * time.Second
+)
+
+// Policy 定义了网关发送失败后的重试策略
+// 采用指数退避：第 n 次重试前等待 BaseBackoff * 2^(n-1)，不超过 MaxBackoff，并按 Jitter 比例随机缩短
+type Policy struct {
+	// 最大尝试次数（包含首次发送），小于等于 1 表示不重试
+	MaxAttempts int
+
+	// 初始退避时间，默认 100 毫秒
+	BaseBackoff time.Duration
+
+	// 最大退避时间，默认 2 秒
+	MaxBackoff time.Duration
+
+	// 抖动比例（0~1），退避时间会随机缩短至多该比例
+	Jitter float64
+
+	// 判断错误是否可重试，为空时使用 IsRetryable
+	Retryable func(err error) bool
+}
+
+// Do 按重试策略执行 fn，attempt 从 1 开始
+// 遇到不可重试的错误、达到最大尝试次数或 ctx 结束时返回最后一次的错误
+func (p *Policy) Do(ctx context.Context, fn func(attempt int) error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn(attempt)
+		if err == nil || !p.shouldRetry(ctx, attempt, err) {
+			return err
+		}
+
+		timer := time.NewTimer(p.Backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// Backoff 返回第 attempt 次失败后的等待时间
+func (p *Policy) Backoff(attempt int) time.Duration {
+	if p == nil {
+		return 0
+	}
+
+	base := p.BaseBackoff
+	if base <= 0 {
+		base = DefaultBaseBackoff
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultMaxBackoff
+	}
+
+	backoff := base
+	for i := 1; i < attempt && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+
+	if p.Jitter > 0 {
+		jitter := p.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		backoff -= time.Duration(float64(backoff) * jitter * rand.Float64())
+	}
+
+	return backoff
+}
+
+// shouldRetry 判断是否需要重试
+func (p *Policy) shouldRetry(ctx context.Context, attempt int, err error) bool {
+	if p == nil || attempt >= p.MaxAttempts || ctx.Err() != nil {
+		return false
+	}
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return IsRetryable(err)
+}
+
+// IsRetryable 判断错误是否可以重试
+// 网络错误（含超时）、服务端 5xx 和限流可以重试；
+// 实现了 Retryable() bool 的错误以其返回值为准，如无效号码等业务错误不可重试
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var r interface{ Retryable() bool }
+	if errors.As(err, &r) {
+		return r.Retryable()
+	}
+
+	// 调用方取消不重试
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// FromConfig 从网关配置项解析重试策略
+// 支持 *Policy、Policy 以及 map[string]any，map 中的时间单位为秒：
+//
+//	"retry": map[string]any{
+//		"max_attempts": 3,
+//		"base_backoff": 0.2,
+//		"max_backoff":  2,
+//		"jitter":       0.2,
+//	}
+func FromConfig(v any) *Policy {
+	switch p := v.(type) {
+	case *Policy:
+		return p
+	case Policy:
+		return &p
+	case map[string]any:
+		return &Policy{
+			MaxAttempts: toInt(p["max_attempts"]),
+			BaseBackoff: toDuration(p["base_backoff"]),
+			MaxBackoff:  toDuration(p["max_backoff"]),
+			Jitter:      toFloat(p["jitter"]),
+		}
+	}
+	return nil
+}
+
+// toInt 将配置值转换为整数
+func toInt(v any) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	}
+	return 0
+}
+
+// toFloat 将配置值转换为浮点数
+func toFloat(v any) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	}
+	return 0
+}
+
+// toDuration 将配置值转换为时间，数字按秒处理
+func toDuration(v any) time.Duration {
+	if d, ok := v.(time.Duration); ok {
+		return d
+	}
+	return time.Duration(toFloat(v) * float64(time.Second))
+}
@@ -0,0 +1,185 @@
+package easysms
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/anhao/go-easy-sms/gateway"
+	"github.com/anhao/go-easy-sms/message"
+	"github.com/anhao/go-easy-sms/receipt"
+)
+
+// 送达状态轮询的默认参数
+const (
+	// DefaultStatusPollInterval 默认轮询间隔
+	DefaultStatusPollInterval = time.Minute
+	// DefaultStatusMaxAge 默认跟踪时长，超过后不再查询
+	// 与网关的查询范围一致，更早的消息服务商已查不到
+	DefaultStatusMaxAge = gateway.StatusQueryLookback
+)
+
+// StatusPoller 轮询支持 gateway.StatusQuerier 的网关，获取已发送消息的送达状态
+// 适用于无法配置状态报告回调的账号，与 receipt 包的回调解析器输出相同的 DeliveryReport
+type StatusPoller struct {
+	sms *EasySms
+
+	// 轮询间隔，默认 DefaultStatusPollInterval
+	Interval time.Duration
+
+	// 消息的最长跟踪时长，超过后不再查询，默认 DefaultStatusMaxAge
+	MaxAge time.Duration
+
+	// 收到最终状态（送达、失败或过期）时的回调
+	OnReport func(report *receipt.DeliveryReport)
+
+	pending map[string]*trackedMessage
+	mu      sync.Mutex
+}
+
+// trackedMessage 等待最终状态的消息
+type trackedMessage struct {
+	gateway   string
+	messageID string
+	to        *message.PhoneNumber
+	sentAt    time.Time
+}
+
+// NewStatusPoller 创建送达状态轮询器，收到最终状态时调用 fn
+func (e *EasySms) NewStatusPoller(fn func(report *receipt.DeliveryReport)) *StatusPoller {
+	return &StatusPoller{
+		sms:      e,
+		Interval: DefaultStatusPollInterval,
+		MaxAge:   DefaultStatusMaxAge,
+		OnReport: fn,
+		pending:  make(map[string]*trackedMessage),
+	}
+}
+
+// Track 跟踪 Send 返回的发送结果，记录成功且网关支持状态查询的消息，返回新跟踪的消息数量
+func (p *StatusPoller) Track(to *message.PhoneNumber, results map[string]Result) int {
+	tracked := 0
+	for name, result := range results {
+		if result.Status != StatusSuccess {
+			continue
+		}
+
+		gw, err := p.sms.Gateway(name)
+		if err != nil {
+			continue
+		}
+		querier, ok := gw.(gateway.StatusQuerier)
+		if !ok {
+			continue
+		}
+
+		if messageID := querier.MessageID(result.Data); messageID != "" {
+			p.Add(name, messageID, to)
+			tracked++
+		}
+	}
+	return tracked
+}
+
+// Add 跟踪指定网关的消息
+func (p *StatusPoller) Add(gatewayName, messageID string, to *message.PhoneNumber) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.pending[gatewayName+"\x00"+messageID] = &trackedMessage{
+		gateway:   gatewayName,
+		messageID: messageID,
+		to:        to,
+		sentAt:    time.Now(),
+	}
+}
+
+// Pending 返回尚未收到最终状态的消息数量
+func (p *StatusPoller) Pending() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.pending)
+}
+
+// Poll 查询所有跟踪中的消息一次，收到最终状态或超过跟踪时长的消息不再跟踪
+func (p *StatusPoller) Poll(ctx context.Context) {
+	p.mu.Lock()
+	messages := make(map[string]*trackedMessage, len(p.pending))
+	for key, m := range p.pending {
+		messages[key] = m
+	}
+	p.mu.Unlock()
+
+	maxAge := p.MaxAge
+	if maxAge <= 0 {
+		maxAge = DefaultStatusMaxAge
+	}
+
+	for key, m := range messages {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if time.Since(m.sentAt) > maxAge {
+			p.sms.logger.Warning("Stop tracking message %s of gateway %s: no final status after %v", m.messageID, m.gateway, maxAge)
+			p.remove(key)
+			continue
+		}
+
+		gw, err := p.sms.Gateway(m.gateway)
+		if err != nil {
+			p.sms.logger.Error("Gateway %s not available: %v", m.gateway, err)
+			continue
+		}
+		querier, ok := gw.(gateway.StatusQuerier)
+		if !ok {
+			p.sms.logger.Error("Gateway %s does not support status query, stop tracking message %s", m.gateway, m.messageID)
+			p.remove(key)
+			continue
+		}
+
+		report, err := querier.QueryStatus(ctx, m.messageID, m.to)
+		if err != nil {
+			p.sms.logger.Error("Failed to query status of message %s via gateway %s: %v", m.messageID, m.gateway, err)
+			continue
+		}
+		if report.Status == receipt.StatusPending {
+			continue
+		}
+
+		// 网关返回的是驱动名称，配置了 driver 的网关实例需要使用跟踪的网关名称区分
+		report.Gateway = m.gateway
+
+		p.remove(key)
+		if p.OnReport != nil {
+			p.OnReport(report)
+		}
+	}
+}
+
+// Run 按轮询间隔持续查询，直到 ctx 结束
+func (p *StatusPoller) Run(ctx context.Context) {
+	interval := p.Interval
+	if interval <= 0 {
+		interval = DefaultStatusPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.Poll(ctx)
+		}
+	}
+}
+
+// remove 停止跟踪消息
+func (p *StatusPoller) remove(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.pending, key)
+}
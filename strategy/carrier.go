@@ -0,0 +1,66 @@
+package strategy
+
+import "github.com/anhao/go-easy-sms/message"
+
+// CarrierStrategy 是按运营商路由的策略
+// 使用基础策略排序后，将号码所属运营商配置的网关按配置顺序移到最前面，其余网关仍作为备选
+type CarrierStrategy struct {
+	wrapper
+	routes map[message.Carrier][]string
+}
+
+// NewCarrierStrategy 创建一个新的运营商路由策略
+// routes 为各运营商优先使用的网关，只调整本次可用网关的顺序，不会加入未指定的网关；
+// base 用于确定网关顺序，未指定时按原始顺序
+func NewCarrierStrategy(routes map[message.Carrier][]string, base ...Strategy) *CarrierStrategy {
+	var s Strategy = NewOrderStrategy()
+	if len(base) > 0 && base[0] != nil {
+		s = base[0]
+	}
+
+	return &CarrierStrategy{
+		wrapper: wrapper{base: s},
+		routes:  routes,
+	}
+}
+
+// Apply 实现 Strategy 接口，不知道号码时使用基础策略排序
+func (s *CarrierStrategy) Apply(gateways []string) []string {
+	return s.base.Apply(gateways)
+}
+
+// Route 实现 RoutingStrategy 接口，将号码所属运营商的网关移到最前面
+func (s *CarrierStrategy) Route(gateways []string, to *message.PhoneNumber, msg *message.Message) []string {
+	ordered := route(s.base, gateways, to, msg)
+	if to == nil {
+		return ordered
+	}
+	return prefer(ordered, s.routes[to.Carrier()])
+}
+
+// prefer 将 preferred 中出现在 gateways 里的网关按 preferred 的顺序移到最前面
+func prefer(gateways, preferred []string) []string {
+	if len(preferred) == 0 {
+		return gateways
+	}
+
+	available := make(map[string]bool, len(gateways))
+	for _, name := range gateways {
+		available[name] = true
+	}
+
+	result := make([]string, 0, len(gateways))
+	moved := make(map[string]bool, len(preferred))
+	for _, name := range preferred {
+		if available[name] && !moved[name] {
+			result = append(result, name)
+			moved[name] = true
+		}
+	}
+	for _, name := range gateways {
+		if !moved[name] {
+			result = append(result, name)
+		}
+	}
+	return result
+}
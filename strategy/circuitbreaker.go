@@ -0,0 +1,295 @@
+package strategy
+
+import (
+	"sync"
+	"time"
+
+	"github.com/anhao/go-easy-sms/gateway"
+	"github.com/anhao/go-easy-sms/message"
+)
+
+// CircuitState 表示网关的熔断状态
+type CircuitState int
+
+const (
+	// StateClosed 正常状态，网关按原顺序参与发送
+	StateClosed CircuitState = iota
+	// StateOpen 熔断状态，网关被移到末尾或被移除
+	StateOpen
+	// StateHalfOpen 半开状态，冷却结束后放行一次探测请求
+	StateHalfOpen
+)
+
+// String 实现 Stringer 接口
+func (s CircuitState) String() string {
+	switch s {
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreakerOptions 是熔断策略的配置
+type CircuitBreakerOptions struct {
+	// 连续失败多少次后熔断，默认 5，小于 0 表示不按连续失败熔断
+	FailureThreshold int
+
+	// 失败率达到多少后熔断（0~1），0 表示不按失败率熔断
+	FailureRate float64
+
+	// 统计失败率的滑动窗口大小，默认 20
+	WindowSize int
+
+	// 窗口内至少有多少次请求才计算失败率，默认 10
+	MinRequests int
+
+	// 熔断后的冷却时间，冷却结束后进入半开状态，默认 30 秒
+	CoolDown time.Duration
+
+	// 为 true 时直接移除熔断中的网关，否则移到末尾
+	DropOpen bool
+
+	// 判断发送错误是否计为网关故障，默认 IsGatewayFailure
+	IsFailure func(err error) bool
+}
+
+// IsGatewayFailure 判断错误是否反映网关的健康状况
+// 号码无效、黑名单、内容违规、模板和参数错误由调用方的输入引起，网关本身正常，不计为故障
+func IsGatewayFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	switch gateway.ClassifyError(err) {
+	case gateway.CategoryInvalidNumber, gateway.CategoryBlacklisted, gateway.CategorySensitiveContent,
+		gateway.CategoryTemplate, gateway.CategoryInvalidParam:
+		return false
+	default:
+		return true
+	}
+}
+
+// CircuitBreakerStrategy 是感知网关健康状况的熔断策略
+// 根据 EasySms 反馈的发送结果统计每个网关的连续失败次数和失败率，
+// 达到阈值后熔断网关，冷却结束后放行一次探测请求，探测成功则恢复
+type CircuitBreakerStrategy struct {
+	wrapper
+	options CircuitBreakerOptions
+	states  map[string]*circuit
+	mu      sync.Mutex
+}
+
+// circuit 记录单个网关的熔断状态
+type circuit struct {
+	state       CircuitState
+	consecutive int
+	outcomes    []bool // 滑动窗口，true 表示失败
+	next        int
+	count       int
+	failures    int
+	openedAt    time.Time
+	probeAt     time.Time
+}
+
+// NewCircuitBreakerStrategy 创建一个新的熔断策略
+// base 用于确定网关顺序，未指定时按原始顺序
+func NewCircuitBreakerStrategy(options CircuitBreakerOptions, base ...Strategy) *CircuitBreakerStrategy {
+	if options.FailureThreshold == 0 {
+		options.FailureThreshold = 5
+	}
+	if options.WindowSize <= 0 {
+		options.WindowSize = 20
+	}
+	if options.MinRequests <= 0 {
+		options.MinRequests = 10
+	}
+	if options.MinRequests > options.WindowSize {
+		options.MinRequests = options.WindowSize
+	}
+	if options.CoolDown <= 0 {
+		options.CoolDown = 30 * time.Second
+	}
+	if options.IsFailure == nil {
+		options.IsFailure = IsGatewayFailure
+	}
+
+	var s Strategy = NewOrderStrategy()
+	if len(base) > 0 && base[0] != nil {
+		s = base[0]
+	}
+
+	return &CircuitBreakerStrategy{
+		wrapper: wrapper{base: s},
+		options: options,
+		states:  make(map[string]*circuit),
+	}
+}
+
+// Apply 实现 Strategy 接口，将熔断中的网关移到末尾或移除（线程安全）
+// 如果所有网关都处于熔断状态，仍按基础策略的顺序返回，避免无网关可用
+func (s *CircuitBreakerStrategy) Apply(gateways []string) []string {
+	return s.filter(s.base.Apply(gateways))
+}
+
+// Route 实现 RoutingStrategy 接口，使用基础策略路由后处理熔断中的网关
+func (s *CircuitBreakerStrategy) Route(gateways []string, to *message.PhoneNumber, msg *message.Message) []string {
+	return s.filter(route(s.base, gateways, to, msg))
+}
+
+// routes 基础策略按收件人路由时返回 true
+func (s *CircuitBreakerStrategy) routes() bool {
+	return Routes(s.base)
+}
+
+// filter 将熔断中的网关移到末尾或移除
+func (s *CircuitBreakerStrategy) filter(ordered []string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	available := make([]string, 0, len(ordered))
+	var open []string
+	for _, name := range ordered {
+		if s.allow(name, now) {
+			available = append(available, name)
+		} else {
+			open = append(open, name)
+		}
+	}
+
+	if len(available) == 0 {
+		return open
+	}
+	if s.options.DropOpen {
+		return available
+	}
+	return append(available, open...)
+}
+
+// Report 实现 FeedbackStrategy 接口，记录网关的发送结果
+// 不计为故障的错误（见 CircuitBreakerOptions.IsFailure）说明网关正常响应，按成功处理
+func (s *CircuitBreakerStrategy) Report(gateway string, err error) {
+	s.wrapper.Report(gateway, err)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.circuit(gateway)
+	failed := s.options.IsFailure(err)
+	c.record(failed, s.options.WindowSize)
+
+	switch {
+	case !failed && c.state != StateClosed:
+		// 探测成功，恢复正常
+		s.states[gateway] = &circuit{}
+	case failed && c.state == StateHalfOpen:
+		// 探测失败，重新熔断
+		c.trip(time.Now())
+	case failed && c.state == StateClosed && s.shouldTrip(c):
+		c.trip(time.Now())
+	}
+}
+
+// State 获取网关当前的熔断状态
+func (s *CircuitBreakerStrategy) State(gateway string) CircuitState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, ok := s.states[gateway]; ok {
+		return c.state
+	}
+	return StateClosed
+}
+
+// Reset 重置网关的熔断状态
+func (s *CircuitBreakerStrategy) Reset(gateway string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.states, gateway)
+}
+
+// allow 判断网关当前是否可用，冷却结束的网关转为半开状态并放行一次探测
+func (s *CircuitBreakerStrategy) allow(gateway string, now time.Time) bool {
+	c, ok := s.states[gateway]
+	if !ok {
+		return true
+	}
+
+	switch c.state {
+	case StateOpen:
+		if now.Sub(c.openedAt) < s.options.CoolDown {
+			return false
+		}
+		c.state = StateHalfOpen
+		c.probeAt = now
+		return true
+	case StateHalfOpen:
+		// 上一次探测未得到反馈（例如被其他网关抢先发送成功）且已超过冷却时间时，再放行一次
+		if now.Sub(c.probeAt) < s.options.CoolDown {
+			return false
+		}
+		c.probeAt = now
+		return true
+	default:
+		return true
+	}
+}
+
+// shouldTrip 判断是否达到熔断条件
+func (s *CircuitBreakerStrategy) shouldTrip(c *circuit) bool {
+	if s.options.FailureThreshold > 0 && c.consecutive >= s.options.FailureThreshold {
+		return true
+	}
+
+	if s.options.FailureRate > 0 && c.count >= s.options.MinRequests {
+		return float64(c.failures)/float64(c.count) >= s.options.FailureRate
+	}
+
+	return false
+}
+
+// circuit 获取网关的熔断记录，不存在时创建
+func (s *CircuitBreakerStrategy) circuit(gateway string) *circuit {
+	c, ok := s.states[gateway]
+	if !ok {
+		c = &circuit{}
+		s.states[gateway] = c
+	}
+	return c
+}
+
+// record 记录一次发送结果
+func (c *circuit) record(failed bool, windowSize int) {
+	if failed {
+		c.consecutive++
+	} else {
+		c.consecutive = 0
+	}
+
+	if c.outcomes == nil {
+		c.outcomes = make([]bool, windowSize)
+	}
+
+	// 覆盖滑动窗口中最早的结果
+	if c.count == windowSize {
+		if c.outcomes[c.next] {
+			c.failures--
+		}
+	} else {
+		c.count++
+	}
+	c.outcomes[c.next] = failed
+	if failed {
+		c.failures++
+	}
+	c.next = (c.next + 1) % windowSize
+}
+
+// trip 熔断网关
+func (c *circuit) trip(now time.Time) {
+	c.state = StateOpen
+	c.openedAt = now
+}
@@ -0,0 +1,103 @@
+package strategy
+
+import (
+	"sync"
+
+	"github.com/anhao/go-easy-sms/message"
+)
+
+// Demoter 是支持临时降级网关的策略接口
+// 余额监控等组件通过它将即将不可用的网关移到末尾，恢复后再还原
+type Demoter interface {
+	Strategy
+
+	// Demote 降级网关
+	Demote(gateway string)
+
+	// Restore 取消网关的降级
+	Restore(gateway string)
+}
+
+// DemoteStrategy 是支持降级网关的策略
+// 使用基础策略排序后，将被降级的网关移到末尾，仍保留为最后的备选
+type DemoteStrategy struct {
+	wrapper
+	demoted map[string]bool
+	mu      sync.RWMutex
+}
+
+// NewDemoteStrategy 创建一个新的降级策略
+// base 用于确定网关顺序，未指定时按原始顺序
+func NewDemoteStrategy(base ...Strategy) *DemoteStrategy {
+	var s Strategy = NewOrderStrategy()
+	if len(base) > 0 && base[0] != nil {
+		s = base[0]
+	}
+
+	return &DemoteStrategy{
+		wrapper: wrapper{base: s},
+		demoted: make(map[string]bool),
+	}
+}
+
+// Apply 实现 Strategy 接口，将被降级的网关移到末尾（线程安全）
+func (s *DemoteStrategy) Apply(gateways []string) []string {
+	return s.demote(s.base.Apply(gateways))
+}
+
+// Route 实现 RoutingStrategy 接口，使用基础策略路由后将被降级的网关移到末尾
+func (s *DemoteStrategy) Route(gateways []string, to *message.PhoneNumber, msg *message.Message) []string {
+	return s.demote(route(s.base, gateways, to, msg))
+}
+
+// routes 基础策略按收件人路由时返回 true
+func (s *DemoteStrategy) routes() bool {
+	return Routes(s.base)
+}
+
+// demote 将被降级的网关移到末尾
+func (s *DemoteStrategy) demote(ordered []string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.demoted) == 0 {
+		return ordered
+	}
+
+	result := make([]string, 0, len(ordered))
+	var demoted []string
+	for _, name := range ordered {
+		if s.demoted[name] {
+			demoted = append(demoted, name)
+		} else {
+			result = append(result, name)
+		}
+	}
+	return append(result, demoted...)
+}
+
+// Demote 实现 Demoter 接口
+func (s *DemoteStrategy) Demote(gateway string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.demoted[gateway] = true
+}
+
+// Restore 实现 Demoter 接口
+func (s *DemoteStrategy) Restore(gateway string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.demoted, gateway)
+}
+
+// Demoted 判断网关是否被降级
+func (s *DemoteStrategy) Demoted(gateway string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.demoted[gateway]
+}
+
+// demotes 实现 Demotes 的判断，降级策略自身总是支持降级
+func (s *DemoteStrategy) demotes() bool {
+	return true
+}
@@ -0,0 +1,116 @@
+package strategy
+
+import (
+	"strings"
+
+	"github.com/anhao/go-easy-sms/message"
+)
+
+// Rule 是按收件人选择网关的路由规则
+// IDDCodes、Regions 和 Match 满足任意一项即匹配，未设置国际区号的号码按中国大陆（86）处理
+type Rule struct {
+	// 匹配的国际区号，如 86、852
+	IDDCodes []int
+
+	// 匹配的 ISO 3166-1 二位国家代码，如 CN、US，不区分大小写
+	Regions []string
+
+	// 自定义匹配函数，可以根据号码和消息判断
+	Match func(to *message.PhoneNumber, msg *message.Message) bool
+
+	// 匹配后使用的网关，按顺序尝试
+	Gateways []string
+}
+
+// matches 判断规则是否匹配收件人
+func (r *Rule) matches(to *message.PhoneNumber, msg *message.Message) bool {
+	if to != nil {
+		code := to.IDDCode
+		if code == 0 {
+			code = 86
+		}
+		for _, c := range r.IDDCodes {
+			if c == code {
+				return true
+			}
+		}
+
+		if len(r.Regions) > 0 {
+			region := to.Region
+			if region == "" {
+				if country, ok := to.Country(); ok {
+					region = country.Region
+				}
+			}
+			for _, rg := range r.Regions {
+				if strings.EqualFold(rg, region) {
+					return true
+				}
+			}
+		}
+	}
+
+	return r.Match != nil && r.Match(to, msg)
+}
+
+// RuleStrategy 是按国家或地区路由的策略
+// 按顺序匹配规则，使用第一条匹配规则的网关；没有匹配的规则时使用默认网关，
+// 默认网关为空时使用全部可用网关。规则只会选择本次可用的网关（消息指定的网关或默认网关）
+type RuleStrategy struct {
+	wrapper
+	rules    []Rule
+	fallback []string
+}
+
+// NewRuleStrategy 创建一个新的规则路由策略
+// fallback 为没有匹配的规则时使用的网关；base 用于对选出的网关排序，未指定时按规则中的顺序
+func NewRuleStrategy(rules []Rule, fallback []string, base ...Strategy) *RuleStrategy {
+	var s Strategy = NewOrderStrategy()
+	if len(base) > 0 && base[0] != nil {
+		s = base[0]
+	}
+
+	return &RuleStrategy{
+		wrapper:  wrapper{base: s},
+		rules:    rules,
+		fallback: fallback,
+	}
+}
+
+// Apply 实现 Strategy 接口，不知道号码时使用默认网关
+func (s *RuleStrategy) Apply(gateways []string) []string {
+	return s.base.Apply(s.choose(gateways, s.fallback))
+}
+
+// Route 实现 RoutingStrategy 接口，使用第一条匹配规则的网关
+func (s *RuleStrategy) Route(gateways []string, to *message.PhoneNumber, msg *message.Message) []string {
+	selected := s.fallback
+	for i := range s.rules {
+		if s.rules[i].matches(to, msg) {
+			selected = s.rules[i].Gateways
+			break
+		}
+	}
+	return route(s.base, s.choose(gateways, selected), to, msg)
+}
+
+// choose 按 selected 的顺序返回其中可用的网关，selected 为空时返回全部可用网关
+func (s *RuleStrategy) choose(gateways, selected []string) []string {
+	if len(selected) == 0 {
+		return gateways
+	}
+
+	available := make(map[string]bool, len(gateways))
+	for _, name := range gateways {
+		available[name] = true
+	}
+
+	result := make([]string, 0, len(selected))
+	for _, name := range selected {
+		if available[name] {
+			result = append(result, name)
+			available[name] = false
+		}
+	}
+	return result
+}
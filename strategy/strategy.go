@@ -2,6 +2,8 @@ package strategy
 
 import (
 	"math/rand"
+
+	"github.com/anhao/go-easy-sms/message"
 )
 
 // Strategy 定义了网关选择策略的接口
@@ -10,6 +12,100 @@ type Strategy interface {
 	Apply(gateways []string) []string
 }
 
+// FeedbackStrategy 是需要感知发送结果的策略接口
+// EasySms 每次通过网关发送后都会调用 Report 反馈结果
+type FeedbackStrategy interface {
+	Strategy
+
+	// Report 反馈网关的发送结果，err 为 nil 表示发送成功
+	Report(gateway string, err error)
+}
+
+// RoutingStrategy 是需要根据收件人和消息决定网关顺序的策略接口
+// EasySms 发送时优先调用 Route，只有网关列表时调用 Apply
+type RoutingStrategy interface {
+	Strategy
+
+	// Route 根据收件号码和消息返回排序后的网关列表
+	Route(gateways []string, to *message.PhoneNumber, msg *message.Message) []string
+}
+
+// Routes 判断策略是否按收件人路由
+// 包装其他策略的内置策略（如 RaceStrategy）在基础策略按收件人路由时才返回 true
+func Routes(s Strategy) bool {
+	if w, ok := s.(interface{ routes() bool }); ok {
+		return w.routes()
+	}
+	_, ok := s.(RoutingStrategy)
+	return ok
+}
+
+// Demotes 判断策略是否支持降级网关
+// 包装其他策略的内置策略（如 RuleStrategy）在基础策略支持降级时才返回 true
+func Demotes(s Strategy) bool {
+	if w, ok := s.(interface{ demotes() bool }); ok {
+		return w.demotes()
+	}
+	_, ok := s.(Demoter)
+	return ok
+}
+
+// route 使用策略确定网关顺序，策略按收件人路由时调用 Route
+func route(s Strategy, gateways []string, to *message.PhoneNumber, msg *message.Message) []string {
+	if rs, ok := s.(RoutingStrategy); ok {
+		return rs.Route(gateways, to, msg)
+	}
+	return s.Apply(gateways)
+}
+
+// wrapper 是包装基础策略的内置策略共用的部分
+// 将 Concurrency、Report、Demote、Restore 转发给基础策略，包装策略自己实现的方法优先
+type wrapper struct {
+	base Strategy
+}
+
+// Concurrency 实现 ConcurrentStrategy 接口，基础策略不支持并发发送时返回 1
+func (w wrapper) Concurrency() int {
+	if cs, ok := w.base.(ConcurrentStrategy); ok {
+		return cs.Concurrency()
+	}
+	return 1
+}
+
+// Report 实现 FeedbackStrategy 接口，将发送结果转发给基础策略
+func (w wrapper) Report(gateway string, err error) {
+	if fs, ok := w.base.(FeedbackStrategy); ok {
+		fs.Report(gateway, err)
+	}
+}
+
+// Demote 实现 Demoter 接口，基础策略支持降级时转发
+func (w wrapper) Demote(gateway string) {
+	if d, ok := w.base.(Demoter); ok {
+		d.Demote(gateway)
+	}
+}
+
+// Restore 实现 Demoter 接口，基础策略支持降级时转发
+func (w wrapper) Restore(gateway string) {
+	if d, ok := w.base.(Demoter); ok {
+		d.Restore(gateway)
+	}
+}
+
+// Demoted 判断网关是否被基础策略降级
+func (w wrapper) Demoted(gateway string) bool {
+	if d, ok := w.base.(interface{ Demoted(gateway string) bool }); ok {
+		return d.Demoted(gateway)
+	}
+	return false
+}
+
+// demotes 基础策略支持降级时返回 true
+func (w wrapper) demotes() bool {
+	return Demotes(w.base)
+}
+
 // OrderStrategy 是按顺序调用网关的策略
 type OrderStrategy struct{}
 
@@ -46,3 +142,57 @@ func (s *RandomStrategy) Apply(gateways []string) []string {
 
 	return result
 }
+
+// ConcurrentStrategy 是支持并发发送的策略接口
+// EasySms 会同时向排序后的前 Concurrency() 个网关发送短信
+type ConcurrentStrategy interface {
+	Strategy
+
+	// Concurrency 返回同时发送的网关数量
+	Concurrency() int
+}
+
+// RaceStrategy 是并发竞速发送的策略
+// 同时向前 N 个网关发送，采用第一个成功的结果并取消其余请求
+type RaceStrategy struct {
+	wrapper
+	concurrency int
+}
+
+// NewRaceStrategy 创建一个新的竞速策略
+// base 用于确定网关顺序，未指定时按原始顺序
+func NewRaceStrategy(concurrency int, base ...Strategy) *RaceStrategy {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var s Strategy = NewOrderStrategy()
+	if len(base) > 0 && base[0] != nil {
+		s = base[0]
+	}
+
+	return &RaceStrategy{
+		wrapper:     wrapper{base: s},
+		concurrency: concurrency,
+	}
+}
+
+// Apply 实现 Strategy 接口，使用基础策略排序网关列表
+func (s *RaceStrategy) Apply(gateways []string) []string {
+	return s.base.Apply(gateways)
+}
+
+// Route 实现 RoutingStrategy 接口，使用基础策略路由
+func (s *RaceStrategy) Route(gateways []string, to *message.PhoneNumber, msg *message.Message) []string {
+	return route(s.base, gateways, to, msg)
+}
+
+// routes 基础策略按收件人路由时返回 true
+func (s *RaceStrategy) routes() bool {
+	return Routes(s.base)
+}
+
+// Concurrency 实现 ConcurrentStrategy 接口
+func (s *RaceStrategy) Concurrency() int {
+	return s.concurrency
+}
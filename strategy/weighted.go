@@ -0,0 +1,135 @@
+package strategy
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// WeightedRoundRobinStrategy 是平滑加权轮询策略
+// 按照网关权重（如 aliyun 70 / qcloud 30）平滑地分配首选网关，其余网关按权重从高到低作为备选
+// 未配置权重或权重为 0 的网关只作为最后的备选
+type WeightedRoundRobinStrategy struct {
+	weights map[string]int
+	current map[string]int
+	mu      sync.Mutex
+}
+
+// NewWeightedRoundRobinStrategy 创建一个新的平滑加权轮询策略
+func NewWeightedRoundRobinStrategy(weights map[string]int) *WeightedRoundRobinStrategy {
+	return &WeightedRoundRobinStrategy{
+		weights: copyWeights(weights),
+		current: make(map[string]int),
+	}
+}
+
+// Apply 实现 Strategy 接口，首选网关由平滑加权轮询算法选出（线程安全）
+func (s *WeightedRoundRobinStrategy) Apply(gateways []string) []string {
+	weighted, unweighted := splitByWeight(gateways, s.weights)
+	if len(weighted) == 0 {
+		return unweighted
+	}
+
+	s.mu.Lock()
+	// 每个网关的当前权重加上其配置权重，选出当前权重最大的网关，再减去总权重
+	total := 0
+	best := -1
+	for i, name := range weighted {
+		weight := s.weights[name]
+		total += weight
+		s.current[name] += weight
+		if best < 0 || s.current[name] > s.current[weighted[best]] {
+			best = i
+		}
+	}
+	s.current[weighted[best]] -= total
+	s.mu.Unlock()
+
+	result := make([]string, 0, len(gateways))
+	result = append(result, weighted[best])
+
+	// 其余网关按权重从高到低排列，权重相同时保持原始顺序
+	rest := make([]string, 0, len(weighted)-1)
+	rest = append(rest, weighted[:best]...)
+	rest = append(rest, weighted[best+1:]...)
+	sortByWeight(rest, s.weights)
+
+	result = append(result, rest...)
+	return append(result, unweighted...)
+}
+
+// WeightedRandomStrategy 是加权随机策略
+// 按照网关权重进行不放回的随机抽样来决定网关顺序
+// 未配置权重或权重为 0 的网关只作为最后的备选
+type WeightedRandomStrategy struct {
+	weights map[string]int
+}
+
+// NewWeightedRandomStrategy 创建一个新的加权随机策略
+func NewWeightedRandomStrategy(weights map[string]int) *WeightedRandomStrategy {
+	return &WeightedRandomStrategy{
+		weights: copyWeights(weights),
+	}
+}
+
+// Apply 实现 Strategy 接口，按权重随机排序网关列表（线程安全）
+func (s *WeightedRandomStrategy) Apply(gateways []string) []string {
+	weighted, unweighted := splitByWeight(gateways, s.weights)
+
+	total := 0
+	for _, name := range weighted {
+		total += s.weights[name]
+	}
+
+	result := make([]string, 0, len(gateways))
+	for len(weighted) > 0 {
+		// 在剩余网关中按权重抽取一个
+		r := rand.Intn(total)
+		i := 0
+		for ; i < len(weighted)-1; i++ {
+			r -= s.weights[weighted[i]]
+			if r < 0 {
+				break
+			}
+		}
+
+		result = append(result, weighted[i])
+		total -= s.weights[weighted[i]]
+		weighted = append(weighted[:i], weighted[i+1:]...)
+	}
+
+	return append(result, unweighted...)
+}
+
+// copyWeights 复制权重配置，忽略非正数权重
+func copyWeights(weights map[string]int) map[string]int {
+	result := make(map[string]int, len(weights))
+	for name, weight := range weights {
+		if weight > 0 {
+			result[name] = weight
+		}
+	}
+	return result
+}
+
+// splitByWeight 将网关列表拆分为有权重和无权重两部分，均保持原始顺序
+func splitByWeight(gateways []string, weights map[string]int) ([]string, []string) {
+	var weighted, unweighted []string
+	for _, name := range gateways {
+		if weights[name] > 0 {
+			weighted = append(weighted, name)
+		} else {
+			unweighted = append(unweighted, name)
+		}
+	}
+	return weighted, unweighted
+}
+
+// sortByWeight 按权重从高到低稳定排序
+func sortByWeight(gateways []string, weights map[string]int) {
+	// 网关数量很少，使用插入排序即可保证稳定性
+	for i := 1; i < len(gateways); i++ {
+		for j := i; j > 0 && weights[gateways[j]] > weights[gateways[j-1]]; j-- {
+			gateways[j], gateways[j-1] = gateways[j-1], gateways[j]
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package easysms
+
+import (
+	"github.com/anhao/go-easy-sms/gateway"
+	"github.com/anhao/go-easy-sms/message"
+)
+
+// resolveMessage 为网关解析消息
+// 先调用消息的解析函数（message.MessageResolver），再解析逻辑模板，需要解析时返回消息副本；
+// 逻辑模板未配置该网关实例时使用驱动名称的配置，都未配置时返回模板错误，不发起请求直接回退到下一个网关
+func (st *state) resolveMessage(gatewayName string, msg *message.Message) (*message.Message, error) {
+	if msg.HasResolver() {
+		msg = msg.Resolve(gatewayName)
+	}
+
+	cfg := st.config
+	tpl, ok := cfg.Template(msg.GetTemplate())
+	if !ok {
+		return msg, nil
+	}
+
+	name := gatewayName
+	if _, ok := tpl.Gateways[name]; !ok {
+		if driver := gatewayDriver(gatewayName, cfg.GatewayConfigs[gatewayName]); tpl.Gateways[driver] != nil {
+			name = driver
+		}
+	}
+
+	resolved, err := tpl.Resolve(name, msg)
+	if err != nil {
+		return nil, gateway.NewSendError(gatewayName, gateway.CategoryTemplate, "", err.Error())
+	}
+	return resolved, nil
+}
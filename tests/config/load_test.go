@@ -0,0 +1,184 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anhao/go-easy-sms/config"
+	"github.com/anhao/go-easy-sms/strategy"
+)
+
+// writeFile 在临时目录中写入配置文件
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFileYAML(t *testing.T) {
+	t.Setenv("TEST_ALIYUN_SECRET", "mock-secret")
+
+	path := writeFile(t, "sms.yaml", `
+timeout: 3
+strategy: random
+default_gateways: [aliyun, qcloud]
+batch_concurrency: 4
+retry:
+  max_attempts: 3
+gateways:
+  aliyun:
+    access_key_id: mock-key
+    access_key_secret: ${TEST_ALIYUN_SECRET}
+    sign_name: ${TEST_SIGN_NAME:-默认签名}
+    timeout: 500ms
+  qcloud:
+    sdk_app_id: 1400000000
+templates:
+  login_otp:
+    gateways:
+      aliyun:
+        id: SMS_152550005
+        params:
+          code: otp
+`)
+
+	cfg, err := config.LoadFile(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if cfg.Timeout != 3 || cfg.BatchConcurrency != 4 {
+		t.Errorf("Unexpected timeout or batch concurrency: %v %d", cfg.Timeout, cfg.BatchConcurrency)
+	}
+	if _, ok := cfg.Strategy.(*strategy.RandomStrategy); !ok {
+		t.Errorf("Expected random strategy, got: %T", cfg.Strategy)
+	}
+	if len(cfg.DefaultGateways) != 2 || cfg.DefaultGateways[1] != "qcloud" {
+		t.Errorf("Unexpected default gateways: %v", cfg.DefaultGateways)
+	}
+	if cfg.Retry == nil || cfg.Retry.MaxAttempts != 3 {
+		t.Errorf("Unexpected retry policy: %+v", cfg.Retry)
+	}
+
+	aliyun := cfg.GatewayConfigs["aliyun"]
+	if aliyun["access_key_secret"] != "mock-secret" || aliyun["sign_name"] != "默认签名" {
+		t.Errorf("Expected environment variables to be interpolated, got: %v", aliyun)
+	}
+	if aliyun["timeout"] != 0.5 {
+		t.Errorf("Expected gateway timeout to be 0.5 seconds, got: %v", aliyun["timeout"])
+	}
+
+	tpl, ok := cfg.Template("login_otp")
+	if !ok || tpl.Gateways["aliyun"].ID != "SMS_152550005" || tpl.Gateways["aliyun"].Params["code"] != "otp" {
+		t.Errorf("Unexpected template: %+v", tpl)
+	}
+}
+
+func TestLoadFileJSON(t *testing.T) {
+	path := writeFile(t, "sms.json", `{
+		"strategy": "order",
+		"default_gateways": ["yunpian"],
+		"gateways": {"yunpian": {"api_key": "mock-api-key", "timeout": "2s"}}
+	}`)
+
+	cfg, err := config.LoadFile(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, ok := cfg.Strategy.(*strategy.OrderStrategy); !ok {
+		t.Errorf("Expected order strategy, got: %T", cfg.Strategy)
+	}
+	if cfg.GatewayConfigs["yunpian"]["api_key"] != "mock-api-key" || cfg.GatewayConfigs["yunpian"]["timeout"] != 2.0 {
+		t.Errorf("Unexpected gateway config: %v", cfg.GatewayConfigs["yunpian"])
+	}
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		message string
+	}{
+		{"sms.toml", `timeout = 5`, "unsupported file format"},
+		{"sms.yaml", `strategy: fastest`, "unknown strategy"},
+		{"sms.yaml", "gateways:\n  aliyun:\n    access_key_secret: ${TEST_MISSING_SECRET}", "TEST_MISSING_SECRET is not set"},
+		{"sms.yaml", "gateways:\n  aliyun:\n    timeout: soon", "gateways.aliyun.timeout"},
+		{"sms.json", `{"default_gateways": "aliyun"}`, "default_gateways"},
+	}
+
+	for _, tt := range tests {
+		_, err := config.LoadFile(writeFile(t, tt.name, tt.content))
+		if err == nil || !strings.Contains(err.Error(), tt.message) {
+			t.Errorf("Expected error containing %q, got: %v", tt.message, err)
+		}
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("SMS_TIMEOUT", "2.5")
+	t.Setenv("SMS_STRATEGY", "random")
+	t.Setenv("SMS_DEFAULT_GATEWAYS", "aliyun_intl, twilio")
+	t.Setenv("SMS_GATEWAYS_ALIYUN_INTL__ACCESS_KEY_ID", "mock-key")
+	t.Setenv("SMS_GATEWAYS_TWILIO__TIMEOUT", "1s")
+	t.Setenv("OTHER_TIMEOUT", "10")
+
+	cfg, err := config.FromEnv("SMS")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if cfg.Timeout != 2.5 {
+		t.Errorf("Expected timeout 2.5, got: %v", cfg.Timeout)
+	}
+	if _, ok := cfg.Strategy.(*strategy.RandomStrategy); !ok {
+		t.Errorf("Expected random strategy, got: %T", cfg.Strategy)
+	}
+	if len(cfg.DefaultGateways) != 2 || cfg.DefaultGateways[0] != "aliyun_intl" || cfg.DefaultGateways[1] != "twilio" {
+		t.Errorf("Unexpected default gateways: %v", cfg.DefaultGateways)
+	}
+	if cfg.GatewayConfigs["aliyun_intl"]["access_key_id"] != "mock-key" || cfg.GatewayConfigs["twilio"]["timeout"] != 1.0 {
+		t.Errorf("Unexpected gateway configs: %v", cfg.GatewayConfigs)
+	}
+
+	// 环境变量覆盖配置文件
+	fileCfg, err := config.LoadFile(writeFile(t, "sms.yaml", "gateways:\n  twilio:\n    account_sid: mock-sid\n    timeout: 5"))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := fileCfg.ApplyEnv("SMS_"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if fileCfg.GatewayConfigs["twilio"]["account_sid"] != "mock-sid" || fileCfg.GatewayConfigs["twilio"]["timeout"] != 1.0 {
+		t.Errorf("Expected env to override file config, got: %v", fileCfg.GatewayConfigs["twilio"])
+	}
+}
+
+func TestApplyEnvKeepsStrategy(t *testing.T) {
+	t.Setenv("SMS_STRATEGY", "random")
+
+	// 策略名称不变时保留代码中设置的策略
+	cfg := config.NewConfig()
+	breaker := strategy.NewCircuitBreakerStrategy(strategy.CircuitBreakerOptions{}, strategy.NewRandomStrategy())
+	cfg.Strategy = breaker
+	cfg.StrategyName = "random"
+	if err := cfg.ApplyEnv("SMS"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.Strategy != breaker {
+		t.Errorf("Expected the existing strategy to be kept, got: %T", cfg.Strategy)
+	}
+
+	// 策略名称变化时创建新的策略
+	cfg.StrategyName = "order"
+	if err := cfg.ApplyEnv("SMS"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, ok := cfg.Strategy.(*strategy.RandomStrategy); !ok || cfg.StrategyName != "random" {
+		t.Errorf("Expected random strategy, got: %T (%s)", cfg.Strategy, cfg.StrategyName)
+	}
+}
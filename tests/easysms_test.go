@@ -1,14 +1,25 @@
 package tests
 
 import (
+	"context"
 	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	easysms "github.com/anhao/go-easy-sms"
 	"github.com/anhao/go-easy-sms/config"
 	"github.com/anhao/go-easy-sms/gateway"
 	"github.com/anhao/go-easy-sms/message"
+	"github.com/anhao/go-easy-sms/receipt"
+	"github.com/anhao/go-easy-sms/retry"
+	"github.com/anhao/go-easy-sms/strategy"
 )
 
 // 创建一个模拟网关用于测试（优化后版本）
@@ -193,6 +204,174 @@ func TestGatewayError(t *testing.T) {
 	}
 }
 
+// 测试全局网关驱动
+func TestRegisterDriver(t *testing.T) {
+	easysms.Register("driver_test", func(config map[string]any) (gateway.Gateway, error) {
+		return NewMockGateway(config, false), nil
+	})
+
+	drivers := easysms.Drivers()
+	found := map[string]bool{}
+	for _, name := range drivers {
+		found[name] = true
+	}
+	if !found["driver_test"] || !found["aliyun"] {
+		t.Errorf("期望包含全局驱动和内置驱动，得到%v", drivers)
+	}
+
+	cfg := config.NewConfig()
+	cfg.DefaultGateways = []string{"driver_test"}
+	cfg.GatewayConfigs = map[string]map[string]any{
+		"driver_test": {"key": "value"},
+	}
+
+	sms, err := easysms.NewWithError(cfg)
+	if err != nil {
+		t.Fatalf("期望全局驱动自动可用，得到%v", err)
+	}
+	results, err := sms.Send(message.NewPhoneNumber("13800138000"), message.NewMessage().SetContent("驱动测试消息"))
+	if err != nil || results["driver_test"].Status != easysms.StatusSuccess {
+		t.Errorf("期望通过全局驱动发送成功，得到%v, %v", results, err)
+	}
+
+	// 实例上注册的创建函数优先
+	otherCfg := config.NewConfig()
+	other := easysms.New(otherCfg)
+	otherCfg.GatewayConfigs["driver_test"] = map[string]any{"key": "value"}
+	other.RegisterGatewayCreator("driver_test", func(config map[string]any) (gateway.Gateway, error) {
+		return nil, errors.New("instance creator")
+	})
+	if _, err := other.Gateway("driver_test"); err == nil || err.Error() != "instance creator" {
+		t.Errorf("期望使用实例上的创建函数，得到%v", err)
+	}
+
+	assertPanics := func(name string, fn func()) {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("期望%s时panic", name)
+			}
+		}()
+		fn()
+	}
+	assertPanics("重复注册", func() {
+		easysms.Register("driver_test", func(config map[string]any) (gateway.Gateway, error) { return nil, nil })
+	})
+	assertPanics("注册nil", func() { easysms.Register("driver_nil", nil) })
+}
+
+// 测试同一驱动的多个网关实例
+func TestReceiptParserInstanceName(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.GatewayConfigs = map[string]map[string]any{
+		"aliyun_backup": {"driver": "aliyun"},
+	}
+	sms := easysms.New(cfg)
+
+	parser, err := sms.ReceiptParser("aliyun_backup")
+	if err != nil {
+		t.Fatalf("获取解析器失败: %v", err)
+	}
+
+	var reports []*receipt.DeliveryReport
+	body := `[{"phone_number":"13800138000","success":true,"biz_id":"932702304080415357^0"}]`
+	req := httptest.NewRequest(http.MethodPost, "/receipt", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	receipt.Handler(parser, func(r []*receipt.DeliveryReport) { reports = r }).ServeHTTP(w, req)
+
+	if len(reports) != 1 || reports[0].Gateway != "aliyun_backup" {
+		t.Fatalf("期望状态报告使用网关实例名称aliyun_backup，得到%+v", reports)
+	}
+
+	// 保留服务商要求的应答
+	if !strings.Contains(w.Body.String(), `"code":0`) {
+		t.Errorf("期望按阿里云要求应答，得到%s", w.Body.String())
+	}
+}
+
+func TestGatewayDriverInstances(t *testing.T) {
+	created := make(map[string]*recordingGateway)
+	easysms.Register("instance_test", func(config map[string]any) (gateway.Gateway, error) {
+		gw := &recordingGateway{fail: config["account"] == "otp"}
+		created[config["account"].(string)] = gw
+		return gw, nil
+	})
+
+	cfg := config.NewConfig()
+	cfg.DefaultGateways = []string{"sms_otp", "sms_marketing"}
+	cfg.GatewayConfigs = map[string]map[string]any{
+		"sms_otp":       {"driver": "instance_test", "account": "otp"},
+		"sms_marketing": {"driver": "instance_test", "account": "marketing"},
+	}
+	cfg.Templates = map[string]*config.Template{
+		"promo": {Gateways: map[string]*config.GatewayTemplate{
+			"instance_test": {ID: "T_DRIVER"},
+			"sms_otp":       {ID: "T_OTP"},
+		}},
+	}
+
+	sms, err := easysms.NewWithError(cfg)
+	if err != nil {
+		t.Fatalf("创建失败: %v", err)
+	}
+	if len(created) != 2 {
+		t.Fatalf("期望创建2个网关实例，得到%d个", len(created))
+	}
+
+	results, err := sms.Send(message.NewPhoneNumber("13800138000"), message.NewMessage().SetTemplate("promo"))
+	if err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+	if results["sms_otp"].Gateway != "sms_otp" || results["sms_otp"].Status != easysms.StatusFailure {
+		t.Errorf("期望sms_otp发送失败，得到%+v", results["sms_otp"])
+	}
+	var sendErr *gateway.SendError
+	if !errors.As(results["sms_otp"].Error, &sendErr) || sendErr.Gateway != "sms_otp" || sendErr.Error() != "sms_otp gateway error: server error" {
+		t.Errorf("期望错误使用网关实例名称sms_otp，得到%v", results["sms_otp"].Error)
+	}
+	if results["sms_marketing"].Gateway != "sms_marketing" || results["sms_marketing"].Status != easysms.StatusSuccess {
+		t.Errorf("期望sms_marketing发送成功，得到%+v", results["sms_marketing"])
+	}
+
+	// 实例未配置逻辑模板时使用驱动名称的配置
+	if got := created["otp"].msg.GetTemplate(); got != "T_OTP" {
+		t.Errorf("期望sms_otp使用T_OTP，得到%s", got)
+	}
+	if got := created["marketing"].msg.GetTemplate(); got != "T_DRIVER" {
+		t.Errorf("期望sms_marketing使用T_DRIVER，得到%s", got)
+	}
+
+	// 按驱动校验内置网关配置，未知驱动返回错误
+	cfg = config.NewConfig()
+	cfg.DefaultGateways = []string{"aliyun_otp", "unknown"}
+	cfg.GatewayConfigs = map[string]map[string]any{
+		"aliyun_otp": {"driver": "aliyun", "acess_key_id": "mock"},
+		"unknown":    {"driver": "nonexistent"},
+	}
+	_, err = easysms.NewWithError(cfg)
+	var initErr *easysms.InitError
+	if !errors.As(err, &initErr) || len(initErr.Errors) != 2 {
+		t.Fatalf("期望2个错误，得到%v", err)
+	}
+	for _, e := range initErr.Errors {
+		var gwErr *easysms.GatewayError
+		if !errors.As(e, &gwErr) {
+			t.Fatalf("期望GatewayError，得到%v", e)
+		}
+		switch gwErr.GatewayName {
+		case "aliyun_otp":
+			if !errors.Is(e, gateway.ErrInvalidConfig) {
+				t.Errorf("期望aliyun_otp配置错误，得到%v", e)
+			}
+		case "unknown":
+			if gwErr.Err.Error() != "driver nonexistent not found" {
+				t.Errorf("期望驱动不存在，得到%v", e)
+			}
+		default:
+			t.Errorf("意外的错误: %v", e)
+		}
+	}
+}
+
 // 测试网关创建错误
 func TestGatewayCreationError(t *testing.T) {
 	cfg := config.NewConfig()
@@ -218,6 +397,87 @@ func TestGatewayCreationError(t *testing.T) {
 	}
 }
 
+// 测试内置网关配置校验
+func TestGatewayConfigValidation(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.GatewayConfigs = map[string]map[string]any{
+		"smsbao": {"user": "mock-user", "pasword": "mock-password"},
+		"juhe":   {"app_key": "mock-app-key"},
+	}
+
+	sms := easysms.New(cfg)
+
+	_, err := sms.Gateway("smsbao")
+	var gwErr *easysms.GatewayError
+	if !errors.As(err, &gwErr) || gwErr.Operation != "config" {
+		t.Fatalf("期望配置错误，得到%v", err)
+	}
+	if !errors.Is(err, gateway.ErrInvalidConfig) {
+		t.Errorf("期望错误包装ErrInvalidConfig，得到%v", err)
+	}
+
+	if _, err := sms.Gateway("juhe"); err != nil {
+		t.Errorf("期望有效配置的网关创建成功，得到%v", err)
+	}
+}
+
+// 测试创建时汇总网关配置错误
+func TestNewWithError(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.DefaultGateways = []string{"juhe", "missing", "custom"}
+	cfg.GatewayConfigs = map[string]map[string]any{
+		"juhe":   {"app_key": "mock-app-key"},
+		"smsbao": {"user": "mock-user"},
+		"custom": {"key": "value"},
+	}
+
+	sms, err := easysms.NewWithError(cfg)
+	if sms == nil {
+		t.Fatal("期望返回EasySms实例")
+	}
+
+	var initErr *easysms.InitError
+	if !errors.As(err, &initErr) {
+		t.Fatalf("期望InitError，得到%v", err)
+	}
+
+	// smsbao 缺少必填项，missing 没有配置，custom 没有创建函数
+	want := map[string]string{"smsbao": "config", "missing": "lookup", "custom": "lookup"}
+	if len(initErr.Errors) != len(want) {
+		t.Fatalf("期望%d个错误，得到%v", len(want), initErr.Errors)
+	}
+	for _, e := range initErr.Errors {
+		var gwErr *easysms.GatewayError
+		if !errors.As(e, &gwErr) || want[gwErr.GatewayName] != gwErr.Operation {
+			t.Errorf("意外的错误: %v", e)
+		}
+	}
+	if !errors.Is(err, gateway.ErrInvalidConfig) {
+		t.Errorf("期望错误包装ErrInvalidConfig，得到%v", err)
+	}
+
+	// 不依赖 Go 1.20 的多错误 Unwrap
+	var gwErr *easysms.GatewayError
+	if !initErr.Is(gateway.ErrInvalidConfig) || !initErr.As(&gwErr) || gwErr.GatewayName != "smsbao" {
+		t.Errorf("期望Is和As检查每个网关错误，得到%v", gwErr)
+	}
+	if initErr.Is(context.Canceled) {
+		t.Error("期望不匹配的错误返回false")
+	}
+
+	if _, err := sms.Gateway("juhe"); err != nil {
+		t.Errorf("期望有效配置的网关可用，得到%v", err)
+	}
+
+	// 配置全部有效时不返回错误
+	cfg = config.NewConfig()
+	cfg.DefaultGateways = []string{"juhe"}
+	cfg.GatewayConfigs = map[string]map[string]any{"juhe": {"app_key": "mock-app-key"}}
+	if _, err := easysms.NewWithError(cfg); err != nil {
+		t.Errorf("期望没有错误，得到%v", err)
+	}
+}
+
 // 测试并发安全性
 func TestConcurrentSafety(t *testing.T) {
 	cfg := config.NewConfig()
@@ -342,3 +602,1392 @@ func TestMultipleGateways(t *testing.T) {
 		t.Errorf("期望second网关状态为%s，得到%s", easysms.StatusSuccess, secondResult.Status)
 	}
 }
+
+// 测试上下文取消后不再尝试后续网关
+func TestSendContextCancelled(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.DefaultGateways = []string{"first", "second"}
+	cfg.GatewayConfigs = map[string]map[string]any{
+		"first":  {"timeout": 5.0},
+		"second": {"timeout": 5.0},
+	}
+
+	sms := easysms.New(cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// 第一个网关失败的同时取消上下文
+	sms.RegisterGateway("first", &cancellingGateway{cancel: cancel})
+	sms.RegisterGateway("second", NewMockGateway(cfg.GatewayConfigs["second"], false))
+
+	phone := message.NewPhoneNumber("13800138000")
+	msg := message.NewMessage().SetContent("上下文测试消息")
+
+	results, err := sms.SendContext(ctx, phone, msg)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("期望返回context.Canceled，得到%v", err)
+	}
+
+	if _, ok := results["second"]; ok {
+		t.Error("上下文取消后不应再尝试second网关")
+	}
+}
+
+// cancellingGateway 发送时取消上下文并返回失败
+type cancellingGateway struct {
+	cancel context.CancelFunc
+}
+
+func (g *cancellingGateway) GetName() string {
+	return "cancelling"
+}
+
+func (g *cancellingGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+func (g *cancellingGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
+	g.cancel()
+	return nil, errors.New("cancelling gateway error")
+}
+
+// 测试竞速发送：第一个成功后取消其余网关
+func TestRaceSend(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.DefaultGateways = []string{"slow", "fast", "backup"}
+	cfg.GatewayConfigs = map[string]map[string]any{
+		"slow":   {"timeout": 5.0},
+		"fast":   {"timeout": 5.0},
+		"backup": {"timeout": 5.0},
+	}
+	cfg.Strategy = strategy.NewRaceStrategy(2)
+
+	sms := easysms.New(cfg)
+	sms.RegisterGateway("slow", &blockingGateway{})
+	sms.RegisterGateway("fast", NewMockGateway(cfg.GatewayConfigs["fast"], false))
+	sms.RegisterGateway("backup", NewMockGateway(cfg.GatewayConfigs["backup"], false))
+
+	phone := message.NewPhoneNumber("13800138000")
+	msg := message.NewMessage().SetContent("竞速测试消息")
+
+	done := make(chan struct{})
+	var results map[string]easysms.Result
+	var err error
+	go func() {
+		results, err = sms.Send(phone, msg)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("竞速发送未在第一个成功后返回")
+	}
+
+	if err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+
+	if results["fast"].Status != easysms.StatusSuccess {
+		t.Errorf("期望fast网关状态为%s，得到%s", easysms.StatusSuccess, results["fast"].Status)
+	}
+
+	if results["slow"].Status != easysms.StatusCanceled {
+		t.Errorf("期望slow网关状态为%s，得到%s", easysms.StatusCanceled, results["slow"].Status)
+	}
+
+	if _, ok := results["backup"]; ok {
+		t.Error("竞速成功后不应再尝试backup网关")
+	}
+}
+
+// 测试竞速全部失败后依次尝试剩余网关
+func TestRaceSendFallback(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.DefaultGateways = []string{"first", "second", "third"}
+	cfg.GatewayConfigs = map[string]map[string]any{
+		"first":  {"timeout": 5.0},
+		"second": {"timeout": 5.0},
+		"third":  {"timeout": 5.0},
+	}
+	cfg.Strategy = strategy.NewRaceStrategy(2)
+
+	sms := easysms.New(cfg)
+	sms.RegisterGateway("first", NewMockGateway(cfg.GatewayConfigs["first"], true))
+	sms.RegisterGateway("second", NewMockGateway(cfg.GatewayConfigs["second"], true))
+	sms.RegisterGateway("third", NewMockGateway(cfg.GatewayConfigs["third"], false))
+
+	results, err := sms.Send(message.NewPhoneNumber("13800138000"), message.NewMessage().SetContent("竞速测试消息"))
+	if err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Errorf("期望3个结果，得到%d个", len(results))
+	}
+
+	if results["third"].Status != easysms.StatusSuccess {
+		t.Errorf("期望third网关状态为%s，得到%s", easysms.StatusSuccess, results["third"].Status)
+	}
+}
+
+// blockingGateway 阻塞直到上下文结束
+type blockingGateway struct{}
+
+func (g *blockingGateway) GetName() string {
+	return "blocking"
+}
+
+func (g *blockingGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return g.SendContext(context.Background(), to, msg)
+}
+
+func (g *blockingGateway) SendContext(ctx context.Context, to *message.PhoneNumber, msg *message.Message) (any, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// 测试熔断策略：网关连续失败后被移到末尾
+func TestCircuitBreakerFeedback(t *testing.T) {
+	breaker := strategy.NewCircuitBreakerStrategy(strategy.CircuitBreakerOptions{
+		FailureThreshold: 2,
+	})
+
+	cfg := config.NewConfig()
+	cfg.DefaultGateways = []string{"down", "up"}
+	cfg.GatewayConfigs = map[string]map[string]any{
+		"down": {"timeout": 5.0},
+		"up":   {"timeout": 5.0},
+	}
+	cfg.Strategy = breaker
+
+	sms := easysms.New(cfg)
+	sms.RegisterGateway("down", NewMockGateway(cfg.GatewayConfigs["down"], true))
+	sms.RegisterGateway("up", NewMockGateway(cfg.GatewayConfigs["up"], false))
+
+	phone := message.NewPhoneNumber("13800138000")
+	msg := message.NewMessage().SetContent("熔断测试消息")
+
+	for i := 0; i < 2; i++ {
+		results, err := sms.Send(phone, msg)
+		if err != nil {
+			t.Fatalf("发送失败: %v", err)
+		}
+		if len(results) != 2 {
+			t.Errorf("期望2个结果，得到%d个", len(results))
+		}
+	}
+
+	if breaker.State("down") != strategy.StateOpen {
+		t.Fatalf("期望down网关熔断，得到%s", breaker.State("down"))
+	}
+
+	// 熔断后直接使用up网关
+	results, err := sms.Send(phone, msg)
+	if err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+	if _, ok := results["down"]; ok {
+		t.Error("熔断后不应再尝试down网关")
+	}
+}
+
+// 测试按网关配置重试
+func TestSendRetry(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.DefaultGateways = []string{"flaky"}
+	cfg.GatewayConfigs = map[string]map[string]any{
+		"flaky": {
+			"retry": map[string]any{
+				"max_attempts": 3,
+				"base_backoff": 0.001,
+			},
+		},
+	}
+
+	sms := easysms.New(cfg)
+	flaky := &flakyGateway{failures: 2}
+	sms.RegisterGateway("flaky", flaky)
+
+	results, err := sms.Send(message.NewPhoneNumber("13800138000"), message.NewMessage().SetContent("重试测试消息"))
+	if err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+
+	if flaky.calls != 3 {
+		t.Errorf("期望调用3次，得到%d次", flaky.calls)
+	}
+
+	if results["flaky"].Status != easysms.StatusSuccess {
+		t.Errorf("期望状态为%s，得到%s", easysms.StatusSuccess, results["flaky"].Status)
+	}
+}
+
+// 测试业务错误不重试
+func TestSendNoRetryOnBusinessError(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.DefaultGateways = []string{"failing"}
+	cfg.GatewayConfigs = map[string]map[string]any{
+		"failing": {},
+	}
+	cfg.Retry = &retry.Policy{MaxAttempts: 3, BaseBackoff: time.Millisecond}
+
+	sms := easysms.New(cfg)
+	failing := &flakyGateway{failures: 10, err: errors.New("invalid phone number")}
+	sms.RegisterGateway("failing", failing)
+
+	if _, err := sms.Send(message.NewPhoneNumber("13800138000"), message.NewMessage().SetContent("重试测试消息")); err == nil {
+		t.Fatal("期望发送失败")
+	}
+
+	if failing.calls != 1 {
+		t.Errorf("期望调用1次，得到%d次", failing.calls)
+	}
+}
+
+// flakyGateway 前 failures 次发送失败
+type flakyGateway struct {
+	failures int
+	calls    int
+	err      error
+}
+
+func (g *flakyGateway) GetName() string {
+	return "flaky"
+}
+
+func (g *flakyGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	g.calls++
+	if g.calls <= g.failures {
+		if g.err != nil {
+			return nil, g.err
+		}
+		return nil, &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+	}
+	return map[string]any{"success": true}, nil
+}
+
+// 测试无效号码等错误不回退到后续网关
+func TestSendFallbackRules(t *testing.T) {
+	newSms := func(fallback *config.FallbackPolicy) (*easysms.EasySms, *flakyGateway, *flakyGateway) {
+		cfg := config.NewConfig()
+		cfg.DefaultGateways = []string{"first", "second"}
+		cfg.GatewayConfigs = map[string]map[string]any{
+			"first":  {},
+			"second": {},
+		}
+		cfg.Fallback = fallback
+
+		sms := easysms.New(cfg)
+		first := &flakyGateway{failures: 1, err: gateway.NewSendError("first", gateway.CategoryInvalidNumber, "isv.MOBILE_NUMBER_ILLEGAL", "非法手机号")}
+		second := &flakyGateway{}
+		sms.RegisterGateway("first", first)
+		sms.RegisterGateway("second", second)
+		return sms, first, second
+	}
+
+	phone := message.NewPhoneNumber("1380013800")
+	msg := message.NewMessage().SetContent("回退测试消息")
+
+	// 默认总是回退到下一个网关
+	sms, _, second := newSms(nil)
+	if _, err := sms.Send(phone, msg); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+
+	if second.calls != 1 {
+		t.Errorf("期望调用第二个网关1次，得到%d次", second.calls)
+	}
+
+	// StopOnUserErrors：无效号码立即停止
+	sms, _, second = newSms(config.StopOnUserErrors())
+	results, err := sms.Send(phone, msg)
+	if err == nil {
+		t.Fatal("期望发送失败")
+	}
+
+	var sendErr *gateway.SendError
+	if !errors.As(err, &sendErr) || sendErr.Category != gateway.CategoryInvalidNumber {
+		t.Errorf("期望返回无效号码错误，得到%v", err)
+	}
+
+	if second.calls != 0 {
+		t.Errorf("期望不再尝试第二个网关，得到%d次调用", second.calls)
+	}
+
+	if _, ok := results["second"]; ok {
+		t.Error("期望结果中不包含第二个网关")
+	}
+
+	// StopOnUserErrors：根据错误信息推断的分类仍然回退
+	sms, first, second := newSms(config.StopOnUserErrors())
+	first.err = &gateway.SendError{Gateway: "first", Category: gateway.CategoryInvalidNumber, Message: "手机号格式错误", Inferred: true}
+	if _, err := sms.Send(phone, msg); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+	if second.calls != 1 {
+		t.Errorf("期望推断的分类回退到第二个网关，得到%d次调用", second.calls)
+	}
+
+	// 每次返回新的规则，修改不会影响其他配置
+	policy := config.StopOnUserErrors()
+	policy.StopOn = nil
+	if len(config.StopOnUserErrors().StopOn) != 2 {
+		t.Error("期望 StopOnUserErrors 每次返回新的规则")
+	}
+
+	// 自定义判断
+	sms, _, second = newSms(&config.FallbackPolicy{
+		ShouldFallback: func(err *gateway.SendError) bool {
+			return err.Category != gateway.CategoryInvalidNumber
+		},
+	})
+	if _, err := sms.Send(phone, msg); err == nil {
+		t.Fatal("期望发送失败")
+	}
+
+	if second.calls != 0 {
+		t.Errorf("期望不再尝试第二个网关，得到%d次调用", second.calls)
+	}
+}
+
+// 测试批量发送
+func TestSendBatch(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.DefaultGateways = []string{"batch", "mock"}
+	cfg.GatewayConfigs = map[string]map[string]any{
+		"batch": {},
+		"mock":  {},
+	}
+	cfg.BatchConcurrency = 2
+	cfg.Fallback = config.StopOnUserErrors()
+
+	sms := easysms.New(cfg)
+	batch := &batchGateway{size: 2}
+	sms.RegisterGateway("batch", batch)
+	sms.RegisterGateway("mock", NewMockGateway(map[string]any{}, false))
+
+	to := []*message.PhoneNumber{
+		message.NewPhoneNumber("13800138000"),
+		message.NewPhoneNumber("13800138001"), // 余额不足，回退到 mock 网关
+		message.NewPhoneNumber("13800138002"), // 无效号码，停止回退
+		message.NewPhoneNumber("13800138003"),
+		message.NewPhoneNumber("13800138004"),
+	}
+
+	results, err := sms.SendBatch(context.Background(), to, message.NewMessage().SetContent("批量测试消息"))
+	if err == nil {
+		t.Fatal("期望部分号码发送失败")
+	}
+
+	if len(results) != len(to) {
+		t.Fatalf("期望%d个结果，得到%d个", len(to), len(results))
+	}
+
+	// 按批量上限分批调用
+	if len(batch.chunks) != 3 || batch.chunks[0] != 2 || batch.chunks[2] != 1 {
+		t.Errorf("期望分3批发送，得到%v", batch.chunks)
+	}
+
+	for i, result := range results {
+		if result.To != to[i] {
+			t.Errorf("期望结果顺序与号码一致")
+		}
+	}
+
+	if !results[0].Success() || results[0].Results["batch"].Status != easysms.StatusSuccess {
+		t.Errorf("期望第1个号码通过批量网关发送成功，得到%+v", results[0])
+	}
+
+	if !results[1].Success() || results[1].Results["mock"].Status != easysms.StatusSuccess {
+		t.Errorf("期望第2个号码回退到 mock 网关发送成功，得到%+v", results[1])
+	}
+
+	if results[2].Success() {
+		t.Error("期望第3个号码发送失败")
+	}
+
+	if _, ok := results[2].Results["mock"]; ok {
+		t.Error("期望无效号码不回退到 mock 网关")
+	}
+}
+
+func TestSendWithCarrierStrategy(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.DefaultGateways = []string{"unicom", "mobile"}
+	cfg.GatewayConfigs = map[string]map[string]any{
+		"unicom": {},
+		"mobile": {},
+	}
+	cfg.Strategy = strategy.NewCarrierStrategy(map[message.Carrier][]string{
+		message.CarrierChinaMobile: {"mobile"},
+	})
+
+	sms := easysms.New(cfg)
+	unicom := &batchGateway{size: 10}
+	mobile := &batchGateway{size: 10}
+	sms.RegisterGateway("unicom", unicom)
+	sms.RegisterGateway("mobile", mobile)
+
+	results, err := sms.Send(message.NewPhoneNumber("13800138000"), message.NewMessage().SetContent("测试消息"))
+	if err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+	if _, ok := results["mobile"]; !ok || len(results) != 1 {
+		t.Errorf("期望移动号码优先使用 mobile 网关，得到%v", results)
+	}
+
+	// 批量发送时按运营商分组
+	to := []*message.PhoneNumber{
+		message.NewPhoneNumber("13800138000"),
+		message.NewPhoneNumber("18612345678"),
+		message.NewPhoneNumber("13900139000"),
+	}
+	batchResults, err := sms.SendBatch(context.Background(), to, message.NewMessage().SetContent("批量测试消息"))
+	if err != nil {
+		t.Fatalf("批量发送失败: %v", err)
+	}
+
+	if _, ok := batchResults[1].Results["unicom"]; !ok {
+		t.Errorf("期望联通号码使用 unicom 网关，得到%v", batchResults[1].Results)
+	}
+	if len(mobile.chunks) != 2 || mobile.chunks[1] != 2 {
+		t.Errorf("期望两个移动号码在同一批中发送，得到%v", mobile.chunks)
+	}
+}
+
+func TestSendWithRuleStrategy(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.DefaultGateways = []string{"aliyun", "twilio"}
+	cfg.GatewayConfigs = map[string]map[string]any{
+		"aliyun": {},
+		"twilio": {},
+	}
+	cfg.Strategy = strategy.NewRuleStrategy([]strategy.Rule{
+		{IDDCodes: []int{86}, Gateways: []string{"aliyun"}},
+	}, []string{"twilio"})
+
+	sms := easysms.New(cfg)
+	aliyun := &recordingGateway{}
+	twilio := &recordingGateway{}
+	sms.RegisterGateway("aliyun", aliyun)
+	sms.RegisterGateway("twilio", twilio)
+
+	msg := message.NewMessage().SetContent("测试消息")
+	if _, err := sms.Send(message.NewPhoneNumber("13800138000"), msg); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+	if aliyun.msg == nil || twilio.msg != nil {
+		t.Error("期望国内号码只通过 aliyun 发送")
+	}
+
+	aliyun.msg = nil
+	results, err := sms.Send(message.NewPhoneNumber("7911123456", 44), msg)
+	if err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+	if aliyun.msg != nil || twilio.msg == nil || len(results) != 1 {
+		t.Errorf("期望国际号码只通过 twilio 发送，得到%v", results)
+	}
+}
+
+func TestSendSkipsUnsupportedGateways(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.DefaultGateways = []string{"template", "domestic", "intl"}
+	cfg.GatewayConfigs = map[string]map[string]any{
+		"template": {},
+		"domestic": {},
+		"intl":     {},
+	}
+
+	sms := easysms.New(cfg)
+	template := &capableGateway{caps: gateway.Capabilities{Template: true, Unicode: true}}
+	domestic := &capableGateway{caps: gateway.Capabilities{Content: true, Unicode: true}}
+	intl := &capableGateway{caps: gateway.Capabilities{International: true, Content: true, Unicode: true}}
+	sms.RegisterGateway("template", template)
+	sms.RegisterGateway("domestic", domestic)
+	sms.RegisterGateway("intl", intl)
+
+	msg := message.NewMessage().SetContent("Your code is 6379")
+	results, err := sms.Send(message.NewPhoneNumber("7911123456", 44), msg)
+	if err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+
+	// 无法发送的网关不发起请求
+	if template.msg != nil || domestic.msg != nil || intl.msg == nil {
+		t.Error("期望只通过 intl 网关发送")
+	}
+	for _, name := range []string{"template", "domestic"} {
+		if results[name].Status != easysms.StatusSkipped || !errors.Is(results[name].Error, gateway.ErrUnsupported) {
+			t.Errorf("期望 %s 网关被跳过，得到%+v", name, results[name])
+		}
+	}
+
+	// 所有网关都无法发送时返回错误
+	_, err = sms.Send(message.NewPhoneNumber("7911123456", 44), message.NewMessage().SetTemplate("SMS_001"))
+	if !errors.Is(err, gateway.ErrUnsupported) {
+		t.Errorf("期望返回 ErrUnsupported，得到%v", err)
+	}
+
+	// 批量发送时国际号码跳过国内网关
+	intl.msg = nil
+	batchResults, err := sms.SendBatch(context.Background(), []*message.PhoneNumber{
+		message.NewPhoneNumber("13800138000"),
+		message.NewPhoneNumber("7911123456", 44),
+	}, msg)
+	if err != nil {
+		t.Fatalf("批量发送失败: %v", err)
+	}
+	if batchResults[0].Results["domestic"].Status != easysms.StatusSuccess {
+		t.Errorf("期望国内号码通过 domestic 网关发送，得到%v", batchResults[0].Results)
+	}
+	if batchResults[1].Results["domestic"].Status != easysms.StatusSkipped || batchResults[1].Results["intl"].Status != easysms.StatusSuccess {
+		t.Errorf("期望国际号码跳过 domestic 网关，得到%v", batchResults[1].Results)
+	}
+}
+
+func TestSendResolvesMessageOncePerGateway(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.DefaultGateways = []string{"template", "domestic", "intl"}
+	cfg.GatewayConfigs = map[string]map[string]any{
+		"template": {},
+		"domestic": {},
+		"intl":     {},
+	}
+
+	sms := easysms.New(cfg)
+	sms.RegisterGateway("template", &capableGateway{caps: gateway.Capabilities{Template: true, Unicode: true}})
+	sms.RegisterGateway("domestic", &capableGateway{caps: gateway.Capabilities{Content: true, Unicode: true}})
+	sms.RegisterGateway("intl", &capableGateway{caps: gateway.Capabilities{International: true, Content: true, Unicode: true}})
+
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	msg := message.NewMessage().SetContentFunc(func(gateway string) string {
+		mu.Lock()
+		defer mu.Unlock()
+		calls[gateway]++
+		return "Your code is 6379"
+	})
+
+	// 每个尝试的网关只解析一次消息，能力检查使用同一个解析结果
+	if _, err := sms.Send(message.NewPhoneNumber("7911123456", 44), msg); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+	for _, name := range []string{"template", "domestic", "intl"} {
+		if calls[name] != 1 {
+			t.Errorf("期望 %s 网关解析一次消息，得到%d次", name, calls[name])
+		}
+	}
+
+	// 批量发送时每个网关只解析一次消息，不随号码数量增加
+	calls = make(map[string]int)
+	if _, err := sms.SendBatch(context.Background(), []*message.PhoneNumber{
+		message.NewPhoneNumber("13800138000"),
+		message.NewPhoneNumber("13800138001"),
+		message.NewPhoneNumber("13800138002"),
+	}, msg); err != nil {
+		t.Fatalf("批量发送失败: %v", err)
+	}
+	if calls["template"] != 1 || calls["domestic"] != 1 || calls["intl"] != 0 {
+		t.Errorf("期望每个网关解析一次消息，得到%v", calls)
+	}
+
+	// 竞速发送时被跳过的网关不占用并发数
+	cfg.Strategy = strategy.NewRaceStrategy(2)
+	raceSms := easysms.New(cfg)
+	intl := &capableGateway{caps: gateway.Capabilities{International: true, Content: true, Unicode: true}}
+	raceSms.RegisterGateway("template", &capableGateway{caps: gateway.Capabilities{Template: true, Unicode: true}})
+	raceSms.RegisterGateway("domestic", &capableGateway{caps: gateway.Capabilities{Content: true, Unicode: true}})
+	raceSms.RegisterGateway("intl", intl)
+
+	results, err := raceSms.Send(message.NewPhoneNumber("7911123456", 44), msg)
+	if err != nil || intl.msg == nil || results["intl"].Status != easysms.StatusSuccess {
+		t.Errorf("期望跳过国内网关后通过 intl 网关发送，得到%v, %v", results, err)
+	}
+}
+
+// capableGateway 声明能力的测试网关
+type capableGateway struct {
+	recordingGateway
+	caps gateway.Capabilities
+}
+
+func (g *capableGateway) Capabilities() gateway.Capabilities {
+	return g.caps
+}
+
+func TestGatewayTimeoutFromConfig(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Timeout = 2
+	cfg.GatewayConfigs = map[string]map[string]any{
+		"global": {},
+		"custom": {"timeout": 0.5},
+	}
+
+	sms := easysms.New(cfg)
+	timeouts := make(map[string]any)
+	for _, name := range []string{"global", "custom"} {
+		name := name
+		sms.RegisterGatewayCreator(name, func(c map[string]any) (gateway.Gateway, error) {
+			timeouts[name] = c["timeout"]
+			return NewMockGateway(c, false), nil
+		})
+		if _, err := sms.Gateway(name); err != nil {
+			t.Fatalf("创建网关失败: %v", err)
+		}
+	}
+
+	if timeouts["global"] != 2.0 || timeouts["custom"] != 0.5 {
+		t.Errorf("期望未配置超时的网关使用全局超时，得到%v", timeouts)
+	}
+	if _, ok := cfg.GatewayConfigs["global"]["timeout"]; ok {
+		t.Error("期望不修改原始网关配置")
+	}
+}
+
+// batchGateway 支持批量接口的测试网关，号码以 1 结尾时余额不足，以 2 结尾时号码无效
+type batchGateway struct {
+	size   int
+	chunks []int
+}
+
+func (g *batchGateway) GetName() string {
+	return "batch"
+}
+
+func (g *batchGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	results, err := g.SendBatch(context.Background(), []*message.PhoneNumber{to}, msg)
+	if err != nil {
+		return nil, err
+	}
+	return results[0].Data, results[0].Err
+}
+
+func (g *batchGateway) BatchSize() int {
+	return g.size
+}
+
+func (g *batchGateway) SendBatch(ctx context.Context, to []*message.PhoneNumber, msg *message.Message) ([]gateway.BatchResult, error) {
+	g.chunks = append(g.chunks, len(to))
+
+	results := make([]gateway.BatchResult, len(to))
+	for i, phone := range to {
+		switch phone.GetNumber()[len(phone.GetNumber())-1] {
+		case '1':
+			results[i].Err = gateway.NewSendError("batch", gateway.CategoryInsufficientBalance, "3", "余额不足")
+		case '2':
+			results[i].Err = gateway.NewSendError("batch", gateway.CategoryInvalidNumber, "20", "号码无效")
+		default:
+			results[i].Data = map[string]any{"success": true}
+		}
+	}
+	return results, nil
+}
+
+func TestStatusPoller(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.DefaultGateways = []string{"querier", "mock"}
+	cfg.GatewayConfigs = map[string]map[string]any{
+		"querier": {},
+		"mock":    {},
+	}
+
+	sms := easysms.New(cfg)
+	querier := &querierGateway{polls: map[string]int{}}
+	sms.RegisterGateway("querier", querier)
+	sms.RegisterGateway("mock", NewMockGateway(map[string]any{}, false))
+
+	var reports []*receipt.DeliveryReport
+	poller := sms.NewStatusPoller(func(report *receipt.DeliveryReport) {
+		reports = append(reports, report)
+	})
+
+	phone := message.NewPhoneNumber("13800138000")
+	results, err := sms.Send(phone, message.NewMessage().SetContent("测试消息"))
+	if err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+
+	// mock 网关不支持状态查询，不会被跟踪
+	results["mock"] = easysms.Result{Gateway: "mock", Status: easysms.StatusSuccess}
+	if n := poller.Track(phone, results); n != 1 {
+		t.Fatalf("期望跟踪1条消息，得到%d条", n)
+	}
+
+	// 第一次查询仍在等待回执
+	poller.Poll(context.Background())
+	if len(reports) != 0 || poller.Pending() != 1 {
+		t.Fatalf("期望消息仍在跟踪中，得到%d个报告", len(reports))
+	}
+
+	poller.Poll(context.Background())
+	if len(reports) != 1 || reports[0].MessageID != "msg-1" || reports[0].Status != receipt.StatusDelivered {
+		t.Fatalf("期望收到送达报告，得到%+v", reports)
+	}
+	if poller.Pending() != 0 {
+		t.Errorf("期望收到最终状态后不再跟踪，得到%d条", poller.Pending())
+	}
+
+	// 报告使用跟踪的网关实例名称
+	sms.RegisterGateway("querier_backup", querier)
+	poller.Add("querier_backup", "msg-2", phone)
+	poller.Poll(context.Background())
+	poller.Poll(context.Background())
+	if len(reports) != 2 || reports[1].Gateway != "querier_backup" {
+		t.Errorf("期望报告的网关为querier_backup，得到%+v", reports)
+	}
+}
+
+func TestStatusPollerMaxAge(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.GatewayConfigs = map[string]map[string]any{"querier": {}}
+
+	sms := easysms.New(cfg)
+	querier := &querierGateway{polls: map[string]int{}}
+	sms.RegisterGateway("querier", querier)
+
+	poller := sms.NewStatusPoller(nil)
+	poller.MaxAge = time.Nanosecond
+	poller.Add("querier", "msg-1", message.NewPhoneNumber("13800138000"))
+
+	time.Sleep(time.Millisecond)
+	poller.Poll(context.Background())
+
+	if poller.Pending() != 0 || querier.polls["msg-1"] != 0 {
+		t.Errorf("期望超过跟踪时长的消息不再查询")
+	}
+}
+
+// querierGateway 支持状态查询的测试网关，第二次查询时返回送达
+type querierGateway struct {
+	polls map[string]int
+}
+
+func (g *querierGateway) GetName() string {
+	return "querier"
+}
+
+func (g *querierGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return map[string]any{"id": "msg-1"}, nil
+}
+
+func (g *querierGateway) MessageID(result any) string {
+	id, _ := result.(map[string]any)["id"].(string)
+	return id
+}
+
+func (g *querierGateway) QueryStatus(ctx context.Context, messageID string, to *message.PhoneNumber) (*receipt.DeliveryReport, error) {
+	g.polls[messageID]++
+
+	status := receipt.StatusPending
+	if g.polls[messageID] > 1 {
+		status = receipt.StatusDelivered
+	}
+	return &receipt.DeliveryReport{Gateway: "querier", MessageID: messageID, Phone: to.GetNumber(), Status: status}, nil
+}
+
+func TestBalances(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.GatewayConfigs = map[string]map[string]any{
+		"prepaid": {},
+		"broken":  {},
+		"mock":    {},
+	}
+
+	sms := easysms.New(cfg)
+	sms.RegisterGateway("prepaid", &balanceGateway{amount: 42})
+	sms.RegisterGateway("broken", &balanceGateway{err: gateway.NewSendError("broken", gateway.CategoryAuth, "30", "密码错误")})
+	sms.RegisterGateway("mock", NewMockGateway(map[string]any{}, false))
+
+	results, err := sms.Balances(context.Background())
+	if err == nil {
+		t.Fatal("期望部分网关查询失败")
+	}
+
+	// mock 网关不支持余额查询
+	if len(results) != 2 {
+		t.Fatalf("期望2个结果，得到%d个", len(results))
+	}
+
+	if b := results["prepaid"].Balance; b == nil || b.Amount != 42 || b.Gateway != "prepaid" {
+		t.Errorf("期望 prepaid 余额为42，得到%+v", results["prepaid"])
+	}
+
+	if gateway.ClassifyError(results["broken"].Error) != gateway.CategoryAuth {
+		t.Errorf("期望 broken 返回认证错误，得到%v", results["broken"].Error)
+	}
+}
+
+// balanceGateway 支持余额查询的测试网关
+type balanceGateway struct {
+	amount float64
+	err    error
+}
+
+func (g *balanceGateway) GetName() string {
+	return "balance"
+}
+
+func (g *balanceGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	return nil, nil
+}
+
+func (g *balanceGateway) QueryBalance(ctx context.Context) (*gateway.Balance, error) {
+	if g.err != nil {
+		return nil, g.err
+	}
+	return &gateway.Balance{Gateway: g.GetName(), Amount: g.amount, Unit: gateway.BalanceUnitMessage}, nil
+}
+
+func TestBalanceMonitor(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.DefaultGateways = []string{"prepaid", "mock"}
+	cfg.Strategy = strategy.NewDemoteStrategy()
+	cfg.GatewayConfigs = map[string]map[string]any{
+		"prepaid":   {"balance_threshold": 100, "balance_critical": 10.0},
+		"unwatched": {},
+		"mock":      {},
+	}
+
+	sms := easysms.New(cfg)
+	prepaid := &balanceGateway{amount: 500}
+	unwatched := &balanceGateway{err: errors.New("should not be queried")}
+	sms.RegisterGateway("prepaid", prepaid)
+	sms.RegisterGateway("unwatched", unwatched)
+	sms.RegisterGateway("mock", NewMockGateway(map[string]any{}, false))
+
+	var alerts []easysms.BalanceAlert
+	monitor := sms.NewBalanceMonitor(func(alert easysms.BalanceAlert) {
+		alerts = append(alerts, alert)
+	})
+
+	monitor.Check(context.Background())
+	if len(alerts) != 0 {
+		t.Fatalf("期望余额充足时不告警，得到%+v", alerts)
+	}
+
+	prepaid.amount = 50
+	monitor.Check(context.Background())
+	monitor.Check(context.Background())
+	if len(alerts) != 1 || alerts[0].Level != easysms.BalanceLow || alerts[0].Threshold != 100 {
+		t.Fatalf("期望低余额告警一次，得到%+v", alerts)
+	}
+
+	prepaid.amount = 5
+	monitor.Check(context.Background())
+	if len(alerts) != 2 || alerts[1].Level != easysms.BalanceCritical || !alerts[1].Demoted {
+		t.Fatalf("期望余额耗尽告警并降级网关，得到%+v", alerts)
+	}
+
+	// 降级后优先使用其他网关
+	results, err := sms.Send(message.NewPhoneNumber("13800138000"), message.NewMessage().SetContent("测试消息"))
+	if err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+	if _, ok := results["prepaid"]; ok {
+		t.Error("期望降级的网关不被优先使用")
+	}
+
+	prepaid.amount = 1000
+	monitor.Check(context.Background())
+	if len(alerts) != 3 || alerts[2].Level != easysms.BalanceOK || alerts[2].Demoted {
+		t.Fatalf("期望余额恢复后还原网关，得到%+v", alerts)
+	}
+	if monitor.Level("prepaid") != easysms.BalanceOK {
+		t.Errorf("期望告警级别恢复为 ok，得到%s", monitor.Level("prepaid"))
+	}
+}
+
+func TestBalanceMonitorKeepsManualDemotion(t *testing.T) {
+	demoter := strategy.NewDemoteStrategy()
+	cfg := config.NewConfig()
+	cfg.DefaultGateways = []string{"prepaid", "manual", "mock"}
+	cfg.Strategy = demoter
+	cfg.GatewayConfigs = map[string]map[string]any{
+		"prepaid": {"balance_critical": 10.0},
+		"manual":  {"balance_critical": 10.0},
+		"mock":    {},
+	}
+
+	sms := easysms.New(cfg)
+	prepaid := &balanceGateway{amount: 500}
+	manual := &balanceGateway{amount: 500}
+	sms.RegisterGateway("prepaid", prepaid)
+	sms.RegisterGateway("manual", manual)
+	sms.RegisterGateway("mock", NewMockGateway(map[string]any{}, false))
+
+	// 手动降级的网关余额充足时不应被监控还原
+	demoter.Demote("manual")
+	monitor := sms.NewBalanceMonitor(nil)
+	monitor.Check(context.Background())
+	if !demoter.Demoted("manual") {
+		t.Fatal("期望余额充足时保留手动降级")
+	}
+
+	// 手动降级的网关余额耗尽后恢复，仍保留手动降级
+	manual.amount = 5
+	monitor.Check(context.Background())
+	manual.amount = 500
+	monitor.Check(context.Background())
+	if !demoter.Demoted("manual") {
+		t.Error("期望余额恢复后保留手动降级")
+	}
+
+	// 监控自己降级的网关余额恢复后还原
+	prepaid.amount = 5
+	monitor.Check(context.Background())
+	if !demoter.Demoted("prepaid") {
+		t.Fatal("期望余额耗尽时降级网关")
+	}
+	prepaid.amount = 500
+	monitor.Check(context.Background())
+	if demoter.Demoted("prepaid") {
+		t.Error("期望余额恢复后还原监控降级的网关")
+	}
+}
+
+func TestBalanceMonitorStrategySwap(t *testing.T) {
+	gatewayConfigs := map[string]map[string]any{
+		"prepaid": {"balance_critical": 10.0},
+		"mock":    {},
+	}
+
+	first := strategy.NewDemoteStrategy()
+	cfg := config.NewConfig()
+	cfg.DefaultGateways = []string{"prepaid", "mock"}
+	cfg.Strategy = first
+	cfg.GatewayConfigs = gatewayConfigs
+
+	sms := easysms.New(cfg)
+	prepaid := &balanceGateway{amount: 5}
+	sms.RegisterGateway("prepaid", prepaid)
+	sms.RegisterGateway("mock", NewMockGateway(map[string]any{}, false))
+
+	monitor := sms.NewBalanceMonitor(nil)
+	monitor.Check(context.Background())
+	if !first.Demoted("prepaid") {
+		t.Fatal("期望余额耗尽时降级网关")
+	}
+
+	// 替换策略后在新策略上重新降级，原策略上的降级被还原；包装策略将降级转发给降级策略
+	second := strategy.NewDemoteStrategy()
+	next := config.NewConfig()
+	next.DefaultGateways = []string{"prepaid", "mock"}
+	next.Strategy = strategy.NewRuleStrategy(nil, nil, second)
+	next.GatewayConfigs = gatewayConfigs
+	if err := sms.UpdateConfig(next); err != nil {
+		t.Fatalf("更新配置失败: %v", err)
+	}
+
+	monitor.Check(context.Background())
+	if !second.Demoted("prepaid") {
+		t.Error("期望在新策略上降级网关")
+	}
+	if first.Demoted("prepaid") {
+		t.Error("期望还原原策略上的降级")
+	}
+
+	// 余额恢复后在新策略上还原
+	prepaid.amount = 500
+	monitor.Check(context.Background())
+	if second.Demoted("prepaid") {
+		t.Error("期望余额恢复后还原网关")
+	}
+}
+
+func TestSendWithTemplateRegistry(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.DefaultGateways = []string{"unmapped", "aliyun", "qcloud", "twilio"}
+	cfg.GatewayConfigs = map[string]map[string]any{
+		"unmapped": {},
+		"aliyun":   {},
+		"qcloud":   {},
+		"twilio":   {},
+	}
+	cfg.Templates = map[string]*config.Template{
+		"login_otp": {
+			Gateways: map[string]*config.GatewayTemplate{
+				"aliyun": {ID: "SMS_152550005", Params: map[string]string{"code": "otp"}},
+				"qcloud": {ID: "1234", Order: []string{"code", "minutes"}},
+				"twilio": {Content: "Your code is {code}, valid for {minutes} minutes"},
+			},
+		},
+	}
+
+	sms := easysms.New(cfg)
+	unmapped := &recordingGateway{}
+	aliyun := &recordingGateway{fail: true}
+	qcloud := &recordingGateway{fail: true}
+	twilio := &recordingGateway{}
+	sms.RegisterGateway("unmapped", unmapped)
+	sms.RegisterGateway("aliyun", aliyun)
+	sms.RegisterGateway("qcloud", qcloud)
+	sms.RegisterGateway("twilio", twilio)
+
+	msg := message.NewMessage().SetTemplate("login_otp").SetData(map[string]any{"code": "123456", "minutes": 5})
+	results, err := sms.Send(message.NewPhoneNumber("13800138000"), msg)
+	if err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+
+	// 未配置的网关不发起请求
+	if unmapped.msg != nil {
+		t.Error("期望未配置逻辑模板的网关不被调用")
+	}
+	if gateway.ClassifyError(results["unmapped"].Error) != gateway.CategoryTemplate {
+		t.Errorf("期望未配置的网关返回模板错误，得到%v", results["unmapped"].Error)
+	}
+
+	if aliyun.msg.GetTemplate() != "SMS_152550005" || aliyun.msg.GetData()["otp"] != "123456" {
+		t.Errorf("期望阿里云使用映射后的模板和参数，得到%s %v", aliyun.msg.GetTemplate(), aliyun.msg.GetData())
+	}
+
+	if qcloud.msg.GetTemplate() != "1234" || qcloud.msg.GetData()["0"] != "123456" || qcloud.msg.GetData()["1"] != 5 {
+		t.Errorf("期望腾讯云使用有序参数，得到%s %v", qcloud.msg.GetTemplate(), qcloud.msg.GetData())
+	}
+
+	if twilio.msg.GetContent() != "Your code is 123456, valid for 5 minutes" {
+		t.Errorf("期望 twilio 使用渲染后的内容，得到%s", twilio.msg.GetContent())
+	}
+
+	// 原消息不被修改
+	if msg.GetTemplate() != "login_otp" || msg.GetData()["code"] != "123456" {
+		t.Errorf("期望原消息不被修改，得到%s %v", msg.GetTemplate(), msg.GetData())
+	}
+}
+
+func TestSimpleSendResolvesPerGateway(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.DefaultGateways = []string{"aliyun", "qcloud"}
+	cfg.GatewayConfigs = map[string]map[string]any{
+		"aliyun": {},
+		"qcloud": {},
+	}
+
+	sms := easysms.New(cfg)
+	aliyun := &recordingGateway{fail: true}
+	qcloud := &recordingGateway{}
+	sms.RegisterGateway("aliyun", aliyun)
+	sms.RegisterGateway("qcloud", qcloud)
+
+	_, err := sms.SimpleSend("13800138000", map[string]any{
+		"template": func(gateway string) string {
+			if gateway == "aliyun" {
+				return "SMS_001"
+			}
+			return "1234"
+		},
+		"data": func(gateway string) map[string]any {
+			if gateway == "aliyun" {
+				return map[string]any{"code": "6379"}
+			}
+			return map[string]any{"0": "6379"}
+		},
+	})
+	if err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+
+	if aliyun.msg.GetTemplate() != "SMS_001" || aliyun.msg.GetData()["code"] != "6379" {
+		t.Errorf("期望阿里云使用自己的模板，得到%s %v", aliyun.msg.GetTemplate(), aliyun.msg.GetData())
+	}
+
+	// 回退的网关重新解析
+	if qcloud.msg.GetTemplate() != "1234" || qcloud.msg.GetData()["0"] != "6379" {
+		t.Errorf("期望腾讯云使用自己的模板，得到%s %v", qcloud.msg.GetTemplate(), qcloud.msg.GetData())
+	}
+}
+
+// recordingGateway 记录收到的消息的测试网关
+type recordingGateway struct {
+	fail bool
+	msg  *message.Message
+	mu   sync.Mutex
+}
+
+func (g *recordingGateway) GetName() string {
+	return "recording"
+}
+
+func (g *recordingGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	g.mu.Lock()
+	g.msg = msg
+	g.mu.Unlock()
+
+	if g.fail {
+		return nil, gateway.NewSendError("recording", gateway.CategoryServer, "", "server error")
+	}
+	return map[string]any{"success": true}, nil
+}
+
+// reloadGateway 返回账号的测试网关，block 不为 nil 时关闭 started 并等待 block 关闭后才返回，fail 为 true 时返回错误
+type reloadGateway struct {
+	account string
+	fail    bool
+	started chan struct{}
+	block   chan struct{}
+}
+
+func (g *reloadGateway) GetName() string {
+	return "reload"
+}
+
+func (g *reloadGateway) Send(to *message.PhoneNumber, msg *message.Message) (any, error) {
+	if g.block != nil {
+		close(g.started)
+		<-g.block
+	}
+	if g.fail {
+		return nil, errors.New(g.account + " failed")
+	}
+	return g.account, nil
+}
+
+func init() {
+	easysms.Register("reload_test", func(config map[string]any) (gateway.Gateway, error) {
+		started, _ := config["started"].(chan struct{})
+		block, _ := config["block"].(chan struct{})
+		account, _ := config["account"].(string)
+		fail, _ := config["fail"].(bool)
+		return &reloadGateway{account: account, fail: fail, started: started, block: block}, nil
+	})
+}
+
+// 测试更新配置
+func TestUpdateConfig(t *testing.T) {
+	started, block := make(chan struct{}), make(chan struct{})
+	cfg := config.NewConfig()
+	cfg.DefaultGateways = []string{"a"}
+	cfg.GatewayConfigs = map[string]map[string]any{
+		"a":      {"driver": "reload_test", "account": "a1", "started": started, "block": block},
+		"b":      {"driver": "reload_test", "account": "b1"},
+		"manual": {},
+	}
+
+	sms := easysms.New(cfg)
+	manual := NewMockGateway(nil, false)
+	sms.RegisterGateway("manual", manual)
+	a1, _ := sms.Gateway("a")
+	b1, _ := sms.Gateway("b")
+
+	// 发送中的请求使用旧网关实例完成
+	done := make(chan map[string]easysms.Result)
+	go func() {
+		results, _ := sms.Send(message.NewPhoneNumber("13800138000"), message.NewMessage().SetContent("热更新测试"))
+		done <- results
+	}()
+	<-started
+
+	updated := config.NewConfig()
+	updated.DefaultGateways = []string{"c", "manual"}
+	updated.Strategy = strategy.NewRandomStrategy()
+	updated.GatewayConfigs = map[string]map[string]any{
+		"a":      {"driver": "reload_test", "account": "a2"},
+		"b":      {"driver": "reload_test", "account": "b1"},
+		"c":      {"driver": "reload_test", "account": "c1"},
+		"manual": {},
+	}
+	if err := sms.UpdateConfig(updated); err != nil {
+		t.Fatalf("更新配置失败: %v", err)
+	}
+
+	close(block)
+	if results := <-done; results["a"].Data != "a1" {
+		t.Errorf("期望发送中的请求使用旧实例，得到%v", results["a"])
+	}
+
+	if a2, _ := sms.Gateway("a"); a2 == a1 || a2.(*reloadGateway).account != "a2" {
+		t.Error("期望配置变化的网关被重新创建")
+	}
+	if b, _ := sms.Gateway("b"); b != b1 {
+		t.Error("期望配置未变化的网关被复用")
+	}
+	if gw, _ := sms.Gateway("manual"); gw != manual {
+		t.Error("期望手动注册的网关保持不变")
+	}
+	results, err := sms.Send(message.NewPhoneNumber("13800138000"), message.NewMessage().SetContent("热更新测试").SetGateways([]string{"c"}))
+	if err != nil || results["c"].Data != "c1" {
+		t.Errorf("期望使用新增的网关发送，得到%v, %v", results, err)
+	}
+
+	// 无效的配置不生效
+	invalid := config.NewConfig()
+	invalid.DefaultGateways = []string{"missing"}
+	invalid.GatewayConfigs = map[string]map[string]any{
+		"a":      {"driver": "reload_test", "account": "a3"},
+		"smsbao": {"user": "mock-user"},
+	}
+	err = sms.UpdateConfig(invalid)
+	var initErr *easysms.InitError
+	if !errors.As(err, &initErr) || len(initErr.Errors) != 2 {
+		t.Fatalf("期望2个错误，得到%v", err)
+	}
+	if a, _ := sms.Gateway("a"); a.(*reloadGateway).account != "a2" {
+		t.Error("期望更新失败时保持原配置")
+	}
+
+	// 删除的网关不再可用
+	removed := config.NewConfig()
+	removed.GatewayConfigs = map[string]map[string]any{
+		"a": {"driver": "reload_test", "account": "a2"},
+	}
+	if err := sms.UpdateConfig(removed); err != nil {
+		t.Fatalf("更新配置失败: %v", err)
+	}
+	if _, err := sms.Gateway("b"); err == nil {
+		t.Error("期望删除的网关不可用")
+	}
+}
+
+// 测试发送过程中更新配置，本次发送的回退仍然使用开始时的配置和网关
+func TestUpdateConfigDuringSend(t *testing.T) {
+	started, block := make(chan struct{}), make(chan struct{})
+	cfg := config.NewConfig()
+	cfg.DefaultGateways = []string{"a", "b"}
+	cfg.GatewayConfigs = map[string]map[string]any{
+		"a": {"driver": "reload_test", "account": "a1", "fail": true, "started": started, "block": block},
+		"b": {"driver": "reload_test", "account": "b1"},
+	}
+	sms := easysms.New(cfg)
+
+	type sendResult struct {
+		results map[string]easysms.Result
+		err     error
+	}
+	done := make(chan sendResult)
+	go func() {
+		results, err := sms.Send(message.NewPhoneNumber("13800138000"), message.NewMessage().SetContent("热更新测试"))
+		done <- sendResult{results, err}
+	}()
+	<-started
+
+	// 删除网关 b 并禁止回退
+	updated := config.NewConfig()
+	updated.DefaultGateways = []string{"a"}
+	updated.Fallback = &config.FallbackPolicy{
+		ShouldFallback: func(err *gateway.SendError) bool { return false },
+	}
+	updated.GatewayConfigs = map[string]map[string]any{
+		"a": {"driver": "reload_test", "account": "a2"},
+	}
+	if err := sms.UpdateConfig(updated); err != nil {
+		t.Fatalf("更新配置失败: %v", err)
+	}
+	close(block)
+
+	res := <-done
+	if res.err != nil {
+		t.Fatalf("期望按开始时的配置回退到网关b，得到错误: %v", res.err)
+	}
+	if b := res.results["b"]; b.Status != easysms.StatusSuccess || b.Data != "b1" {
+		t.Errorf("期望使用开始时的网关b发送成功，得到%v", b)
+	}
+
+	// 之后的发送使用新配置
+	results, err := sms.Send(message.NewPhoneNumber("13800138000"), message.NewMessage().SetContent("热更新测试"))
+	if err != nil || results["a"].Data != "a2" {
+		t.Errorf("期望使用新配置发送，得到%v, %v", results, err)
+	}
+}
+
+// 测试监视配置文件
+func TestConfigWatcher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sms.yaml")
+	write := func(content string, modTime time.Time) {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Now()
+	write("gateways:\n  a: {driver: reload_test, account: a1}\n", now)
+	cfg, err := config.LoadFile(path)
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+	sms := easysms.New(cfg)
+
+	var reloadErr error
+	watcher := sms.NewConfigWatcher(path)
+	watcher.OnReload = func(err error) { reloadErr = err }
+
+	if watcher.Check() {
+		t.Error("期望文件未修改时不重新加载")
+	}
+
+	write("gateways:\n  a: {driver: reload_test, account: a2}\n", now.Add(time.Second))
+	if !watcher.Check() || reloadErr != nil {
+		t.Fatalf("期望重新加载成功，得到%v", reloadErr)
+	}
+	if a, _ := sms.Gateway("a"); a.(*reloadGateway).account != "a2" {
+		t.Error("期望使用新配置")
+	}
+
+	write("gateways:\n  a: {driver: reload_test, account: a3}\n  smsbao: {user: mock-user, pasword: mock-password}\n", now.Add(2*time.Second))
+	if !watcher.Check() || reloadErr == nil {
+		t.Error("期望无效配置重新加载失败")
+	}
+	if a, _ := sms.Gateway("a"); a.(*reloadGateway).account != "a2" {
+		t.Error("期望加载失败时保持原配置")
+	}
+
+	// 加载失败后每次检查都重试，文件修正后生效
+	if !watcher.Check() || reloadErr == nil {
+		t.Error("期望加载失败后再次检查时重试")
+	}
+	write("gateways:\n  a: {driver: reload_test, account: a3}\n  smsbao: {user: mock-user, password: mock-password}\n", now.Add(3*time.Second))
+	if !watcher.Check() || reloadErr != nil {
+		t.Fatalf("期望修正后重新加载成功，得到%v", reloadErr)
+	}
+	if a, _ := sms.Gateway("a"); a.(*reloadGateway).account != "a3" {
+		t.Error("期望使用修正后的配置")
+	}
+	if watcher.Check() {
+		t.Error("期望加载成功后文件未修改时不重新加载")
+	}
+}
+
+func TestConfigWatcherKeepsStrategy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sms.yaml")
+	write := func(account string, modTime time.Time) {
+		content := "strategy: order\ndefault_gateways: [a, b]\ngateways:\n  a: {driver: reload_test, account: " + account + "}\n  b: {driver: reload_test, account: b1}\n"
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Now()
+	write("a1", now)
+	cfg, err := config.LoadFile(path)
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+
+	// 在代码中包装策略，并降级网关 a
+	demoter := strategy.NewDemoteStrategy(cfg.Strategy)
+	demoter.Demote("a")
+	cfg.Strategy = demoter
+	sms := easysms.New(cfg)
+
+	watcher := sms.NewConfigWatcher(path)
+	write("a2", now.Add(time.Second))
+	if !watcher.Check() {
+		t.Fatal("期望重新加载")
+	}
+
+	// 策略名称未变，继续使用降级策略
+	results, err := sms.Send(message.NewPhoneNumber("13800138000"), message.NewMessage().SetContent("测试消息"))
+	if err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+	if _, ok := results["a"]; ok {
+		t.Error("期望重新加载后保留原策略的降级")
+	}
+}
@@ -1,7 +1,12 @@
 package gateway
 
 import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 	"testing"
 
@@ -176,3 +181,69 @@ func TestAliyunGatewayWithMockServer(t *testing.T) {
 		t.Errorf("Expected Code to be isv.MOBILE_NUMBER_ILLEGAL, got: %v", code)
 	}
 }
+
+// rfc3986Encode percent-encodes every byte outside the RFC 3986 unreserved set
+func rfc3986Encode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// TestAliyunSignatureEncoding tests that the signature follows the RFC 3986 encoding Aliyun expects
+func TestAliyunSignatureEncoding(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var signature, expected string
+	httpmock.RegisterResponder("GET", `=~^https://dysmsapi\.aliyuncs\.com/.*`, func(r *http.Request) (*http.Response, error) {
+		query := r.URL.Query()
+		signature = query.Get("Signature")
+
+		var keys []string
+		for k := range query {
+			if k != "Signature" {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		var pairs []string
+		for _, k := range keys {
+			pairs = append(pairs, rfc3986Encode(k)+"="+rfc3986Encode(query.Get(k)))
+		}
+
+		mac := hmac.New(sha1.New, []byte("test_key_secret&"))
+		mac.Write([]byte("GET&%2F&" + rfc3986Encode(strings.Join(pairs, "&"))))
+		expected = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+		return httpmock.NewJsonResponse(http.StatusOK, map[string]any{"Code": "OK"})
+	})
+
+	g := gateway.NewAliyunGateway(map[string]any{
+		"access_key_id":     "test_key_id",
+		"access_key_secret": "test_key_secret",
+		"sign_name":         "测试签名",
+		"endpoint":          "https://dysmsapi.aliyuncs.com",
+	})
+
+	msg := message.NewMessage().
+		SetTemplate("SMS_12345678").
+		SetData(map[string]any{
+			"product": "a b*c~d",
+		})
+
+	if _, err := g.Send(message.NewPhoneNumber("13800138000"), msg); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if signature == "" || signature != expected {
+		t.Errorf("Expected signature %s, got: %s", expected, signature)
+	}
+}
@@ -0,0 +1,125 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/anhao/go-easy-sms/gateway"
+	"github.com/jarcoal/httpmock"
+)
+
+// TestQueryBalance 测试内置网关的余额查询
+func TestQueryBalance(t *testing.T) {
+	tests := []struct {
+		name      string
+		newGW     func() gateway.Gateway
+		method    string
+		url       string
+		responder httpmock.Responder
+		amount    float64
+		unit      string
+	}{
+		{
+			name: "smsbao",
+			newGW: func() gateway.Gateway {
+				return gateway.NewSmsbaoGateway(map[string]any{"user": "mock-user", "password": "mock-password"})
+			},
+			method:    "GET",
+			url:       "http://api.smsbao.com/query",
+			responder: httpmock.NewStringResponder(http.StatusOK, "0\n120,880"),
+			amount:    880,
+			unit:      gateway.BalanceUnitMessage,
+		},
+		{
+			name:      "yunpian",
+			newGW:     func() gateway.Gateway { return gateway.NewYunpianGateway(map[string]any{"api_key": "mock-api-key"}) },
+			method:    "POST",
+			url:       "https://sms.yunpian.com/v2/user/get.json",
+			responder: httpmock.NewJsonResponderOrPanic(http.StatusOK, map[string]any{"nick": "mock", "balance": 12.5}),
+			amount:    12.5,
+			unit:      gateway.BalanceUnitCNY,
+		},
+		{
+			name: "huyi",
+			newGW: func() gateway.Gateway {
+				return gateway.NewHuyiGateway(map[string]any{"api_id": "mock-api-id", "api_key": "mock-api-key"})
+			},
+			method:    "POST",
+			url:       "http://106.ihuyi.com/webservice/sms.php?method=GetNum",
+			responder: httpmock.NewJsonResponderOrPanic(http.StatusOK, map[string]any{"code": 2, "msg": "查询成功", "num": "1000"}),
+			amount:    1000,
+			unit:      gateway.BalanceUnitMessage,
+		},
+		{
+			name: "chuanglan",
+			newGW: func() gateway.Gateway {
+				return gateway.NewChuanglanGateway(map[string]any{"account": "mock-account", "password": "mock-password"})
+			},
+			method:    "POST",
+			url:       "https://smssh1.253.com/msg/balance/json",
+			responder: httpmock.NewJsonResponderOrPanic(http.StatusOK, map[string]any{"code": "0", "balance": "3000", "errorMsg": ""}),
+			amount:    3000,
+			unit:      gateway.BalanceUnitMessage,
+		},
+		{
+			name: "twilio",
+			newGW: func() gateway.Gateway {
+				return gateway.NewTwilioGateway(map[string]any{"account_sid": "mock-sid", "token": "mock-token"})
+			},
+			method:    "GET",
+			url:       "https://api.twilio.com/2010-04-01/Accounts/mock-sid/Balance.json",
+			responder: httpmock.NewJsonResponderOrPanic(http.StatusOK, map[string]any{"account_sid": "mock-sid", "balance": "0.56", "currency": "USD"}),
+			amount:    0.56,
+			unit:      "USD",
+		},
+		{
+			name: "submail",
+			newGW: func() gateway.Gateway {
+				return gateway.NewSubmailGateway(map[string]any{"app_id": "mock-app-id", "app_key": "mock-app-key"})
+			},
+			method:    "POST",
+			url:       "https://api.mysubmail.com/balance/sms.json",
+			responder: httpmock.NewJsonResponderOrPanic(http.StatusOK, map[string]any{"status": "success", "balance": "100", "transactional_balance": "20"}),
+			amount:    120,
+			unit:      gateway.BalanceUnitMessage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			httpmock.RegisterResponder(tt.method, tt.url, tt.responder)
+
+			// 网关需在 httpmock 激活后创建
+
+			querier, ok := tt.newGW().(gateway.BalanceQuerier)
+			if !ok {
+				t.Fatalf("Expected %s gateway to implement BalanceQuerier", tt.name)
+			}
+
+			balance, err := querier.QueryBalance(context.Background())
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if balance.Gateway != tt.name || balance.Amount != tt.amount || balance.Unit != tt.unit {
+				t.Errorf("Unexpected balance: %+v", balance)
+			}
+		})
+	}
+}
+
+// TestQueryBalanceError 测试余额查询失败时返回分类后的错误
+func TestQueryBalanceError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://api.smsbao.com/query", httpmock.NewStringResponder(http.StatusOK, "30"))
+
+	g := gateway.NewSmsbaoGateway(map[string]any{"user": "mock-user", "password": "wrong-password"})
+	_, err := g.QueryBalance(context.Background())
+	if gateway.ClassifyError(err) != gateway.CategoryAuth {
+		t.Errorf("Expected auth error, got: %v", err)
+	}
+}
@@ -0,0 +1,201 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/anhao/go-easy-sms/gateway"
+	"github.com/anhao/go-easy-sms/message"
+	"github.com/jarcoal/httpmock"
+)
+
+// TestBuiltinBatchGateways 测试支持批量接口的内置网关
+func TestBuiltinBatchGateways(t *testing.T) {
+	tests := []struct {
+		name string
+		gw   gateway.Gateway
+		size int
+	}{
+		{"aliyun", gateway.NewAliyunGateway(map[string]any{}), 1000},
+		{"qcloud", gateway.NewQcloudGateway(map[string]any{}), 200},
+		{"yunpian", gateway.NewYunpianGateway(map[string]any{}), 1000},
+		{"volcengine", gateway.NewVolcengineGateway(map[string]any{}), 200},
+		{"ucloud", gateway.NewUcloudGateway(map[string]any{}), 100},
+	}
+
+	for _, tt := range tests {
+		bg, ok := tt.gw.(gateway.BatchGateway)
+		if !ok {
+			t.Errorf("Expected %s gateway to implement BatchGateway", tt.name)
+			continue
+		}
+		if bg.BatchSize() != tt.size {
+			t.Errorf("Expected %s batch size to be %d, got: %d", tt.name, tt.size, bg.BatchSize())
+		}
+	}
+}
+
+// TestQcloudSendBatch 测试腾讯云批量发送
+func TestQcloudSendBatch(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	baseURL := "https://sms.tencentcloudapi.com"
+	httpmock.RegisterResponder("POST", baseURL,
+		func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			var params map[string]any
+			_ = json.Unmarshal(body, &params)
+
+			phones, _ := params["PhoneNumberSet"].([]any)
+			if len(phones) != 2 {
+				t.Errorf("Expected 2 phone numbers, got: %v", params["PhoneNumberSet"])
+			}
+
+			// 返回顺序与请求不同
+			return httpmock.NewJsonResponse(http.StatusOK, map[string]any{
+				"Response": map[string]any{
+					"SendStatusSet": []map[string]any{
+						{
+							"PhoneNumber": "+8618888888889",
+							"Code":        "InvalidParameterValue.IncorrectPhoneNumber",
+							"Message":     "incorrect phone number",
+						},
+						{
+							"PhoneNumber": "+8618888888888",
+							"SerialNo":    "2028:f825e6b16e23f73f4123",
+							"Code":        "Ok",
+							"Message":     "send success",
+						},
+					},
+				},
+			})
+		})
+
+	g := gateway.NewQcloudGateway(map[string]any{
+		"sdk_app_id": "mock-sdk-app-id",
+		"secret_key": "mock-secret-key",
+		"secret_id":  "mock-secret-id",
+		"sign_name":  "mock-api-sign-name",
+		"endpoint":   baseURL,
+	})
+
+	to := []*message.PhoneNumber{
+		message.NewPhoneNumber("18888888888"),
+		message.NewPhoneNumber("18888888889"),
+	}
+	msg := message.NewMessage().SetTemplate("template-id").SetData(map[string]any{"0": "888888"})
+
+	results, err := g.SendBatch(context.Background(), to, msg)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got: %d", len(results))
+	}
+
+	if results[0].Err != nil {
+		t.Errorf("Expected first recipient to succeed, got: %v", results[0].Err)
+	}
+
+	var sendErr *gateway.SendError
+	if !errors.As(results[1].Err, &sendErr) || sendErr.Category != gateway.CategoryInvalidNumber {
+		t.Errorf("Expected invalid number error for second recipient, got: %v", results[1].Err)
+	}
+}
+
+// TestYunpianSendBatch 测试云片批量发送
+func TestYunpianSendBatch(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", "https://sms.yunpian.com/v2/sms/batch_send.json",
+		func(req *http.Request) (*http.Response, error) {
+			_ = req.ParseForm()
+			if req.PostForm.Get("mobile") != "13800138000,13800138001" {
+				t.Errorf("Expected comma-joined mobiles, got: %s", req.PostForm.Get("mobile"))
+			}
+
+			return httpmock.NewJsonResponse(http.StatusOK, map[string]any{
+				"total_count": 1,
+				"data": []map[string]any{
+					{"code": 0, "msg": "发送成功", "mobile": "13800138000", "sid": 1},
+					{"code": 3, "msg": "账户余额不足", "mobile": "13800138001"},
+				},
+			})
+		})
+
+	g := gateway.NewYunpianGateway(map[string]any{"api_key": "test_api_key"})
+
+	to := []*message.PhoneNumber{
+		message.NewPhoneNumber("13800138000"),
+		message.NewPhoneNumber("13800138001"),
+	}
+
+	results, err := g.SendBatch(context.Background(), to, message.NewMessage().SetContent("您的验证码是：123456"))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if results[0].Err != nil {
+		t.Errorf("Expected first recipient to succeed, got: %v", results[0].Err)
+	}
+
+	var sendErr *gateway.SendError
+	if !errors.As(results[1].Err, &sendErr) || sendErr.Category != gateway.CategoryInsufficientBalance {
+		t.Errorf("Expected insufficient balance error for second recipient, got: %v", results[1].Err)
+	}
+}
+
+// TestAliyunSendBatch 测试阿里云批量发送
+func TestAliyunSendBatch(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", `=~^http://dysmsapi\.aliyuncs\.com/.*`,
+		func(req *http.Request) (*http.Response, error) {
+			phones := strings.Split(req.URL.Query().Get("PhoneNumbers"), ",")
+			if len(phones) != 3 {
+				t.Errorf("Expected 3 comma-joined phone numbers, got: %v", phones)
+			}
+
+			return httpmock.NewJsonResponse(http.StatusOK, map[string]any{
+				"Code":    "OK",
+				"Message": "OK",
+				"BizId":   "900619746936498440^0",
+			})
+		})
+
+	g := gateway.NewAliyunGateway(map[string]any{
+		"access_key_id":     "test_key_id",
+		"access_key_secret": "test_key_secret",
+		"sign_name":         "测试签名",
+	})
+
+	to := []*message.PhoneNumber{
+		message.NewPhoneNumber("13800138000"),
+		message.NewPhoneNumber("13800138001"),
+		message.NewPhoneNumber("13800138002"),
+	}
+
+	results, err := g.SendBatch(context.Background(), to, message.NewMessage().SetTemplate("SMS_001"))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got: %d", len(results))
+	}
+
+	for i, result := range results {
+		if result.Err != nil || result.Data == nil {
+			t.Errorf("Expected recipient %d to succeed, got: %+v", i, result)
+		}
+	}
+}
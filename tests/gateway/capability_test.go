@@ -0,0 +1,70 @@
+package gateway
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/anhao/go-easy-sms/gateway"
+	"github.com/anhao/go-easy-sms/message"
+)
+
+// TestBuiltinCapabilities 测试内置网关声明的能力
+func TestBuiltinCapabilities(t *testing.T) {
+	tests := []struct {
+		name string
+		gw   gateway.Gateway
+		caps gateway.Capabilities
+	}{
+		{"aliyun", gateway.NewAliyunGateway(map[string]any{}), gateway.Capabilities{Template: true, Batch: true, Unicode: true}},
+		{"twilio", gateway.NewTwilioGateway(map[string]any{}), gateway.Capabilities{International: true, Content: true, Unicode: true, MaxLength: gateway.TwilioMaxLength}},
+		{"smsbao", gateway.NewSmsbaoGateway(map[string]any{}), gateway.Capabilities{International: true, Content: true, Unicode: true}},
+		{"qcloud", gateway.NewQcloudGateway(map[string]any{}), gateway.Capabilities{International: true, Template: true, Batch: true, Unicode: true}},
+	}
+
+	for _, tt := range tests {
+		cg, ok := tt.gw.(gateway.CapableGateway)
+		if !ok {
+			t.Errorf("Expected %s gateway to implement CapableGateway", tt.name)
+			continue
+		}
+		if caps := cg.Capabilities(); caps != tt.caps {
+			t.Errorf("Expected %s capabilities to be %+v, got: %+v", tt.name, tt.caps, caps)
+		}
+	}
+}
+
+// TestCapabilitiesSupports 测试按能力判断能否发送
+func TestCapabilitiesSupports(t *testing.T) {
+	domestic := message.NewPhoneNumber("13800138000")
+	intl := message.NewPhoneNumber("7911123456", 44)
+	content := message.NewMessage().SetContent("您的验证码为: 6379")
+	template := message.NewMessage().SetTemplate("SMS_001")
+
+	tests := []struct {
+		name      string
+		caps      gateway.Capabilities
+		to        *message.PhoneNumber
+		msg       *message.Message
+		supported bool
+	}{
+		{"template only with template", gateway.Capabilities{Template: true, Unicode: true}, domestic, template, true},
+		{"template only with content", gateway.Capabilities{Template: true, Unicode: true}, domestic, content, false},
+		{"content only with template", gateway.Capabilities{Content: true, Unicode: true}, domestic, template, false},
+		{"domestic only", gateway.Capabilities{Content: true, Unicode: true}, intl, content, false},
+		{"international", gateway.Capabilities{International: true, Content: true, Unicode: true}, intl, content, true},
+		{"voice", gateway.Capabilities{Content: true, Unicode: true}, domestic, message.NewMessage().SetContent("6379").SetType(message.VoiceMessage), false},
+		{"ascii only", gateway.Capabilities{Content: true}, domestic, content, false},
+		{"max length", gateway.Capabilities{Content: true, Unicode: true, MaxLength: 10}, domestic, message.NewMessage().SetContent(strings.Repeat("验", 11)), false},
+	}
+
+	for _, tt := range tests {
+		err := tt.caps.Supports(tt.to, tt.msg)
+		if tt.supported && err != nil {
+			t.Errorf("%s: expected to be supported, got: %v", tt.name, err)
+		}
+		if !tt.supported && !errors.Is(err, gateway.ErrUnsupported) {
+			t.Errorf("%s: expected ErrUnsupported, got: %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package gateway
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/anhao/go-easy-sms/gateway"
+)
+
+// TestParseConfig 测试将通用配置解码为类型化配置
+func TestParseConfig(t *testing.T) {
+	cfg, err := gateway.ParseConfig("aliyun", map[string]any{
+		"access_key_id":     "mock-key-id",
+		"access_key_secret": "mock-key-secret",
+		"sign_name":         "测试签名",
+		"region_id":         "cn-shanghai",
+		"timeout":           3,
+		"balance_threshold": "100",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	aliyun, ok := cfg.(*gateway.AliyunConfig)
+	if !ok {
+		t.Fatalf("Expected *AliyunConfig, got: %T", cfg)
+	}
+	if aliyun.AccessKeyID != "mock-key-id" || aliyun.SignName != "测试签名" || aliyun.RegionID != "cn-shanghai" {
+		t.Errorf("Unexpected aliyun config: %+v", aliyun)
+	}
+	if aliyun.Timeout != 3 || aliyun.BalanceThreshold != 100 {
+		t.Errorf("Expected common config to be decoded, got: %+v", aliyun.CommonConfig)
+	}
+
+	// 数字和字符串按配置项的类型转换
+	cfg, err = gateway.ParseConfig("yunxin", map[string]any{
+		"app_key":     "mock-app-key",
+		"app_secret":  "mock-app-secret",
+		"code_length": "6",
+		"need_up":     "true",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if yunxin := cfg.(*gateway.YunxinConfig); yunxin.CodeLength != 6 || !yunxin.NeedUp {
+		t.Errorf("Unexpected yunxin config: %+v", yunxin)
+	}
+
+	// 自定义网关不校验
+	cfg, err = gateway.ParseConfig("custom", map[string]any{"anything": 1})
+	if cfg != nil || err != nil {
+		t.Errorf("Expected custom gateway to be skipped, got: %v, %v", cfg, err)
+	}
+}
+
+// TestParseConfigInvalid 测试无效的网关配置
+func TestParseConfigInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		gateway string
+		config  map[string]any
+		want    string
+	}{
+		{
+			name:    "unknown key",
+			gateway: "aliyun",
+			config:  map[string]any{"acess_key_id": "mock", "access_key_secret": "mock", "sign_name": "mock"},
+			want:    `unknown key "acess_key_id"`,
+		},
+		{
+			name:    "missing required",
+			gateway: "twilio",
+			config:  map[string]any{"account_sid": "mock"},
+			want:    "missing token, from",
+		},
+		{
+			name:    "wrong type",
+			gateway: "moduyun",
+			config:  map[string]any{"accesskey": "mock", "secretkey": "mock", "type": "marketing"},
+			want:    "type: expected an integer",
+		},
+		{
+			name:    "unknown channel",
+			gateway: "chuanglan",
+			config:  map[string]any{"account": "mock", "password": "mock", "channel": "smsbj2"},
+			want:    `unknown channel "smsbj2"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := gateway.ParseConfig(tt.gateway, tt.config)
+			if !errors.Is(err, gateway.ErrInvalidConfig) {
+				t.Fatalf("Expected ErrInvalidConfig, got: %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error to contain %q, got: %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,306 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/anhao/go-easy-sms/gateway"
+	easyhttp "github.com/anhao/go-easy-sms/http"
+	"github.com/anhao/go-easy-sms/message"
+	"github.com/jarcoal/httpmock"
+)
+
+// TestSmsbaoSendError 测试短信宝错误码分类
+func TestSmsbaoSendError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	tests := []struct {
+		status   string
+		category gateway.ErrorCategory
+	}{
+		{"30", gateway.CategoryAuth},
+		{"41", gateway.CategoryInsufficientBalance},
+		{"50", gateway.CategorySensitiveContent},
+		{"99", gateway.CategoryUnknown},
+	}
+
+	g := gateway.NewSmsbaoGateway(map[string]any{
+		"user":     "mock-user",
+		"password": "mock-password",
+	})
+
+	for _, tt := range tests {
+		httpmock.RegisterResponder("GET", "http://api.smsbao.com/sms",
+			httpmock.NewStringResponder(200, tt.status))
+
+		_, err := g.Send(message.NewPhoneNumber("18188888888"), message.NewMessage().SetContent("This is a test message."))
+
+		var sendErr *gateway.SendError
+		if !errors.As(err, &sendErr) {
+			t.Fatalf("Expected SendError, got: %T", err)
+		}
+
+		if sendErr.Category != tt.category {
+			t.Errorf("Expected category of %s to be %s, got: %s", tt.status, tt.category, sendErr.Category)
+		}
+
+		if sendErr.Code != tt.status || sendErr.Gateway != "smsbao" {
+			t.Errorf("Unexpected SendError: %+v", sendErr)
+		}
+
+		if sendErr.Retryable() {
+			t.Errorf("Expected status %s not to be retryable", tt.status)
+		}
+	}
+}
+
+// TestYunpianSendError 测试云片错误码分类
+func TestYunpianSendError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", "https://sms.yunpian.com/v2/sms/single_send.json",
+		httpmock.NewJsonResponderOrPanic(http.StatusOK, map[string]any{
+			"code": 10,
+			"msg":  "手机号防骚扰名单过滤",
+		}))
+
+	g := gateway.NewYunpianGateway(map[string]any{
+		"api_key": "test_api_key",
+	})
+
+	_, err := g.Send(message.NewPhoneNumber("13800138000"), message.NewMessage().SetContent("您的验证码是：123456"))
+
+	var sendErr *gateway.SendError
+	if !errors.As(err, &sendErr) {
+		t.Fatalf("Expected SendError, got: %T", err)
+	}
+
+	if sendErr.Category != gateway.CategoryBlacklisted || sendErr.Code != "10" {
+		t.Errorf("Unexpected SendError: %+v", sendErr)
+	}
+
+	if err.Error() != "yunpian gateway error: 手机号防骚扰名单过滤" {
+		t.Errorf("Expected error message to be kept, got: %s", err.Error())
+	}
+}
+
+// TestGatewayErrorCodes 测试各网关错误码分类，未收录的错误码根据错误信息推断
+func TestGatewayErrorCodes(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	phone := message.NewPhoneNumber("18888888888")
+	msg := message.NewMessage().SetContent("测试消息").SetTemplate("mock-template").SetData(map[string]any{"code": "1234"})
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		body     map[string]any
+		gateway  gateway.Gateway
+		category gateway.ErrorCategory
+		inferred bool
+	}{
+		{
+			name:     "ucloud",
+			method:   "GET",
+			url:      "https://api.ucloud.cn",
+			body:     map[string]any{"RetCode": 171, "Message": "Signature VerifyAC Error"},
+			gateway:  gateway.NewUcloudGateway(map[string]any{"private_key": "mock", "public_key": "mock", "sig_content": "mock"}),
+			category: gateway.CategoryAuth,
+		},
+		{
+			name:     "submail",
+			method:   "POST",
+			url:      "https://api.mysubmail.com/sms/send.json",
+			body:     map[string]any{"status": "error", "code": 106, "msg": "This account has been disabled"},
+			gateway:  gateway.NewSubmailGateway(map[string]any{"app_id": "mock", "app_key": "mock", "project": "mock"}),
+			category: gateway.CategoryAuth,
+		},
+		{
+			name:     "moduyun",
+			method:   "POST",
+			url:      `=~^https://live\.moduyun\.com/sms/v2/sendsinglesms`,
+			body:     map[string]any{"result": 1016, "errmsg": "mobile format error"},
+			gateway:  gateway.NewModuyunGateway(map[string]any{"accesskey": "mock", "secretkey": "mock", "signId": "mock"}),
+			category: gateway.CategoryInvalidNumber,
+		},
+		{
+			name:     "volcengine",
+			method:   "POST",
+			url:      `=~^https://sms.volcengineapi.com\?Action=SendSms`,
+			body:     map[string]any{"ResponseMetadata": map[string]any{"Error": map[string]any{"Code": "FlowLimitExceeded", "Message": "Flow limit exceeded"}}},
+			gateway:  gateway.NewVolcengineGateway(map[string]any{"access_key_id": "mock", "access_key_secret": "mock", "sign_name": "mock", "sms_account": "mock"}),
+			category: gateway.CategoryRateLimited,
+		},
+		{
+			name:     "huaxin",
+			method:   "POST",
+			url:      "http://127.0.0.1/smsJson.aspx",
+			body:     map[string]any{"returnstatus": "Faild", "message": "用户名或密码错误"},
+			gateway:  gateway.NewHuaxinGateway(map[string]any{"user_id": "mock", "account": "mock", "password": "mock", "ip": "127.0.0.1"}),
+			category: gateway.CategoryAuth,
+		},
+		{
+			// 未收录的错误码，错误信息中的 mobile 不代表号码无效
+			name:     "baidu fallback",
+			method:   "POST",
+			url:      "http://smsv3.bj.baidubce.com/api/v3/sendSms",
+			body:     map[string]any{"code": 999, "message": "mobile parameter missing"},
+			gateway:  gateway.NewBaiduGateway(map[string]any{"ak": "mock", "sk": "mock", "invoke_id": "mock"}),
+			category: gateway.CategoryInvalidParam,
+			inferred: true,
+		},
+		{
+			// sub_code 收录时覆盖根据错误信息推断的分类
+			name:     "aliyunrest sub_code",
+			method:   "POST",
+			url:      `=~^http://gw\.api\.taobao\.com/router/rest`,
+			body:     map[string]any{"error_response": map[string]any{"code": 99, "msg": "Invalid arguments", "sub_code": "isv.MOBILE_NUMBER_ILLEGAL"}},
+			gateway:  gateway.NewAliyunrestGateway(map[string]any{"app_key": "mock", "app_secret_key": "mock", "sign_name": "mock"}),
+			category: gateway.CategoryInvalidNumber,
+		},
+		{
+			// 错误信息为空时根据描述性的错误码推断
+			name:     "qiniu error code",
+			method:   "POST",
+			url:      "https://sms.qiniuapi.com/v1/message/single",
+			body:     map[string]any{"error": "BadToken"},
+			gateway:  gateway.NewQiniuGateway(map[string]any{"access_key": "mock", "secret_key": "mock"}),
+			category: gateway.CategoryAuth,
+			inferred: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.RegisterResponder(tt.method, tt.url, httpmock.NewJsonResponderOrPanic(http.StatusOK, tt.body))
+
+			_, err := tt.gateway.Send(phone, msg)
+
+			var sendErr *gateway.SendError
+			if !errors.As(err, &sendErr) {
+				t.Fatalf("Expected SendError, got: %v", err)
+			}
+			if sendErr.Category != tt.category || sendErr.Inferred != tt.inferred {
+				t.Errorf("Expected category %s (inferred %v), got: %s (inferred %v)", tt.category, tt.inferred, sendErr.Category, sendErr.Inferred)
+			}
+		})
+	}
+}
+
+// TestClassifyError 测试非网关返回错误的分类
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		category gateway.ErrorCategory
+	}{
+		{"network", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, gateway.CategoryNetwork},
+		{"server", &easyhttp.StatusError{StatusCode: 502}, gateway.CategoryServer},
+		{"rate limit", &easyhttp.StatusError{StatusCode: 429}, gateway.CategoryRateLimited},
+		{"wrapped", fmt.Errorf("failed to send request: %w", &easyhttp.StatusError{StatusCode: 503}), gateway.CategoryServer},
+		{"plain", errors.New("something wrong"), gateway.CategoryUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := gateway.ClassifyError(tt.err); got != tt.category {
+			t.Errorf("%s: expected category %s, got: %s", tt.name, tt.category, got)
+		}
+	}
+
+	// WrapError 保留原有的错误信息和错误链
+	cause := &easyhttp.StatusError{StatusCode: 503}
+	wrapped := gateway.WrapError("aliyun", cause)
+	if wrapped.Error() != cause.Error() || !errors.Is(wrapped, cause) || !wrapped.Retryable() {
+		t.Errorf("Unexpected wrapped error: %+v", wrapped)
+	}
+
+	// 已经是 SendError 时直接返回
+	sendErr := gateway.NewSendError("aliyun", gateway.CategoryInvalidNumber, "isv.MOBILE_NUMBER_ILLEGAL", "非法手机号")
+	if gateway.WrapError("aliyun", fmt.Errorf("wrap: %w", sendErr)) != sendErr {
+		t.Error("Expected WrapError to return the existing SendError")
+	}
+
+	// 网关实例名称与驱动名称不同时使用实例名称
+	instance := gateway.WrapError("aliyun_backup", sendErr)
+	if instance.Gateway != "aliyun_backup" || instance.Category != gateway.CategoryInvalidNumber || instance.Error() != "aliyun_backup gateway error: [isv.MOBILE_NUMBER_ILLEGAL] 非法手机号" {
+		t.Errorf("Expected the instance name, got: %+v", instance)
+	}
+	if sendErr.Gateway != "aliyun" {
+		t.Error("Expected the original SendError to be unchanged")
+	}
+}
+
+// TestStatusErrorBody 测试服务端返回 5xx 或 429 时优先使用响应体中的服务商错误码
+func TestStatusErrorBody(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		category gateway.ErrorCategory
+		code     string
+	}{
+		{"vendor code", http.StatusTooManyRequests, `{"Code":"isv.AMOUNT_NOT_ENOUGH","Message":"账户余额不足"}`, gateway.CategoryInsufficientBalance, "isv.AMOUNT_NOT_ENOUGH"},
+		{"unknown vendor code", http.StatusServiceUnavailable, `{"Code":"isv.UNKNOWN","Message":"服务暂不可用"}`, gateway.CategoryServer, "isv.UNKNOWN"},
+		{"no vendor error", http.StatusBadGateway, `{"Code":"OK"}`, gateway.CategoryServer, ""},
+		{"not json", http.StatusBadGateway, `<html>Bad Gateway</html>`, gateway.CategoryServer, ""},
+	}
+
+	g := gateway.NewAliyunGateway(map[string]any{
+		"access_key_id":     "mock-access-key-id",
+		"access_key_secret": "mock-access-key-secret",
+		"sign_name":         "mock-sign-name",
+		"endpoint":          "https://dysmsapi.aliyuncs.com",
+	})
+	msg := message.NewMessage().SetTemplate("SMS_001").SetData(map[string]any{"code": "1234"})
+
+	for _, tt := range tests {
+		httpmock.RegisterResponder("GET", `=~^https://dysmsapi\.aliyuncs\.com/.*`,
+			httpmock.NewStringResponder(tt.status, tt.body))
+
+		_, err := g.Send(message.NewPhoneNumber("18888888888"), msg)
+		if got := gateway.ClassifyError(err); got != tt.category {
+			t.Errorf("%s: expected category %s, got: %s (%v)", tt.name, tt.category, got, err)
+		}
+
+		var statusErr *easyhttp.StatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != tt.status {
+			t.Errorf("%s: expected the status error in the chain, got: %v", tt.name, err)
+		}
+
+		var sendErr *gateway.SendError
+		if tt.code != "" && (!errors.As(err, &sendErr) || sendErr.Code != tt.code) {
+			t.Errorf("%s: expected vendor code %s, got: %v", tt.name, tt.code, err)
+		}
+	}
+}
+
+// TestBaseGatewayStatusError 测试 BaseGateway 请求出错时不返回响应，响应体保留在 *http.StatusError 中
+func TestBaseGatewayStatusError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", "https://example.com/send",
+		httpmock.NewStringResponder(http.StatusServiceUnavailable, `{"code":503}`))
+
+	g := gateway.NewBaseGateway("test", map[string]any{})
+
+	result, err := g.PostJSON("https://example.com/send", map[string]any{}, nil)
+	if result != nil {
+		t.Errorf("Expected nil result on error, got: %v", result)
+	}
+
+	var statusErr *easyhttp.StatusError
+	if !errors.As(err, &statusErr) || string(statusErr.Body) != `{"code":503}` {
+		t.Errorf("Expected the response body in the status error, got: %v", err)
+	}
+}
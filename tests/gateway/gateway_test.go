@@ -1,9 +1,12 @@
 package gateway_test
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/anhao/go-easy-sms/gateway"
+	"github.com/anhao/go-easy-sms/message"
 )
 
 func TestBaseGateway(t *testing.T) {
@@ -90,3 +93,62 @@ func TestBaseGateway(t *testing.T) {
 		t.Errorf("Expected GetConfigBool(non_existent, false) to return false")
 	}
 }
+
+func TestBuiltinGatewaysImplementContextGateway(t *testing.T) {
+	config := map[string]any{}
+	gateways := []gateway.Gateway{
+		gateway.NewAliyunGateway(config),
+		gateway.NewAliyunIntlGateway(config),
+		gateway.NewAliyunrestGateway(config),
+		gateway.NewBaiduGateway(config),
+		gateway.NewChuanglanGateway(config),
+		gateway.NewChuanglanv1Gateway(config),
+		gateway.NewCtyunGateway(config),
+		gateway.NewErrorlogGateway(config),
+		gateway.NewHuaxinGateway(config),
+		gateway.NewHuyiGateway(config),
+		gateway.NewJuheGateway(config),
+		gateway.NewKingttoGateway(config),
+		gateway.NewLuosimaoGateway(config),
+		gateway.NewMaapGateway(config),
+		gateway.NewModuyunGateway(config),
+		gateway.NewNowcnGateway(config),
+		gateway.NewQcloudGateway(config),
+		gateway.NewQiniuGateway(config),
+		gateway.NewRongcloudGateway(config),
+		gateway.NewRongheyunGateway(config),
+		gateway.NewSendcloudGateway(config),
+		gateway.NewSmsbaoGateway(config),
+		gateway.NewSubmailGateway(config),
+		gateway.NewTwilioGateway(config),
+		gateway.NewUcloudGateway(config),
+		gateway.NewUe35Gateway(config),
+		gateway.NewVolcengineGateway(config),
+		gateway.NewYidongmasblackGateway(config),
+		gateway.NewYunpianGateway(config),
+		gateway.NewYuntongxunGateway(config),
+		gateway.NewYunxinGateway(config),
+		gateway.NewYunzhixunGateway(config),
+	}
+
+	for _, g := range gateways {
+		if _, ok := g.(gateway.ContextGateway); !ok {
+			t.Errorf("Expected %s to implement ContextGateway", g.GetName())
+		}
+	}
+}
+
+func TestSendWithCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	g := gateway.NewSmsbaoGateway(map[string]any{
+		"user":     "mock-user",
+		"password": "mock-password",
+	})
+
+	_, err := gateway.SendWithContext(ctx, g, message.NewPhoneNumber("18188888888"), message.NewMessage().SetContent("test"))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got: %v", err)
+	}
+}
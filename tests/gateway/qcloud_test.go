@@ -1,9 +1,16 @@
 package gateway
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/anhao/go-easy-sms/gateway"
 	"github.com/anhao/go-easy-sms/message"
@@ -211,3 +218,109 @@ func TestQcloudGatewayWithSignName(t *testing.T) {
 func contains(s, substr string) bool {
 	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
 }
+
+// TestQcloudGatewayOrderedParams 测试以数字为键的模板参数按顺序传递
+func TestQcloudGatewayOrderedParams(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var paramSet []any
+	httpmock.RegisterResponder("POST", "https://sms.tencentcloudapi.com",
+		func(req *http.Request) (*http.Response, error) {
+			var params map[string]any
+			_ = json.NewDecoder(req.Body).Decode(&params)
+			paramSet, _ = params["TemplateParamSet"].([]any)
+
+			return httpmock.NewJsonResponse(http.StatusOK, map[string]any{
+				"Response": map[string]any{
+					"SendStatusSet": []map[string]any{{"PhoneNumber": "+8618888888888", "Code": "Ok"}},
+				},
+			})
+		})
+
+	g := gateway.NewQcloudGateway(map[string]any{
+		"sdk_app_id": "1400000000",
+		"secret_id":  "mock-secret-id",
+		"secret_key": "mock-secret-key",
+	})
+
+	data := map[string]any{}
+	for i := 0; i < 12; i++ {
+		data[strconv.Itoa(i)] = i
+	}
+	msg := message.NewMessage().SetTemplate("1234").SetData(data)
+
+	if _, err := g.Send(message.NewPhoneNumber("18888888888"), msg); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(paramSet) != 12 {
+		t.Fatalf("Expected 12 params, got: %v", paramSet)
+	}
+	for i, v := range paramSet {
+		if v != strconv.Itoa(i) {
+			t.Errorf("Expected params in order, got: %v", paramSet)
+			break
+		}
+	}
+}
+
+// TestQcloudSignature 测试 TC3-HMAC-SHA256 签名，派生密钥使用原始的 HMAC 结果
+func TestQcloudSignature(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	hmacSHA256 := func(key []byte, data string) []byte {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(data))
+		return mac.Sum(nil)
+	}
+	sha256Hex := func(data string) string {
+		sum := sha256.Sum256([]byte(data))
+		return hex.EncodeToString(sum[:])
+	}
+
+	var authorization, expected string
+	httpmock.RegisterResponder("POST", "https://sms.tencentcloudapi.com",
+		func(r *http.Request) (*http.Response, error) {
+			authorization = r.Header.Get("Authorization")
+			body, _ := io.ReadAll(r.Body)
+
+			timestamp := r.Header.Get("X-TC-Timestamp")
+			unix, _ := strconv.ParseInt(timestamp, 10, 64)
+			date := time.Unix(unix, 0).UTC().Format("2006-01-02")
+
+			canonicalRequest := "POST\n/\n\ncontent-type:application/json; charset=utf-8\nhost:sms.tencentcloudapi.com\n\ncontent-type;host\n" + sha256Hex(string(body))
+			scope := date + "/sms/tc3_request"
+			stringToSign := "TC3-HMAC-SHA256\n" + timestamp + "\n" + scope + "\n" + sha256Hex(canonicalRequest)
+
+			secretDate := hmacSHA256([]byte("TC3mock-secret-key"), date)
+			secretService := hmacSHA256(secretDate, "sms")
+			secretSigning := hmacSHA256(secretService, "tc3_request")
+			signature := hex.EncodeToString(hmacSHA256(secretSigning, stringToSign))
+
+			expected = "TC3-HMAC-SHA256 Credential=mock-secret-id/" + scope + ", SignedHeaders=content-type;host, Signature=" + signature
+
+			return httpmock.NewJsonResponse(http.StatusOK, map[string]any{
+				"Response": map[string]any{
+					"SendStatusSet": []map[string]any{{"Code": "Ok", "SerialNo": "mock-serial-no"}},
+				},
+			})
+		})
+
+	g := gateway.NewQcloudGateway(map[string]any{
+		"sdk_app_id": "mock-sdk-app-id",
+		"secret_key": "mock-secret-key",
+		"secret_id":  "mock-secret-id",
+		"sign_name":  "mock-api-sign-name",
+	})
+
+	msg := message.NewMessage().SetTemplate("template-id").SetData(map[string]any{"0": "888888"})
+	if _, err := g.Send(message.NewPhoneNumber("18888888888"), msg); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if authorization == "" || authorization != expected {
+		t.Errorf("Expected authorization %s, got: %s", expected, authorization)
+	}
+}
@@ -0,0 +1,266 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/anhao/go-easy-sms/gateway"
+	"github.com/anhao/go-easy-sms/message"
+	"github.com/anhao/go-easy-sms/receipt"
+	"github.com/jarcoal/httpmock"
+)
+
+// TestBuiltinStatusQueriers 测试支持状态查询的内置网关
+func TestBuiltinStatusQueriers(t *testing.T) {
+	tests := []struct {
+		name   string
+		gw     gateway.Gateway
+		result any
+		id     string
+	}{
+		{"aliyun", gateway.NewAliyunGateway(map[string]any{}), map[string]any{"BizId": "900619746936498440^0"}, "900619746936498440^0"},
+		{"qcloud", gateway.NewQcloudGateway(map[string]any{}), map[string]any{
+			"Response": map[string]any{
+				"SendStatusSet": []any{map[string]any{"SerialNo": "2028:f825e6b16e23f73f4123", "Code": "Ok"}},
+			},
+		}, "2028:f825e6b16e23f73f4123"},
+		{"yunpian", gateway.NewYunpianGateway(map[string]any{}), map[string]any{"code": float64(0), "sid": float64(3310228982)}, "3310228982"},
+	}
+
+	for _, tt := range tests {
+		querier, ok := tt.gw.(gateway.StatusQuerier)
+		if !ok {
+			t.Errorf("Expected %s gateway to implement StatusQuerier", tt.name)
+			continue
+		}
+		if id := querier.MessageID(tt.result); id != tt.id {
+			t.Errorf("Expected %s message id to be %s, got: %s", tt.name, tt.id, id)
+		}
+	}
+}
+
+// TestAliyunQueryStatus 测试阿里云 QuerySendDetails 查询
+func TestAliyunQueryStatus(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", `=~^https://dysmsapi\.aliyuncs\.com/.*`, func(r *http.Request) (*http.Response, error) {
+		query := r.URL.Query()
+		if query.Get("Action") != "QuerySendDetails" || query.Get("BizId") != "900619746936498440^0" || query.Get("PhoneNumber") != "18888888888" {
+			t.Errorf("Unexpected query: %v", query)
+		}
+		if query.Get("SendDate") == "" || query.Get("Signature") == "" {
+			t.Errorf("Expected SendDate and Signature, got: %v", query)
+		}
+
+		return httpmock.NewJsonResponse(http.StatusOK, map[string]any{
+			"Code":       "OK",
+			"TotalCount": 1,
+			"SmsSendDetailDTOs": map[string]any{
+				"SmsSendDetailDTO": []map[string]any{
+					{
+						"ErrCode":     "DELIVERED",
+						"PhoneNum":    "18888888888",
+						"SendDate":    "2024-01-08 16:44:10",
+						"ReceiveDate": "2024-01-08 16:44:13",
+						"SendStatus":  3,
+					},
+				},
+			},
+		})
+	})
+
+	g := gateway.NewAliyunGateway(map[string]any{
+		"access_key_id":     "mock-access-key-id",
+		"access_key_secret": "mock-access-key-secret",
+		"sign_name":         "mock-sign-name",
+		"endpoint":          "https://dysmsapi.aliyuncs.com",
+	})
+
+	report, err := g.QueryStatus(context.Background(), "900619746936498440^0", message.NewPhoneNumber("18888888888"))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if report.Status != receipt.StatusDelivered || report.ErrorCode != "DELIVERED" || report.Phone != "18888888888" {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	if report.ReportTime.IsZero() {
+		t.Errorf("Expected report time to be parsed")
+	}
+}
+
+// TestAliyunQueryStatusPending 测试阿里云尚无发送记录时返回等待状态
+func TestAliyunQueryStatusPending(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", `=~^https://dysmsapi\.aliyuncs\.com/.*`,
+		httpmock.NewJsonResponderOrPanic(http.StatusOK, map[string]any{
+			"Code":              "OK",
+			"TotalCount":        0,
+			"SmsSendDetailDTOs": map[string]any{"SmsSendDetailDTO": []any{}},
+		}))
+
+	g := gateway.NewAliyunGateway(map[string]any{
+		"access_key_id":     "mock-access-key-id",
+		"access_key_secret": "mock-access-key-secret",
+		"sign_name":         "mock-sign-name",
+		"endpoint":          "https://dysmsapi.aliyuncs.com",
+	})
+
+	report, err := g.QueryStatus(context.Background(), "900619746936498440^0", message.NewPhoneNumber("18888888888"))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if report.Status != receipt.StatusPending {
+		t.Errorf("Expected pending status, got: %s", report.Status)
+	}
+
+	// 依次查询查询范围内的每一天
+	if count := httpmock.GetTotalCallCount(); count != 3 {
+		t.Errorf("Expected 3 requests, got: %d", count)
+	}
+}
+
+// TestQcloudQueryStatus 测试腾讯云按号码拉取状态报告
+func TestQcloudQueryStatus(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", "https://sms.tencentcloudapi.com",
+		func(req *http.Request) (*http.Response, error) {
+			if action := req.Header.Get("X-TC-Action"); action != "PullSmsSendStatusByPhoneNumber" {
+				t.Errorf("Expected PullSmsSendStatusByPhoneNumber action, got: %s", action)
+			}
+
+			body, _ := io.ReadAll(req.Body)
+			var params map[string]any
+			_ = json.Unmarshal(body, &params)
+			if params["PhoneNumber"] != "+8618888888888" || params["SmsSdkAppId"] != "1400000000" {
+				t.Errorf("Unexpected params: %v", params)
+			}
+
+			return httpmock.NewJsonResponse(http.StatusOK, map[string]any{
+				"Response": map[string]any{
+					"PullSmsSendStatusSet": []map[string]any{
+						{
+							"SerialNo":         "2028:other",
+							"SubscriberNumber": "18888888888",
+							"ReportStatus":     "SUCCESS",
+						},
+						{
+							"SerialNo":         "2028:f825e6b16e23f73f4123",
+							"SubscriberNumber": "18888888888",
+							"ReportStatus":     "FAIL",
+							"Description":      "MK:0001",
+							"UserReceiveTime":  1704703453,
+						},
+					},
+				},
+			})
+		})
+
+	g := gateway.NewQcloudGateway(map[string]any{
+		"sdk_app_id": "1400000000",
+		"secret_id":  "mock-secret-id",
+		"secret_key": "mock-secret-key",
+	})
+
+	report, err := g.QueryStatus(context.Background(), "2028:f825e6b16e23f73f4123", message.NewPhoneNumber("18888888888", 86))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if report.Status != receipt.StatusFailed || report.ErrorMessage != "MK:0001" {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	if report.ReportTime.Unix() != 1704703453 {
+		t.Errorf("Expected report time to be parsed, got: %v", report.ReportTime)
+	}
+}
+
+// TestYunpianQueryStatus 测试云片 pull_status 拉取的报告被缓存供后续查询
+func TestYunpianQueryStatus(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", "https://sms.yunpian.com/v2/sms/pull_status.json",
+		httpmock.NewJsonResponderOrPanic(http.StatusOK, []map[string]any{
+			{
+				"sid":               9527,
+				"mobile":            "18888888888",
+				"report_status":     "SUCCESS",
+				"user_receive_time": "2024-01-08 16:44:13",
+			},
+			{
+				"sid":               9528,
+				"mobile":            "18888888889",
+				"report_status":     "FAIL",
+				"error_msg":         "UNDELIV",
+				"user_receive_time": "2024-01-08 16:44:15",
+			},
+		}))
+
+	g := gateway.NewYunpianGateway(map[string]any{"api_key": "mock-api-key"})
+
+	report, err := g.QueryStatus(context.Background(), "9527", message.NewPhoneNumber("18888888888"))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if report.Status != receipt.StatusDelivered {
+		t.Errorf("Expected delivered status, got: %s", report.Status)
+	}
+
+	// 第二条报告已在上次拉取时缓存
+	report, err = g.QueryStatus(context.Background(), "9528", message.NewPhoneNumber("18888888889"))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if report.Status != receipt.StatusFailed || report.ErrorCode != "UNDELIV" {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	if count := httpmock.GetTotalCallCount(); count != 1 {
+		t.Errorf("Expected 1 request, got: %d", count)
+	}
+}
+
+// TestYunpianQueryStatusError 测试云片 pull_status 返回错误码
+func TestYunpianQueryStatusError(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", "https://sms.yunpian.com/v2/sms/pull_status.json",
+		httpmock.NewJsonResponderOrPanic(http.StatusOK, map[string]any{
+			"code": -1,
+			"msg":  "非法的apikey",
+		}))
+
+	g := gateway.NewYunpianGateway(map[string]any{"api_key": "mock-api-key"})
+
+	_, err := g.QueryStatus(context.Background(), "9527", message.NewPhoneNumber("18888888888"))
+	if gateway.ClassifyError(err) != gateway.CategoryAuth {
+		t.Errorf("Expected auth error, got: %v", err)
+	}
+}
+
+// TestYunpianQueryStatusMalformed 测试云片 pull_status 返回无法解析的响应
+func TestYunpianQueryStatusMalformed(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", "https://sms.yunpian.com/v2/sms/pull_status.json",
+		httpmock.NewStringResponder(http.StatusOK, `"maintenance"`))
+
+	g := gateway.NewYunpianGateway(map[string]any{"api_key": "mock-api-key"})
+
+	_, err := g.QueryStatus(context.Background(), "9527", message.NewPhoneNumber("18888888888"))
+	// 返回按错误对象解析时的错误
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) || typeErr.Type.Kind() != reflect.Map {
+		t.Errorf("Expected the error object parse error in the chain, got: %v", err)
+	}
+}
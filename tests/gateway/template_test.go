@@ -0,0 +1,204 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/anhao/go-easy-sms/gateway"
+	"github.com/jarcoal/httpmock"
+)
+
+// TestBuiltinTemplateManagers 测试支持模板管理的内置网关
+func TestBuiltinTemplateManagers(t *testing.T) {
+	for name, gw := range map[string]gateway.Gateway{
+		"aliyun": gateway.NewAliyunGateway(map[string]any{}),
+		"qcloud": gateway.NewQcloudGateway(map[string]any{}),
+	} {
+		if _, ok := gw.(gateway.TemplateManager); !ok {
+			t.Errorf("Expected %s gateway to implement TemplateManager", name)
+		}
+	}
+}
+
+// TestAliyunTemplateManager 测试阿里云模板和签名管理
+func TestAliyunTemplateManager(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var actions []string
+	httpmock.RegisterResponder("GET", `=~^https://dysmsapi\.aliyuncs\.com/.*`, func(r *http.Request) (*http.Response, error) {
+		query := r.URL.Query()
+		actions = append(actions, query.Get("Action"))
+		if query.Get("Signature") == "" {
+			t.Errorf("Expected request to be signed")
+		}
+
+		switch query.Get("Action") {
+		case "AddSmsTemplate":
+			if query.Get("TemplateType") != "0" || query.Get("TemplateName") != "登录验证码" {
+				t.Errorf("Unexpected params: %v", query)
+			}
+			return httpmock.NewJsonResponse(http.StatusOK, map[string]any{"Code": "OK", "TemplateCode": "SMS_152550005"})
+		case "QuerySmsTemplate":
+			return httpmock.NewJsonResponse(http.StatusOK, map[string]any{
+				"Code":           "OK",
+				"TemplateCode":   query.Get("TemplateCode"),
+				"TemplateName":   "登录验证码",
+				"TemplateStatus": 2,
+				"Reason":         "变量格式错误",
+			})
+		case "QuerySmsSign":
+			return httpmock.NewJsonResponse(http.StatusOK, map[string]any{"Code": "OK", "SignName": query.Get("SignName"), "SignStatus": 1})
+		case "DeleteSmsSign":
+			return httpmock.NewJsonResponse(http.StatusOK, map[string]any{"Code": "isv.SMS_SIGN_ILLEGAL", "Message": "签名不存在"})
+		default:
+			return httpmock.NewJsonResponse(http.StatusOK, map[string]any{"Code": "OK"})
+		}
+	})
+
+	g := gateway.NewAliyunGateway(map[string]any{
+		"access_key_id":     "mock-access-key-id",
+		"access_key_secret": "mock-access-key-secret",
+		"endpoint":          "https://dysmsapi.aliyuncs.com",
+	})
+	ctx := context.Background()
+
+	id, err := g.AddTemplate(ctx, &gateway.TemplateRequest{Name: "登录验证码", Content: "您的验证码为${code}", Type: gateway.TemplateVerification})
+	if err != nil || id != "SMS_152550005" {
+		t.Fatalf("Expected template code SMS_152550005, got: %s, %v", id, err)
+	}
+
+	status, err := g.QueryTemplateStatus(ctx, id)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if status.Status != gateway.ReviewRejected || status.Reason != "变量格式错误" {
+		t.Errorf("Unexpected template status: %+v", status)
+	}
+
+	if err := g.DeleteTemplate(ctx, id); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	sign, err := g.AddSign(ctx, &gateway.SignRequest{Name: "阿里云", Source: 1, Remark: "测试"})
+	if err != nil || sign != "阿里云" {
+		t.Fatalf("Expected sign name, got: %s, %v", sign, err)
+	}
+
+	signStatus, err := g.QuerySignStatus(ctx, sign)
+	if err != nil || signStatus.Status != gateway.ReviewApproved {
+		t.Errorf("Expected approved sign, got: %+v, %v", signStatus, err)
+	}
+
+	if err := g.DeleteSign(ctx, sign); gateway.ClassifyError(err) != gateway.CategoryTemplate {
+		t.Errorf("Expected template error, got: %v", err)
+	}
+
+	expected := []string{"AddSmsTemplate", "QuerySmsTemplate", "DeleteSmsTemplate", "AddSmsSign", "QuerySmsSign", "DeleteSmsSign"}
+	if len(actions) != len(expected) {
+		t.Fatalf("Expected actions %v, got: %v", expected, actions)
+	}
+	for i := range expected {
+		if actions[i] != expected[i] {
+			t.Errorf("Expected actions %v, got: %v", expected, actions)
+			break
+		}
+	}
+}
+
+// TestQcloudTemplateManager 测试腾讯云模板和签名管理
+func TestQcloudTemplateManager(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", "https://sms.tencentcloudapi.com",
+		func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			var params map[string]any
+			_ = json.Unmarshal(body, &params)
+
+			switch req.Header.Get("X-TC-Action") {
+			case "AddSmsTemplate":
+				if params["SmsType"] != float64(1) || params["International"] != float64(0) {
+					t.Errorf("Unexpected params: %v", params)
+				}
+				return httpmock.NewJsonResponse(http.StatusOK, map[string]any{
+					"Response": map[string]any{"AddTemplateStatus": map[string]any{"TemplateId": "1234"}},
+				})
+			case "DescribeSmsTemplateList":
+				// 国内模板中没有，在国际模板中找到
+				statusSet := []any{}
+				if params["International"] == float64(1) {
+					statusSet = append(statusSet, map[string]any{"TemplateId": 1234, "TemplateName": "营销", "StatusCode": 0, "International": 1})
+				}
+				return httpmock.NewJsonResponse(http.StatusOK, map[string]any{
+					"Response": map[string]any{"DescribeTemplateStatusSet": statusSet},
+				})
+			case "DeleteSmsTemplate":
+				if params["TemplateId"] != float64(1234) {
+					t.Errorf("Unexpected params: %v", params)
+				}
+				return httpmock.NewJsonResponse(http.StatusOK, map[string]any{
+					"Response": map[string]any{"DeleteTemplateStatus": map[string]any{"DeleteStatus": "return successfully!"}},
+				})
+			case "AddSmsSign":
+				if params["SignType"] != float64(1) || params["DocumentType"] != float64(3) {
+					t.Errorf("Unexpected params: %v", params)
+				}
+				return httpmock.NewJsonResponse(http.StatusOK, map[string]any{
+					"Response": map[string]any{"AddSignStatus": map[string]any{"SignId": 10000}},
+				})
+			case "DescribeSmsSignList":
+				return httpmock.NewJsonResponse(http.StatusOK, map[string]any{
+					"Response": map[string]any{"DescribeSignListStatusSet": []any{
+						map[string]any{"SignId": 10000, "SignName": "腾讯云", "StatusCode": -1, "ReviewReply": "证明材料不清晰"},
+					}},
+				})
+			default:
+				return httpmock.NewJsonResponse(http.StatusOK, map[string]any{
+					"Response": map[string]any{"Error": map[string]any{"Code": "UnauthorizedOperation.SmsSdkAppIdVerifyFail", "Message": "denied"}},
+				})
+			}
+		})
+
+	g := gateway.NewQcloudGateway(map[string]any{
+		"secret_id":  "mock-secret-id",
+		"secret_key": "mock-secret-key",
+	})
+	ctx := context.Background()
+
+	id, err := g.AddTemplate(ctx, &gateway.TemplateRequest{Name: "营销", Content: "新品上市", Type: gateway.TemplateMarketing})
+	if err != nil || id != "1234" {
+		t.Fatalf("Expected template id 1234, got: %s, %v", id, err)
+	}
+
+	status, err := g.QueryTemplateStatus(ctx, id)
+	if err != nil || status.Status != gateway.ReviewApproved || status.Name != "营销" {
+		t.Errorf("Unexpected template status: %+v, %v", status, err)
+	}
+
+	if err := g.DeleteTemplate(ctx, id); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	if _, err := g.QueryTemplateStatus(ctx, "not-a-number"); err == nil {
+		t.Error("Expected error for invalid template id")
+	}
+
+	sign, err := g.AddSign(ctx, &gateway.SignRequest{Name: "腾讯云", Source: 1, Extra: map[string]any{"DocumentType": 3, "SignPurpose": 0}})
+	if err != nil || sign != "10000" {
+		t.Fatalf("Expected sign id 10000, got: %s, %v", sign, err)
+	}
+
+	signStatus, err := g.QuerySignStatus(ctx, sign)
+	if err != nil || signStatus.Status != gateway.ReviewRejected || signStatus.Reason != "证明材料不清晰" {
+		t.Errorf("Unexpected sign status: %+v, %v", signStatus, err)
+	}
+
+	if err := g.DeleteSign(ctx, sign); gateway.ClassifyError(err) != gateway.CategoryAuth {
+		t.Errorf("Expected auth error, got: %v", err)
+	}
+}
@@ -63,3 +63,43 @@ func TestMessage(t *testing.T) {
 		t.Errorf("Expected type to be %s, got: %s", msgType, msg.GetType())
 	}
 }
+
+func TestMessageResolve(t *testing.T) {
+	msg := message.NewMessage().
+		SetContent("默认内容").
+		SetTemplateFunc(func(gateway string) string {
+			return gateway + "_template"
+		}).
+		AddResolver(func(gateway string, m *message.Message) {
+			if gateway == "qcloud" {
+				m.SetData(map[string]any{"0": "123456"})
+			}
+		})
+
+	if !msg.HasResolver() {
+		t.Fatal("Expected message to have resolvers")
+	}
+
+	aliyun := msg.Resolve("aliyun")
+	if aliyun.GetTemplate() != "aliyun_template" || aliyun.GetContent() != "默认内容" {
+		t.Errorf("Unexpected aliyun message: %s %s", aliyun.GetTemplate(), aliyun.GetContent())
+	}
+	if aliyun.HasResolver() {
+		t.Error("Expected resolved message to have no resolvers")
+	}
+
+	qcloud := msg.Resolve("qcloud")
+	if qcloud.GetTemplate() != "qcloud_template" || qcloud.GetData()["0"] != "123456" {
+		t.Errorf("Unexpected qcloud message: %s %v", qcloud.GetTemplate(), qcloud.GetData())
+	}
+
+	// 原消息不被修改
+	if msg.GetTemplate() != "" || len(msg.GetData()) != 0 {
+		t.Errorf("Expected original message unchanged, got: %s %v", msg.GetTemplate(), msg.GetData())
+	}
+
+	// 复制的消息保留解析函数
+	if !msg.Clone().HasResolver() {
+		t.Error("Expected cloned message to keep resolvers")
+	}
+}
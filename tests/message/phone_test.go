@@ -1,6 +1,7 @@
 package message_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/anhao/go-easy-sms/message"
@@ -68,3 +69,105 @@ func TestPhoneNumber(t *testing.T) {
 		t.Errorf("Expected InChineseMainland() to return true for phone with IDDCode 86")
 	}
 }
+
+func TestParsePhoneNumber(t *testing.T) {
+	tests := []struct {
+		raw           string
+		region        string
+		iddCode       int
+		number        string
+		countryRegion string
+		e164          string
+		national      string
+		international string
+	}{
+		{"+44 7911 123456", "", 44, "7911123456", "GB", "+447911123456", "07911 123456", "+44 7911 123456"},
+		{"0044 (0)7911-123456", "", 44, "7911123456", "GB", "+447911123456", "07911 123456", "+44 7911 123456"},
+		{"07911 123456", "gb", 44, "7911123456", "GB", "+447911123456", "07911 123456", "+44 7911 123456"},
+		{"138-0013-8000", "CN", 86, "13800138000", "CN", "+8613800138000", "138 0013 8000", "+86 138 0013 8000"},
+		{"8613800138000", "CN", 86, "13800138000", "CN", "+8613800138000", "138 0013 8000", "+86 138 0013 8000"},
+		{"+1 (201) 555-0123", "", 1, "2015550123", "US", "+12015550123", "201 555 0123", "+1 201 555 0123"},
+		{"+1 416 555 0123", "", 1, "4165550123", "CA", "+14165550123", "416 555 0123", "+1 416 555 0123"},
+		{"+7 701 123 4567", "", 7, "7011234567", "KZ", "+77011234567", "8701 123 4567", "+7 701 123 4567"},
+		{"+33 6 12 34 56 78", "", 33, "612345678", "FR", "+33612345678", "06 12 34 56 78", "+33 6 12 34 56 78"},
+		{"+852 5123 4567", "", 852, "51234567", "HK", "+85251234567", "5123 4567", "+852 5123 4567"},
+	}
+
+	for _, tt := range tests {
+		phone, err := message.ParsePhoneNumber(tt.raw, tt.region)
+		if err != nil {
+			t.Errorf("Expected %q to be parsed, got: %v", tt.raw, err)
+			continue
+		}
+		if phone.GetIDDCode() != tt.iddCode || phone.GetNumber() != tt.number || phone.Region != tt.countryRegion {
+			t.Errorf("Unexpected result for %q: %+v", tt.raw, phone)
+		}
+		if phone.E164() != tt.e164 {
+			t.Errorf("Expected E164 of %q to be %s, got: %s", tt.raw, tt.e164, phone.E164())
+		}
+		if phone.NationalFormat() != tt.national {
+			t.Errorf("Expected national format of %q to be %s, got: %s", tt.raw, tt.national, phone.NationalFormat())
+		}
+		if phone.InternationalFormat() != tt.international {
+			t.Errorf("Expected international format of %q to be %s, got: %s", tt.raw, tt.international, phone.InternationalFormat())
+		}
+		if err := phone.Validate(); err != nil {
+			t.Errorf("Expected parsed number %q to be valid, got: %v", tt.raw, err)
+		}
+	}
+}
+
+func TestParsePhoneNumberInvalid(t *testing.T) {
+	tests := []struct {
+		raw    string
+		region string
+	}{
+		{"", "CN"},
+		{"abc", "CN"},
+		{"+44 20 7946 0958", ""}, // 英国固定电话
+		{"+44 7911 12345", ""},   // 位数不足
+		{"+999 1234567", ""},     // 未知的国际区号
+		{"12800138000", "CN"},    // 无效号段
+		{"13800138000", ""},      // 缺少国际区号
+		{"13800138000", "XX"},    // 未知的国家
+		{"+86 138 0013 8000 ext", ""},
+	}
+
+	for _, tt := range tests {
+		_, err := message.ParsePhoneNumber(tt.raw, tt.region)
+		if !errors.Is(err, message.ErrInvalidPhoneNumber) {
+			t.Errorf("Expected %q to be rejected, got: %v", tt.raw, err)
+		}
+	}
+
+	if err := message.NewPhoneNumber("12345").Validate(); !errors.Is(err, message.ErrInvalidPhoneNumber) {
+		t.Errorf("Expected invalid number to fail validation, got: %v", err)
+	}
+	if err := message.NewPhoneNumber("13800138000").Validate(); err != nil {
+		t.Errorf("Expected number without IDD code to be validated as Chinese mainland, got: %v", err)
+	}
+}
+
+func TestPhoneNumberCarrier(t *testing.T) {
+	tests := []struct {
+		phone   *message.PhoneNumber
+		carrier message.Carrier
+	}{
+		{message.NewPhoneNumber("13800138000"), message.CarrierChinaMobile},
+		{message.NewPhoneNumber("18612345678", 86), message.CarrierChinaUnicom},
+		{message.NewPhoneNumber("18912345678"), message.CarrierChinaTelecom},
+		{message.NewPhoneNumber("13491234567"), message.CarrierChinaTelecom},
+		{message.NewPhoneNumber("19212345678"), message.CarrierChinaBroadnet},
+		{message.NewPhoneNumber("17012345678"), message.CarrierVirtual},
+		{message.NewPhoneNumber("16512345678"), message.CarrierVirtual},
+		{message.NewPhoneNumber("12012345678"), message.CarrierUnknown},
+		{message.NewPhoneNumber("1380013800"), message.CarrierUnknown},
+		{message.NewPhoneNumber("13800138000", 44), message.CarrierUnknown},
+	}
+
+	for _, tt := range tests {
+		if carrier := tt.phone.Carrier(); carrier != tt.carrier {
+			t.Errorf("Expected carrier of %s to be %s, got: %s", tt.phone, tt.carrier, carrier)
+		}
+	}
+}
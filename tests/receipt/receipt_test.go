@@ -0,0 +1,217 @@
+package receipt_test
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anhao/go-easy-sms/receipt"
+)
+
+// newFormRequest 创建表单请求
+func newFormRequest(target string, form url.Values) *http.Request {
+	req := httptest.NewRequest("POST", target, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestAliyunParser(t *testing.T) {
+	body := `[
+		{"phone_number":"13900000001","send_time":"2017-01-01 11:12:13","report_time":"2017-01-01 11:12:14","success":true,"err_code":"DELIVERED","err_msg":"用户接收成功","biz_id":"12345","out_id":"67890"},
+		{"phone_number":"13900000002","send_time":"2017-01-01 11:12:13","report_time":"2017-01-01 11:12:14","success":false,"err_code":"EXPIRED","err_msg":"短信超时","biz_id":"12346"}
+	]`
+
+	p, err := receipt.New("aliyun", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reports, err := p.Parse(httptest.NewRequest("POST", "/receipt", strings.NewReader(body)))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(reports) != 2 {
+		t.Fatalf("Expected 2 reports, got: %d", len(reports))
+	}
+
+	r := reports[0]
+	if r.MessageID != "12345" || r.Phone != "13900000001" || r.Status != receipt.StatusDelivered {
+		t.Errorf("Unexpected report: %+v", r)
+	}
+
+	expected := time.Date(2017, 1, 1, 11, 12, 14, 0, time.FixedZone("CST", 8*3600))
+	if !r.ReportTime.Equal(expected) {
+		t.Errorf("Expected report time %v, got: %v", expected, r.ReportTime)
+	}
+
+	if reports[1].Status != receipt.StatusExpired || reports[1].ErrorCode != "EXPIRED" {
+		t.Errorf("Expected expired report, got: %+v", reports[1])
+	}
+}
+
+func TestQcloudParser(t *testing.T) {
+	body := `[{"user_receive_time":"2015-10-17 08:03:04","nationcode":"86","mobile":"13900000001","report_status":"FAIL","errmsg":"MK:0001","description":"用户短信接收失败","sid":"xxxxxxx"}]`
+
+	reports, err := receipt.NewQcloudParser(nil).Parse(httptest.NewRequest("POST", "/receipt", strings.NewReader(body)))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	r := reports[0]
+	if r.MessageID != "xxxxxxx" || r.Status != receipt.StatusFailed || r.ErrorCode != "MK:0001" {
+		t.Errorf("Unexpected report: %+v", r)
+	}
+}
+
+func TestYunpianParser(t *testing.T) {
+	form := url.Values{}
+	form.Set("sms_status", `[{"sid":1234567890123456789,"uid":null,"user_receive_time":"2014-03-17 22:55:21","error_msg":"DELIVRD","mobile":"15205201314","report_status":"SUCCESS"}]`)
+
+	reports, err := receipt.NewYunpianParser(nil).Parse(newFormRequest("/receipt", form))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	r := reports[0]
+	if r.MessageID != "1234567890123456789" || r.Phone != "15205201314" || r.Status != receipt.StatusDelivered {
+		t.Errorf("Unexpected report: %+v", r)
+	}
+
+	// 云片要求应答 SUCCESS
+	w := httptest.NewRecorder()
+	receipt.Handler(receipt.NewYunpianParser(nil), func([]*receipt.DeliveryReport) {}).ServeHTTP(w, newFormRequest("/receipt", form))
+	if w.Body.String() != "SUCCESS" {
+		t.Errorf("Expected response SUCCESS, got: %s", w.Body.String())
+	}
+}
+
+func TestTwilioParser(t *testing.T) {
+	form := url.Values{}
+	form.Set("MessageSid", "SM1234567890")
+	form.Set("MessageStatus", "undelivered")
+	form.Set("To", "+14155552345")
+	form.Set("ErrorCode", "30003")
+
+	target := "https://example.com/receipt/twilio"
+	sign := func(form url.Values) string {
+		data := target + "ErrorCode" + form.Get("ErrorCode") + "MessageSid" + form.Get("MessageSid") + "MessageStatus" + form.Get("MessageStatus") + "To" + form.Get("To")
+		mac := hmac.New(sha1.New, []byte("mock-token"))
+		mac.Write([]byte(data))
+		return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	}
+
+	p := receipt.NewTwilioParser(map[string]any{"token": "mock-token"})
+
+	req := newFormRequest(target, form)
+	req.Header.Set("X-Twilio-Signature", sign(form))
+	reports, err := p.Parse(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(reports) != 1 || reports[0].Status != receipt.StatusFailed || reports[0].ErrorCode != "30003" {
+		t.Errorf("Unexpected reports: %+v", reports)
+	}
+
+	// 签名错误
+	req = newFormRequest(target, form)
+	req.Header.Set("X-Twilio-Signature", "invalid")
+	if _, err := p.Parse(req); !errors.Is(err, receipt.ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature, got: %v", err)
+	}
+
+	// 中间状态不生成报告
+	form.Set("MessageStatus", "sent")
+	req = newFormRequest(target, form)
+	req.Header.Set("X-Twilio-Signature", sign(form))
+	reports, err = p.Parse(req)
+	if err != nil || len(reports) != 0 {
+		t.Errorf("Expected no reports for sent status, got: %v, %v", reports, err)
+	}
+}
+
+func TestChuanglanParser(t *testing.T) {
+	query := "receiver=admin&pswd=12345&msgid=17041010383624511&reportTime=1704101038&mobile=15700000004&status=DELIVRD&statusDesc=短信发送成功"
+
+	p := receipt.NewChuanglanParser(map[string]any{
+		"receipt_receiver": "admin",
+		"receipt_password": "12345",
+	})
+
+	reports, err := p.Parse(httptest.NewRequest("GET", "/receipt?"+query, nil))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	r := reports[0]
+	if r.MessageID != "17041010383624511" || r.Status != receipt.StatusDelivered {
+		t.Errorf("Unexpected report: %+v", r)
+	}
+
+	if r.ReportTime.Year() != 2017 || r.ReportTime.Month() != 4 || r.ReportTime.Minute() != 38 {
+		t.Errorf("Unexpected report time: %v", r.ReportTime)
+	}
+
+	if _, err := p.Parse(httptest.NewRequest("GET", "/receipt?receiver=admin&pswd=wrong", nil)); !errors.Is(err, receipt.ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature, got: %v", err)
+	}
+}
+
+func TestSubmailParser(t *testing.T) {
+	sum := md5.Sum([]byte("mock-token" + "mock-key"))
+
+	form := url.Values{}
+	form.Set("events", "dropped")
+	form.Set("send_id", "093c0a7df143c087d6cba9cdf0cf3738")
+	form.Set("address", "13900000001")
+	form.Set("report", "UNDELIV")
+	form.Set("timestamp", "1415235448")
+	form.Set("token", "mock-token")
+	form.Set("signature", hex.EncodeToString(sum[:]))
+
+	p := receipt.NewSubmailParser(map[string]any{"subhook_key": "mock-key"})
+	reports, err := p.Parse(newFormRequest("/receipt", form))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	r := reports[0]
+	if r.MessageID != "093c0a7df143c087d6cba9cdf0cf3738" || r.Status != receipt.StatusFailed || r.ReportTime.Unix() != 1415235448 {
+		t.Errorf("Unexpected report: %+v", r)
+	}
+
+	form.Set("signature", "invalid")
+	if _, err := p.Parse(newFormRequest("/receipt", form)); !errors.Is(err, receipt.ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature, got: %v", err)
+	}
+}
+
+func TestVolcengineParser(t *testing.T) {
+	body := `{"MessageID":"21010711410808880001","PhoneNumber":"13900000001","ErrorCode":"DELIVRD","Description":"成功","SendTime":1610000000,"ReceiveTime":1610000005}`
+
+	reports, err := receipt.NewVolcengineParser(nil).Parse(httptest.NewRequest("POST", "/receipt", strings.NewReader(body)))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	r := reports[0]
+	if r.MessageID != "21010711410808880001" || r.Status != receipt.StatusDelivered || r.ReportTime.Unix() != 1610000005 {
+		t.Errorf("Unexpected report: %+v", r)
+	}
+}
+
+func TestUnsupportedGateway(t *testing.T) {
+	if _, err := receipt.New("smsbao", nil); err == nil {
+		t.Error("Expected error for gateway without delivery reports")
+	}
+}
@@ -0,0 +1,133 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/anhao/go-easy-sms/http"
+	"github.com/anhao/go-easy-sms/retry"
+)
+
+// permanentError 不可重试的业务错误
+type permanentError struct{}
+
+func (e *permanentError) Error() string   { return "invalid phone number" }
+func (e *permanentError) Retryable() bool { return false }
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain", errors.New("business error"), false},
+		{"network", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{"status 502", &http.StatusError{StatusCode: 502}, true},
+		{"rate limit", &http.StatusError{StatusCode: 429}, true},
+		{"permanent", &permanentError{}, false},
+		{"canceled", context.Canceled, false},
+	}
+
+	for _, tt := range tests {
+		if got := retry.IsRetryable(tt.err); got != tt.want {
+			t.Errorf("%s: expected IsRetryable to be %v, got: %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestPolicyDo(t *testing.T) {
+	p := &retry.Policy{
+		MaxAttempts: 3,
+		BaseBackoff: time.Millisecond,
+	}
+
+	// 可重试错误会重试至最大次数
+	attempts := 0
+	err := p.Do(context.Background(), func(attempt int) error {
+		attempts++
+		return &http.StatusError{StatusCode: 503}
+	})
+	if err == nil || attempts != 3 {
+		t.Errorf("Expected 3 attempts with error, got: %d attempts, err: %v", attempts, err)
+	}
+
+	// 成功后停止重试
+	attempts = 0
+	err = p.Do(context.Background(), func(attempt int) error {
+		attempts++
+		if attempt < 2 {
+			return &http.StatusError{StatusCode: 503}
+		}
+		return nil
+	})
+	if err != nil || attempts != 2 {
+		t.Errorf("Expected success after 2 attempts, got: %d attempts, err: %v", attempts, err)
+	}
+
+	// 不可重试错误不重试
+	attempts = 0
+	_ = p.Do(context.Background(), func(attempt int) error {
+		attempts++
+		return &permanentError{}
+	})
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt for permanent error, got: %d", attempts)
+	}
+
+	// nil 策略只执行一次
+	var nilPolicy *retry.Policy
+	attempts = 0
+	_ = nilPolicy.Do(context.Background(), func(attempt int) error {
+		attempts++
+		return &http.StatusError{StatusCode: 503}
+	})
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt for nil policy, got: %d", attempts)
+	}
+}
+
+func TestPolicyBackoff(t *testing.T) {
+	p := &retry.Policy{
+		BaseBackoff: 100 * time.Millisecond,
+		MaxBackoff:  300 * time.Millisecond,
+	}
+
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond}
+	for i, want := range expected {
+		if got := p.Backoff(i + 1); got != want {
+			t.Errorf("Expected backoff %d to be %v, got: %v", i+1, want, got)
+		}
+	}
+
+	// 抖动只会缩短退避时间
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		got := p.Backoff(1)
+		if got < 50*time.Millisecond || got > 100*time.Millisecond {
+			t.Fatalf("Expected jittered backoff within [50ms, 100ms], got: %v", got)
+		}
+	}
+}
+
+func TestFromConfig(t *testing.T) {
+	p := retry.FromConfig(map[string]any{
+		"max_attempts": 3,
+		"base_backoff": 0.2,
+		"max_backoff":  2,
+		"jitter":       0.1,
+	})
+	if p == nil {
+		t.Fatal("Expected policy, got nil")
+	}
+	if p.MaxAttempts != 3 || p.BaseBackoff != 200*time.Millisecond || p.MaxBackoff != 2*time.Second || p.Jitter != 0.1 {
+		t.Errorf("Unexpected policy: %+v", p)
+	}
+
+	if retry.FromConfig(nil) != nil {
+		t.Error("Expected nil policy for nil config")
+	}
+}
@@ -1,9 +1,14 @@
 package strategy_test
 
 import (
+	"errors"
 	"reflect"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/anhao/go-easy-sms/gateway"
+	"github.com/anhao/go-easy-sms/message"
 	"github.com/anhao/go-easy-sms/strategy"
 )
 
@@ -72,3 +77,427 @@ func TestRandomStrategy(t *testing.T) {
 		}
 	}
 }
+
+func TestRaceStrategy(t *testing.T) {
+	s := strategy.NewRaceStrategy(2)
+
+	// 测试默认按原始顺序
+	multiple := []string{"aliyun", "yunpian", "custom"}
+	result := s.Apply(multiple)
+	if !reflect.DeepEqual(result, multiple) {
+		t.Errorf("Expected %v, got: %v", multiple, result)
+	}
+
+	if s.Concurrency() != 2 {
+		t.Errorf("Expected concurrency 2, got: %d", s.Concurrency())
+	}
+
+	// 测试非法并发数
+	if strategy.NewRaceStrategy(0).Concurrency() != 1 {
+		t.Errorf("Expected concurrency to be at least 1")
+	}
+
+	// 测试实现 ConcurrentStrategy 接口
+	var _ strategy.ConcurrentStrategy = s
+}
+
+func TestWeightedRoundRobinStrategy(t *testing.T) {
+	s := strategy.NewWeightedRoundRobinStrategy(map[string]int{
+		"aliyun": 70,
+		"qcloud": 30,
+	})
+
+	gateways := []string{"qcloud", "aliyun", "custom"}
+
+	// 10 次调用中 aliyun 应首选 7 次，qcloud 首选 3 次
+	counts := map[string]int{}
+	for i := 0; i < 10; i++ {
+		result := s.Apply(gateways)
+		if len(result) != 3 {
+			t.Fatalf("Expected 3 gateways, got: %v", result)
+		}
+		if result[2] != "custom" {
+			t.Errorf("Expected unweighted gateway to be last, got: %v", result)
+		}
+		counts[result[0]]++
+	}
+
+	if counts["aliyun"] != 7 || counts["qcloud"] != 3 {
+		t.Errorf("Expected aliyun 7 / qcloud 3, got: %v", counts)
+	}
+
+	// 平滑轮询：权重 5/1/1 时的首选序列应交错分布
+	s = strategy.NewWeightedRoundRobinStrategy(map[string]int{"a": 5, "b": 1, "c": 1})
+	var sequence []string
+	for i := 0; i < 7; i++ {
+		sequence = append(sequence, s.Apply([]string{"a", "b", "c"})[0])
+	}
+	expected := []string{"a", "a", "b", "a", "c", "a", "a"}
+	if !reflect.DeepEqual(sequence, expected) {
+		t.Errorf("Expected %v, got: %v", expected, sequence)
+	}
+
+	// 没有权重时保持原始顺序
+	result := s.Apply([]string{"x", "y"})
+	if !reflect.DeepEqual(result, []string{"x", "y"}) {
+		t.Errorf("Expected [x y], got: %v", result)
+	}
+}
+
+func TestWeightedRoundRobinStrategyConcurrent(t *testing.T) {
+	s := strategy.NewWeightedRoundRobinStrategy(map[string]int{
+		"aliyun": 70,
+		"qcloud": 30,
+	})
+
+	var mu sync.Mutex
+	counts := map[string]int{}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				first := s.Apply([]string{"aliyun", "qcloud"})[0]
+				mu.Lock()
+				counts[first]++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if counts["aliyun"] != 700 || counts["qcloud"] != 300 {
+		t.Errorf("Expected aliyun 700 / qcloud 300, got: %v", counts)
+	}
+}
+
+func TestWeightedRandomStrategy(t *testing.T) {
+	s := strategy.NewWeightedRandomStrategy(map[string]int{
+		"aliyun": 70,
+		"qcloud": 30,
+		"zero":   0,
+	})
+
+	gateways := []string{"zero", "qcloud", "aliyun"}
+
+	counts := map[string]int{}
+	for i := 0; i < 2000; i++ {
+		result := s.Apply(gateways)
+		if len(result) != 3 {
+			t.Fatalf("Expected 3 gateways, got: %v", result)
+		}
+		if result[2] != "zero" {
+			t.Errorf("Expected zero-weight gateway to be last, got: %v", result)
+		}
+		counts[result[0]]++
+	}
+
+	// 首选比例应接近 70%
+	ratio := float64(counts["aliyun"]) / 2000
+	if ratio < 0.6 || ratio > 0.8 {
+		t.Errorf("Expected aliyun ratio around 0.7, got: %.2f", ratio)
+	}
+}
+
+func TestCircuitBreakerStrategy(t *testing.T) {
+	s := strategy.NewCircuitBreakerStrategy(strategy.CircuitBreakerOptions{
+		FailureThreshold: 3,
+		CoolDown:         50 * time.Millisecond,
+	})
+
+	gateways := []string{"aliyun", "yunpian"}
+	failure := errors.New("timeout")
+
+	// 未达到阈值时保持原顺序
+	s.Report("aliyun", failure)
+	s.Report("aliyun", failure)
+	if result := s.Apply(gateways); !reflect.DeepEqual(result, gateways) {
+		t.Errorf("Expected %v, got: %v", gateways, result)
+	}
+
+	// 连续失败达到阈值后熔断，移到末尾
+	s.Report("aliyun", failure)
+	if s.State("aliyun") != strategy.StateOpen {
+		t.Fatalf("Expected aliyun to be open, got: %s", s.State("aliyun"))
+	}
+	expected := []string{"yunpian", "aliyun"}
+	if result := s.Apply(gateways); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got: %v", expected, result)
+	}
+
+	// 冷却结束后半开，放行一次探测
+	time.Sleep(60 * time.Millisecond)
+	if result := s.Apply(gateways); !reflect.DeepEqual(result, gateways) {
+		t.Errorf("Expected %v, got: %v", gateways, result)
+	}
+	if s.State("aliyun") != strategy.StateHalfOpen {
+		t.Fatalf("Expected aliyun to be half-open, got: %s", s.State("aliyun"))
+	}
+
+	// 探测期间的其他请求仍视为熔断
+	if result := s.Apply(gateways); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got: %v", expected, result)
+	}
+
+	// 探测失败重新熔断
+	s.Report("aliyun", failure)
+	if s.State("aliyun") != strategy.StateOpen {
+		t.Fatalf("Expected aliyun to be open again, got: %s", s.State("aliyun"))
+	}
+
+	// 探测成功后恢复
+	time.Sleep(60 * time.Millisecond)
+	s.Apply(gateways)
+	s.Report("aliyun", nil)
+	if s.State("aliyun") != strategy.StateClosed {
+		t.Errorf("Expected aliyun to be closed, got: %s", s.State("aliyun"))
+	}
+}
+
+func TestCircuitBreakerStrategyFailureRate(t *testing.T) {
+	s := strategy.NewCircuitBreakerStrategy(strategy.CircuitBreakerOptions{
+		FailureThreshold: -1,
+		FailureRate:      0.5,
+		WindowSize:       10,
+		MinRequests:      4,
+		DropOpen:         true,
+	})
+
+	failure := errors.New("rejected")
+
+	// 交替成功失败，失败率达到 50% 时熔断
+	s.Report("aliyun", failure)
+	s.Report("aliyun", nil)
+	s.Report("aliyun", failure)
+	if s.State("aliyun") != strategy.StateClosed {
+		t.Fatalf("Expected aliyun to be closed before min requests, got: %s", s.State("aliyun"))
+	}
+	s.Report("aliyun", nil)
+	s.Report("aliyun", failure)
+	if s.State("aliyun") != strategy.StateOpen {
+		t.Fatalf("Expected aliyun to be open, got: %s", s.State("aliyun"))
+	}
+
+	// DropOpen 时移除熔断中的网关
+	if result := s.Apply([]string{"aliyun", "yunpian"}); !reflect.DeepEqual(result, []string{"yunpian"}) {
+		t.Errorf("Expected [yunpian], got: %v", result)
+	}
+
+	// 全部熔断时仍返回网关
+	if result := s.Apply([]string{"aliyun"}); !reflect.DeepEqual(result, []string{"aliyun"}) {
+		t.Errorf("Expected [aliyun], got: %v", result)
+	}
+}
+
+func TestCircuitBreakerStrategyBusinessErrors(t *testing.T) {
+	s := strategy.NewCircuitBreakerStrategy(strategy.CircuitBreakerOptions{FailureThreshold: 2})
+
+	// 号码无效、内容违规等业务错误不触发熔断
+	for _, category := range []gateway.ErrorCategory{
+		gateway.CategoryInvalidNumber,
+		gateway.CategorySensitiveContent,
+		gateway.CategoryTemplate,
+		gateway.CategoryInvalidNumber,
+	} {
+		s.Report("aliyun", gateway.NewSendError("aliyun", category, "", "rejected"))
+	}
+	if s.State("aliyun") != strategy.StateClosed {
+		t.Fatalf("Expected aliyun to stay closed, got: %s", s.State("aliyun"))
+	}
+
+	// 业务错误说明网关正常响应，连续失败重新计数
+	s.Report("aliyun", gateway.NewSendError("aliyun", gateway.CategoryServer, "", "internal error"))
+	s.Report("aliyun", gateway.NewSendError("aliyun", gateway.CategoryInvalidNumber, "", "invalid mobile"))
+	s.Report("aliyun", gateway.NewSendError("aliyun", gateway.CategoryServer, "", "internal error"))
+	if s.State("aliyun") != strategy.StateClosed {
+		t.Fatalf("Expected aliyun to stay closed, got: %s", s.State("aliyun"))
+	}
+
+	// 服务商系统错误和余额不足计为故障
+	s.Report("aliyun", gateway.NewSendError("aliyun", gateway.CategoryInsufficientBalance, "", "no balance"))
+	if s.State("aliyun") != strategy.StateOpen {
+		t.Fatalf("Expected aliyun to be open, got: %s", s.State("aliyun"))
+	}
+
+	// 自定义故障判断
+	custom := strategy.NewCircuitBreakerStrategy(strategy.CircuitBreakerOptions{
+		FailureThreshold: 1,
+		IsFailure:        func(err error) bool { return err != nil },
+	})
+	custom.Report("aliyun", gateway.NewSendError("aliyun", gateway.CategoryInvalidNumber, "", "invalid mobile"))
+	if custom.State("aliyun") != strategy.StateOpen {
+		t.Errorf("Expected aliyun to be open, got: %s", custom.State("aliyun"))
+	}
+}
+
+func TestCircuitBreakerStrategyConcurrency(t *testing.T) {
+	// 包装竞速策略时保留并发数
+	s := strategy.NewCircuitBreakerStrategy(strategy.CircuitBreakerOptions{FailureThreshold: 1, DropOpen: true}, strategy.NewRaceStrategy(2))
+	var cs strategy.ConcurrentStrategy = s
+	if cs.Concurrency() != 2 {
+		t.Errorf("Expected concurrency 2, got: %d", cs.Concurrency())
+	}
+
+	// 熔断的网关不参与竞速
+	s.Report("aliyun", errors.New("rejected"))
+	if result := s.Apply([]string{"aliyun", "yunpian", "custom"}); !reflect.DeepEqual(result, []string{"yunpian", "custom"}) {
+		t.Errorf("Expected [yunpian custom], got: %v", result)
+	}
+
+	// 基础策略不支持并发发送时返回 1
+	if c := strategy.NewCircuitBreakerStrategy(strategy.CircuitBreakerOptions{}).Concurrency(); c != 1 {
+		t.Errorf("Expected concurrency 1, got: %d", c)
+	}
+}
+
+func TestDemoteStrategy(t *testing.T) {
+	cb := strategy.NewCircuitBreakerStrategy(strategy.CircuitBreakerOptions{FailureThreshold: 1})
+	s := strategy.NewDemoteStrategy(cb)
+	gateways := []string{"smsbao", "aliyun", "qcloud"}
+
+	s.Demote("smsbao")
+	if !s.Demoted("smsbao") {
+		t.Error("Expected smsbao to be demoted")
+	}
+
+	expected := []string{"aliyun", "qcloud", "smsbao"}
+	if result := s.Apply(gateways); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got: %v", expected, result)
+	}
+
+	// 发送结果转发给基础策略
+	s.Report("aliyun", errors.New("failed"))
+	if cb.State("aliyun") != strategy.StateOpen {
+		t.Errorf("Expected report to be forwarded to base strategy")
+	}
+
+	s.Restore("smsbao")
+	expected = []string{"smsbao", "qcloud", "aliyun"}
+	if result := s.Apply(gateways); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got: %v", expected, result)
+	}
+
+	if s.Concurrency() != 1 {
+		t.Errorf("Expected concurrency 1, got: %d", s.Concurrency())
+	}
+}
+
+func TestCarrierStrategy(t *testing.T) {
+	s := strategy.NewCarrierStrategy(map[message.Carrier][]string{
+		message.CarrierChinaMobile:  {"yidongmasblack", "missing"},
+		message.CarrierChinaTelecom: {"qcloud", "aliyun"},
+	})
+	gateways := []string{"aliyun", "qcloud", "yidongmasblack"}
+
+	expected := []string{"yidongmasblack", "aliyun", "qcloud"}
+	if result := s.Route(gateways, message.NewPhoneNumber("13800138000"), nil); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got: %v", expected, result)
+	}
+
+	expected = []string{"qcloud", "aliyun", "yidongmasblack"}
+	if result := s.Route(gateways, message.NewPhoneNumber("18912345678"), nil); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got: %v", expected, result)
+	}
+
+	// 未配置的运营商和国际号码使用基础策略的顺序
+	for _, to := range []*message.PhoneNumber{message.NewPhoneNumber("18612345678"), message.NewPhoneNumber("13800138000", 44)} {
+		if result := s.Route(gateways, to, nil); !reflect.DeepEqual(result, gateways) {
+			t.Errorf("Expected %v for %s, got: %v", gateways, to, result)
+		}
+	}
+
+	if result := s.Apply(gateways); !reflect.DeepEqual(result, gateways) {
+		t.Errorf("Expected %v, got: %v", gateways, result)
+	}
+}
+
+func TestRuleStrategy(t *testing.T) {
+	s := strategy.NewRuleStrategy([]strategy.Rule{
+		{IDDCodes: []int{86}, Gateways: []string{"aliyun"}},
+		{Regions: []string{"hk", "MO"}, Gateways: []string{"aliyun_intl", "twilio"}},
+		{
+			Match: func(to *message.PhoneNumber, msg *message.Message) bool {
+				return msg != nil && msg.GetType() == message.VoiceMessage
+			},
+			Gateways: []string{"qcloud"},
+		},
+	}, []string{"twilio", "aliyun_intl"})
+	gateways := []string{"aliyun", "qcloud", "aliyun_intl", "twilio"}
+
+	tests := []struct {
+		to       *message.PhoneNumber
+		msg      *message.Message
+		expected []string
+	}{
+		{message.NewPhoneNumber("13800138000"), nil, []string{"aliyun"}},
+		{message.NewPhoneNumber("13800138000", 86), nil, []string{"aliyun"}},
+		{message.NewPhoneNumber("51234567", 852), nil, []string{"aliyun_intl", "twilio"}},
+		{message.NewPhoneNumber("7911123456", 44), message.NewMessage().SetType(message.VoiceMessage), []string{"qcloud"}},
+		{message.NewPhoneNumber("7911123456", 44), nil, []string{"twilio", "aliyun_intl"}},
+	}
+	for _, tt := range tests {
+		if result := s.Route(gateways, tt.to, tt.msg); !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("Expected %v for %s, got: %v", tt.expected, tt.to, result)
+		}
+	}
+
+	// 只选择可用的网关
+	expected := []string{"twilio"}
+	if result := s.Route([]string{"aliyun", "twilio"}, message.NewPhoneNumber("51234567", 852), nil); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got: %v", expected, result)
+	}
+
+	// 包装策略转发路由
+	demote := strategy.NewDemoteStrategy(s)
+	demote.Demote("twilio")
+	expected = []string{"aliyun_intl", "twilio"}
+	if result := demote.Route(gateways, message.NewPhoneNumber("7911123456", 44), nil); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got: %v", expected, result)
+	}
+
+	if !strategy.Routes(demote) || !strategy.Routes(strategy.NewRaceStrategy(2, s)) {
+		t.Error("Expected wrapped rule strategy to route by recipient")
+	}
+	if strategy.Routes(strategy.NewDemoteStrategy(strategy.NewRandomStrategy())) {
+		t.Error("Expected wrapped random strategy not to route by recipient")
+	}
+}
+
+func TestWrappedDemoteStrategy(t *testing.T) {
+	demote := strategy.NewDemoteStrategy()
+	rule := strategy.NewRuleStrategy(nil, nil, demote)
+	carrier := strategy.NewCarrierStrategy(nil, rule)
+	gateways := []string{"aliyun", "qcloud", "twilio"}
+
+	// 降级和还原转发给基础策略
+	var d strategy.Demoter = carrier
+	d.Demote("aliyun")
+	if !demote.Demoted("aliyun") || !carrier.Demoted("aliyun") {
+		t.Error("Expected demote to be forwarded to the base strategy")
+	}
+
+	expected := []string{"qcloud", "twilio", "aliyun"}
+	if result := carrier.Route(gateways, message.NewPhoneNumber("13800138000"), nil); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got: %v", expected, result)
+	}
+
+	rule.Restore("aliyun")
+	if demote.Demoted("aliyun") {
+		t.Error("Expected restore to be forwarded to the base strategy")
+	}
+
+	if !strategy.Demotes(carrier) || !strategy.Demotes(strategy.NewRaceStrategy(2, rule)) || !strategy.Demotes(demote) {
+		t.Error("Expected wrapped demote strategy to support demotion")
+	}
+	if strategy.Demotes(strategy.NewRuleStrategy(nil, nil)) || strategy.Demotes(strategy.NewOrderStrategy()) {
+		t.Error("Expected strategies without a demote strategy not to support demotion")
+	}
+
+	// 基础策略不支持降级时忽略
+	plain := strategy.NewCarrierStrategy(nil)
+	plain.Demote("aliyun")
+	if plain.Demoted("aliyun") {
+		t.Error("Expected demote to be ignored without a demote strategy")
+	}
+}